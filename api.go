@@ -0,0 +1,693 @@
+package main
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ========== REST API ==========
+// "vgc serve" exposes the collection as JSON over HTTP for phones and other tools of the home
+// network. Games, consoles and accessories can be listed (with the search syntax of query.go
+// and pagination), read, created, updated and deleted; so can the entries of the lookup
+// tables, filtered by name, as long as nothing uses an entry being deleted. Writes go
+// through the validation of the forms (validation.go). The document served at
+// /api/openapi.json is generated from the same definitions (see openapi.go).
+
+// Pagination of the list endpoints
+const (
+	apiDefaultLimit = 50
+	apiMaxLimit     = 500
+)
+
+// apiMaxBody limits the size of a request body
+const apiMaxBody = 1 << 20
+
+// apiError is an error returned to the client with its HTTP status
+type apiError struct {
+	Status  int
+	Message string
+}
+
+func (e *apiError) Error() string {
+	return e.Message
+}
+
+func badRequestf(format string, args ...any) error {
+	return &apiError{Status: http.StatusBadRequest, Message: fmt.Sprintf(format, args...)}
+}
+
+func notFoundf(format string, args ...any) error {
+	return &apiError{Status: http.StatusNotFound, Message: fmt.Sprintf(format, args...)}
+}
+
+func conflictf(format string, args ...any) error {
+	return &apiError{Status: http.StatusConflict, Message: fmt.Sprintf(format, args...)}
+}
+
+// apiPage is the body returned by the list endpoints
+type apiPage[T any] struct {
+	Items  []T `json:"items"`
+	Total  int `json:"total"` // Number of items matching the filters, all pages together
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
+}
+
+// ========== Resources ==========
+
+// apiResource describes the endpoints of an entity: /api/<Path> and /api/<Path>/{id}
+type apiResource[T any] struct {
+	Path     string // "games"
+	Label    string // Name of one item in error messages ("jeu")
+	Search   func(query string) ([]T, error)
+	Get      func(id int) (*T, error)
+	Save     func(item *T) (int, error) // ID 0 inserts, otherwise updates
+	Delete   func(id int) error
+	SetID    func(item *T, id int)
+	Validate func(item *T) error
+	// CheckReferences rejects IDs of lookup entries that don't exist, which the forms can't send
+	CheckReferences func(item *T) error
+}
+
+// apiLookup describes a lookup table: GET and POST /api/<Path>, PUT and DELETE /api/<Path>/{id}
+// The functions are built by nameLookup and ratingLookup, which know the type of the entries.
+type apiLookup struct {
+	Path   string
+	Schema any // Zero value of the entry type listed, for the OpenAPI document
+	Body   any // Zero value of the body of POST and PUT and of their response
+	// List returns the page of entries whose text contains q, accents and case ignored
+	List   func(q string, limit, offset int) (any, error)
+	Save   func(r *http.Request, id int) (any, error) // id 0 adds the entry of the body
+	Delete func(id int) error
+}
+
+// apiLookupEntry is the body of POST and PUT on a lookup table with a name, and their response
+type apiLookupEntry struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// nameLookup describes a lookup table whose entries are just a name
+// entry gives the ID and name of an entry of type T, as returned by list.
+func nameLookup[T any](store CollectionStore, path string, list func() ([]T, error), entry func(*T) apiLookupEntry) apiLookup {
+	entries := func() ([]apiLookupEntry, error) {
+		items, err := list()
+		if err != nil {
+			return nil, err
+		}
+		result := make([]apiLookupEntry, len(items))
+		for i := range items {
+			result[i] = entry(&items[i])
+		}
+		return result, nil
+	}
+
+	return apiLookup{
+		Path:   path,
+		Schema: *new(T),
+		Body:   apiLookupEntry{},
+		List: func(q string, limit, offset int) (any, error) {
+			items, err := list()
+			if err != nil {
+				return nil, err
+			}
+			items = slices.DeleteFunc(items, func(item T) bool { return !matchesLookupQuery(q, entry(&item).Name) })
+			return apiPageOf(items, limit, offset), nil
+		},
+		Save: func(r *http.Request, id int) (any, error) {
+			var body apiLookupEntry
+			if err := decodeAPIBody(r, &body); err != nil {
+				return nil, err
+			}
+			body.Name = strings.TrimSpace(body.Name)
+			if body.Name == "" {
+				return nil, badRequestf("nom requis")
+			}
+
+			existing, err := entries()
+			if err != nil {
+				return nil, err
+			}
+			if id != 0 && !slices.ContainsFunc(existing, func(e apiLookupEntry) bool { return e.ID == id }) {
+				return nil, notFoundf("entrée %d introuvable", id)
+			}
+			if slices.ContainsFunc(existing, func(e apiLookupEntry) bool { return e.ID != id && strings.EqualFold(e.Name, body.Name) }) {
+				return nil, conflictf("'%s' existe déjà", body.Name)
+			}
+
+			body.ID, err = store.SaveLookupEntry(path, id, body.Name)
+			return body, err
+		},
+		Delete: func(id int) error {
+			return deleteLookupEntry(store, path, id)
+		},
+	}
+}
+
+// ratingLookup describes the rating_systems table, whose entries have a region and a code
+func ratingLookup(store CollectionStore) apiLookup {
+	return apiLookup{
+		Path:   "rating_systems",
+		Schema: RatingSystem{},
+		Body:   RatingSystem{},
+		List: func(q string, limit, offset int) (any, error) {
+			ratings, err := store.GetRatingSystems()
+			if err != nil {
+				return nil, err
+			}
+			ratings = slices.DeleteFunc(ratings, func(r RatingSystem) bool {
+				text := r.Region + " " + r.Code
+				if r.Description != nil {
+					text += " " + *r.Description
+				}
+				return !matchesLookupQuery(q, text)
+			})
+			return apiPageOf(ratings, limit, offset), nil
+		},
+		Save: func(r *http.Request, id int) (any, error) {
+			var rating RatingSystem
+			if err := decodeAPIBody(r, &rating); err != nil {
+				return nil, err
+			}
+			rating.RatingID = id
+			rating.Region = strings.ToUpper(strings.TrimSpace(rating.Region))
+			rating.Code = strings.TrimSpace(rating.Code)
+			if !slices.Contains([]string{"JP", "US", "EU"}, rating.Region) {
+				return nil, badRequestf("région invalide '%s' (JP, US ou EU attendu)", rating.Region)
+			}
+			if rating.Code == "" {
+				return nil, badRequestf("code requis")
+			}
+
+			existing, err := store.GetRatingSystems()
+			if err != nil {
+				return nil, err
+			}
+			if id != 0 && !slices.ContainsFunc(existing, func(r RatingSystem) bool { return r.RatingID == id }) {
+				return nil, notFoundf("classification %d introuvable", id)
+			}
+			if slices.ContainsFunc(existing, func(r RatingSystem) bool {
+				return r.RatingID != id && r.Region == rating.Region && strings.EqualFold(r.Code, rating.Code)
+			}) {
+				return nil, conflictf("la classification %s %s existe déjà", rating.Region, rating.Code)
+			}
+
+			rating.RatingID, err = store.SaveRatingSystem(&rating)
+			return rating, err
+		},
+		Delete: func(id int) error {
+			return deleteLookupEntry(store, "rating_systems", id)
+		},
+	}
+}
+
+// deleteLookupEntry deletes an entry, as a 404 when it doesn't exist and a 409 while it is used
+func deleteLookupEntry(store CollectionStore, table string, id int) error {
+	err := store.DeleteLookupEntry(table, id)
+	switch {
+	case errors.Is(err, errNoRows):
+		return notFoundf("entrée %d introuvable", id)
+	case errors.Is(err, errLookupEntryInUse):
+		return conflictf("%v", err)
+	}
+	return err
+}
+
+// matchesLookupQuery tells whether the text of an entry contains q, accents and case ignored
+func matchesLookupQuery(q, text string) bool {
+	return strings.Contains(foldSearchText(text), foldSearchText(strings.TrimSpace(q)))
+}
+
+func gameResource(store CollectionStore) apiResource[Game] {
+	return apiResource[Game]{
+		Path:     "games",
+		Label:    "jeu",
+		Search:   store.SearchGames,
+		Get:      store.GetGameByID,
+		Save:     store.SaveGame,
+		Delete:   store.DeleteGame,
+		SetID:    func(g *Game, id int) { g.GameID = id },
+		Validate: validateGame,
+		CheckReferences: func(g *Game) error {
+			return checkGameReferences(store, g)
+		},
+	}
+}
+
+func consoleResource(store CollectionStore) apiResource[Console] {
+	return apiResource[Console]{
+		Path:     "consoles",
+		Label:    "console",
+		Search:   store.SearchConsoles,
+		Get:      store.GetConsoleByID,
+		Save:     store.SaveConsole,
+		Delete:   store.DeleteConsole,
+		SetID:    func(c *Console, id int) { c.ConsoleID = id },
+		Validate: validateConsole,
+		CheckReferences: func(c *Console) error {
+			return checkConsoleReferences(store, c)
+		},
+	}
+}
+
+func accessoryResource(store CollectionStore) apiResource[Accessory] {
+	return apiResource[Accessory]{
+		Path:     "accessories",
+		Label:    "accessoire",
+		Search:   store.SearchAccessories,
+		Get:      store.GetAccessoryByID,
+		Save:     store.SaveAccessory,
+		Delete:   store.DeleteAccessory,
+		SetID:    func(a *Accessory, id int) { a.AccessoryID = id },
+		Validate: validateAccessory,
+		CheckReferences: func(a *Accessory) error {
+			return checkAccessoryReferences(store, a)
+		},
+	}
+}
+
+// apiLookups lists the lookup tables in the order of the OpenAPI document
+func apiLookups(store CollectionStore) []apiLookup {
+	return []apiLookup{
+		nameLookup(store, "genres", store.GetGenres, func(g *Genre) apiLookupEntry { return apiLookupEntry{g.GenreID, g.Name} }),
+		nameLookup(store, "developers", store.GetDevelopers, func(d *Developer) apiLookupEntry { return apiLookupEntry{d.DeveloperID, d.Name} }),
+		nameLookup(store, "publishers", store.GetPublishers, func(p *Publisher) apiLookupEntry { return apiLookupEntry{p.PublisherID, p.Name} }),
+		nameLookup(store, "composers", store.GetComposers, func(c *Composer) apiLookupEntry { return apiLookupEntry{c.ComposerID, c.Name} }),
+		nameLookup(store, "producers", store.GetProducers, func(p *Producer) apiLookupEntry { return apiLookupEntry{p.ProducerID, p.Name} }),
+		nameLookup(store, "manufacturers", store.GetManufacturers, func(m *Manufacturer) apiLookupEntry { return apiLookupEntry{m.ManufacturerID, m.Name} }),
+		nameLookup(store, "console_types", store.GetConsoleTypes, func(t *ConsoleType) apiLookupEntry { return apiLookupEntry{t.TypeID, t.Name} }),
+		nameLookup(store, "accessory_types", store.GetAccessoryTypes, func(t *AccessoryType) apiLookupEntry { return apiLookupEntry{t.TypeID, t.Name} }),
+		ratingLookup(store),
+	}
+}
+
+// ========== Server ==========
+
+// newAPIHandler routes the endpoints of the API
+// An empty token leaves the API open; otherwise requests need "Authorization: Bearer <token>".
+func newAPIHandler(store CollectionStore, token string) http.Handler {
+	mux := http.NewServeMux()
+
+	handleResource(mux, gameResource(store))
+	handleResource(mux, consoleResource(store))
+	handleResource(mux, accessoryResource(store))
+
+	for _, lookup := range apiLookups(store) {
+		handleLookup(mux, lookup)
+	}
+
+	document := openAPIDocument(store)
+	mux.Handle("GET /api/openapi.json", apiHandler(func(r *http.Request) (int, any, error) {
+		return http.StatusOK, document, nil
+	}))
+	mux.Handle("/", apiHandler(func(r *http.Request) (int, any, error) {
+		return 0, nil, notFoundf("aucune route pour %s %s", r.Method, r.URL.Path)
+	}))
+
+	if token == "" {
+		return mux
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		given, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !found || subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+			writeAPIError(w, &apiError{Status: http.StatusUnauthorized, Message: "jeton d'accès manquant ou invalide"})
+			return
+		}
+		mux.ServeHTTP(w, r)
+	})
+}
+
+// handleResource registers the list, read, create, update and delete endpoints of an entity
+func handleResource[T any](mux *http.ServeMux, res apiResource[T]) {
+	base := "/api/" + res.Path
+
+	mux.Handle("GET "+base, apiHandler(func(r *http.Request) (int, any, error) {
+		page, err := listAPIResource(res, r)
+		return http.StatusOK, page, err
+	}))
+
+	mux.Handle("GET "+base+"/{id}", apiHandler(func(r *http.Request) (int, any, error) {
+		id, err := pathID(r)
+		if err != nil {
+			return 0, nil, err
+		}
+		item, err := getAPIResource(res, id)
+		return http.StatusOK, item, err
+	}))
+
+	mux.Handle("POST "+base, apiHandler(func(r *http.Request) (int, any, error) {
+		item, err := saveAPIResource(res, r, 0)
+		return http.StatusCreated, item, err
+	}))
+
+	mux.Handle("PUT "+base+"/{id}", apiHandler(func(r *http.Request) (int, any, error) {
+		id, err := pathID(r)
+		if err != nil {
+			return 0, nil, err
+		}
+		if _, err := getAPIResource(res, id); err != nil {
+			return 0, nil, err
+		}
+		item, err := saveAPIResource(res, r, id)
+		return http.StatusOK, item, err
+	}))
+
+	mux.Handle("DELETE "+base+"/{id}", apiHandler(func(r *http.Request) (int, any, error) {
+		id, err := pathID(r)
+		if err != nil {
+			return 0, nil, err
+		}
+		if _, err := getAPIResource(res, id); err != nil {
+			return 0, nil, err
+		}
+		return http.StatusNoContent, nil, res.Delete(id)
+	}))
+}
+
+// handleLookup registers the list, create, update and delete endpoints of a lookup table
+func handleLookup(mux *http.ServeMux, lookup apiLookup) {
+	base := "/api/" + lookup.Path
+
+	mux.Handle("GET "+base, apiHandler(func(r *http.Request) (int, any, error) {
+		limit, offset, err := pageParams(r)
+		if err != nil {
+			return 0, nil, err
+		}
+		page, err := lookup.List(r.URL.Query().Get("q"), limit, offset)
+		return http.StatusOK, page, err
+	}))
+
+	mux.Handle("POST "+base, apiHandler(func(r *http.Request) (int, any, error) {
+		entry, err := lookup.Save(r, 0)
+		return http.StatusCreated, entry, err
+	}))
+
+	mux.Handle("PUT "+base+"/{id}", apiHandler(func(r *http.Request) (int, any, error) {
+		id, err := pathID(r)
+		if err != nil {
+			return 0, nil, err
+		}
+		entry, err := lookup.Save(r, id)
+		return http.StatusOK, entry, err
+	}))
+
+	mux.Handle("DELETE "+base+"/{id}", apiHandler(func(r *http.Request) (int, any, error) {
+		id, err := pathID(r)
+		if err != nil {
+			return 0, nil, err
+		}
+		return http.StatusNoContent, nil, lookup.Delete(id)
+	}))
+}
+
+// listAPIResource runs the search given by the query string and returns the requested page
+// q takes the search syntax, owned=true|false is a shortcut for owned:yes|no, and
+// limit/offset select the page.
+func listAPIResource[T any](res apiResource[T], r *http.Request) (*apiPage[T], error) {
+	params := r.URL.Query()
+	query := params.Get("q")
+	if owned := params.Get("owned"); owned != "" {
+		value, ok := parseQueryBool(owned)
+		if !ok {
+			return nil, badRequestf("owned: valeur oui/non invalide '%s'", owned)
+		}
+		query = fmt.Sprintf("owned:%t %s", value, query)
+	}
+
+	limit, offset, err := pageParams(r)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := res.Search(query)
+	var queryErr *queryError
+	if errors.As(err, &queryErr) {
+		return nil, badRequestf("q: %s", queryErr.Message)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return apiPageOf(items, limit, offset), nil
+}
+
+// pageParams reads the limit and offset parameters of a list
+func pageParams(r *http.Request) (limit, offset int, err error) {
+	params := r.URL.Query()
+	limit, err = queryInt(params.Get("limit"), apiDefaultLimit)
+	if err != nil || limit < 1 || limit > apiMaxLimit {
+		return 0, 0, badRequestf("limit invalide (1 à %d attendu)", apiMaxLimit)
+	}
+	offset, err = queryInt(params.Get("offset"), 0)
+	if err != nil || offset < 0 {
+		return 0, 0, badRequestf("offset invalide")
+	}
+	return limit, offset, nil
+}
+
+// apiPageOf returns the page of items starting at offset
+func apiPageOf[T any](items []T, limit, offset int) *apiPage[T] {
+	page := &apiPage[T]{Items: []T{}, Total: len(items), Limit: limit, Offset: offset}
+	if offset < len(items) {
+		page.Items = items[offset:min(offset+limit, len(items))]
+	}
+	return page
+}
+
+// getAPIResource reads one item, as a 404 when it doesn't exist
+func getAPIResource[T any](res apiResource[T], id int) (*T, error) {
+	item, err := res.Get(id)
+	if errors.Is(err, errNoRows) {
+		return nil, notFoundf("%s %d introuvable", res.Label, id)
+	}
+	return item, err
+}
+
+// saveAPIResource validates the item in the body, saves it under id (0 creates it) and returns it as stored
+func saveAPIResource[T any](res apiResource[T], r *http.Request, id int) (*T, error) {
+	var item T
+	if err := decodeAPIBody(r, &item); err != nil {
+		return nil, err
+	}
+	res.SetID(&item, id)
+
+	if err := res.Validate(&item); err != nil {
+		return nil, badRequestf("%v", err)
+	}
+	if err := res.CheckReferences(&item); err != nil {
+		return nil, err
+	}
+
+	id, err := res.Save(&item)
+	if err != nil {
+		return nil, err
+	}
+	return res.Get(id)
+}
+
+// ========== References ==========
+
+// checkGameReferences rejects a game whose platform, genre, ratings or credits don't exist
+func checkGameReferences(store CollectionStore, game *Game) error {
+	consoles, err := store.GetConsoles()
+	if err != nil {
+		return err
+	}
+	genres, err := store.GetGenres()
+	if err != nil {
+		return err
+	}
+	ratings, err := store.GetRatingSystems()
+	if err != nil {
+		return err
+	}
+	developers, err := store.GetDevelopers()
+	if err != nil {
+		return err
+	}
+	publishers, err := store.GetPublishers()
+	if err != nil {
+		return err
+	}
+	composers, err := store.GetComposers()
+	if err != nil {
+		return err
+	}
+	producers, err := store.GetProducers()
+	if err != nil {
+		return err
+	}
+
+	// The rating of each region must come from the system of that region
+	ratingRegions := make(map[int]string)
+	for _, r := range ratings {
+		ratingRegions[r.RatingID] = r.Region
+	}
+	for _, rating := range []struct {
+		id     *int
+		region string
+	}{{game.JPRatingID, "JP"}, {game.USRatingID, "US"}, {game.EURatingID, "EU"}} {
+		if rating.id != nil && ratingRegions[*rating.id] != rating.region {
+			return badRequestf("classification %d inconnue pour la région %s", *rating.id, rating.region)
+		}
+	}
+
+	return errors.Join(
+		checkReferenceIDs("plateforme", optionalIDs(game.ConsoleID), consoles, func(c *Console) int { return c.ConsoleID }),
+		checkReferenceIDs("genre", optionalIDs(game.GenreID), genres, func(g *Genre) int { return g.GenreID }),
+		checkReferenceIDs("développeur", game.DeveloperIDs, developers, func(d *Developer) int { return d.DeveloperID }),
+		checkReferenceIDs("distributeur", game.PublisherIDs, publishers, func(p *Publisher) int { return p.PublisherID }),
+		checkReferenceIDs("compositeur", game.ComposerIDs, composers, func(c *Composer) int { return c.ComposerID }),
+		checkReferenceIDs("producteur", game.ProducerIDs, producers, func(p *Producer) int { return p.ProducerID }),
+	)
+}
+
+// checkConsoleReferences rejects a console whose type or manufacturer doesn't exist
+func checkConsoleReferences(store CollectionStore, console *Console) error {
+	types, err := store.GetConsoleTypes()
+	if err != nil {
+		return err
+	}
+	manufacturers, err := store.GetManufacturers()
+	if err != nil {
+		return err
+	}
+	return errors.Join(
+		checkReferenceIDs("type", optionalIDs(console.TypeID), types, func(t *ConsoleType) int { return t.TypeID }),
+		checkReferenceIDs("fabricant", optionalIDs(console.ManufacturerID), manufacturers, func(m *Manufacturer) int { return m.ManufacturerID }),
+	)
+}
+
+// checkAccessoryReferences rejects an accessory whose type, manufacturer or consoles don't exist
+func checkAccessoryReferences(store CollectionStore, accessory *Accessory) error {
+	types, err := store.GetAccessoryTypes()
+	if err != nil {
+		return err
+	}
+	manufacturers, err := store.GetManufacturers()
+	if err != nil {
+		return err
+	}
+	consoles, err := store.GetConsoles()
+	if err != nil {
+		return err
+	}
+	return errors.Join(
+		checkReferenceIDs("type", optionalIDs(accessory.TypeID), types, func(t *AccessoryType) int { return t.TypeID }),
+		checkReferenceIDs("fabricant", optionalIDs(accessory.ManufacturerID), manufacturers, func(m *Manufacturer) int { return m.ManufacturerID }),
+		checkReferenceIDs("plateforme", accessory.ConsoleIDs, consoles, func(c *Console) int { return c.ConsoleID }),
+	)
+}
+
+// checkReferenceIDs returns a 400 error for the first ID absent from entries, nil when all exist
+func checkReferenceIDs[T any](label string, ids []int, entries []T, entryID func(*T) int) error {
+	known := make(map[int]bool, len(entries))
+	for i := range entries {
+		known[entryID(&entries[i])] = true
+	}
+	for _, id := range ids {
+		if !known[id] {
+			return badRequestf("%s %d inconnu(e)", label, id)
+		}
+	}
+	return nil
+}
+
+func optionalIDs(id *int) []int {
+	if id == nil {
+		return nil
+	}
+	return []int{*id}
+}
+
+// ========== Requests and responses ==========
+
+// apiHandler adapts a function returning a status, a body and an error to an http.Handler
+type apiHandler func(r *http.Request) (int, any, error)
+
+func (h apiHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	status, body, err := h(r)
+	if err != nil {
+		writeAPIError(w, err)
+		return
+	}
+	if status == http.StatusNoContent {
+		w.WriteHeader(status)
+		return
+	}
+	writeAPIJSON(w, status, body)
+}
+
+// writeAPIError sends an error as {"error": "..."}; errors other than apiError are logged and sent as 500
+func writeAPIError(w http.ResponseWriter, err error) {
+	var apiErr *apiError
+	if !errors.As(err, &apiErr) {
+		log.Println("API error:", err)
+		apiErr = &apiError{Status: http.StatusInternalServerError, Message: err.Error()}
+	}
+	writeAPIJSON(w, apiErr.Status, map[string]string{"error": apiErr.Message})
+}
+
+func writeAPIJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(body); err != nil {
+		log.Println("API response:", err)
+	}
+}
+
+// decodeAPIBody reads a JSON body into v, refusing unknown fields so typos don't go unnoticed
+func decodeAPIBody(r *http.Request, v any) error {
+	decoder := json.NewDecoder(io.LimitReader(r.Body, apiMaxBody))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		return badRequestf("JSON invalide: %v", err)
+	}
+	return nil
+}
+
+// pathID reads the {id} segment of the URL
+func pathID(r *http.Request) (int, error) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || id < 1 {
+		return 0, notFoundf("identifiant invalide '%s'", r.PathValue("id"))
+	}
+	return id, nil
+}
+
+// queryInt reads an integer parameter, fallback when absent
+func queryInt(text string, fallback int) (int, error) {
+	if text == "" {
+		return fallback, nil
+	}
+	return strconv.Atoi(text)
+}
+
+// logAPIRequests logs each request with its status and duration
+func logAPIRequests(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(recorder, r)
+		log.Printf("%s %s %d %s", r.Method, r.URL.RequestURI(), recorder.status, time.Since(start).Round(time.Millisecond))
+	})
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
@@ -0,0 +1,720 @@
+package main
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ========== BACKUP ==========
+// A backup is a zip archive holding every VGC table and the pictures they use:
+//
+//	manifest.json        format and schema versions, date and number of rows per table
+//	tables/<table>.json  {"columns": [...], "rows": [[...], ...]}
+//	media/<hash><ext>    original pictures (thumbnails are regenerated on demand)
+//
+// Values don't depend on the driver (dates as YYYY-MM-DD, booleans as true/false), so a
+// PostgreSQL backup restores into SQLite and the reverse. Restoring rebuilds the schema at
+// the version of the archive, loads its rows then applies the newer migrations: backups made
+// before an upgrade stay usable, those of a newer build are refused.
+//
+// Backups are made from the File menu or "vgc backup", every BACKUP_INTERVAL while the app
+// runs and when it closes if BACKUP_ON_EXIT is set; only the newest BACKUP_KEEP are kept.
+
+// backupFormatVersion is the version of the archive layout, independent of the schema version
+const backupFormatVersion = 1
+
+// backupTables lists the saved tables, parents before children so rows can be inserted in
+// this order and tables dropped in the reverse one. A table added by a migration goes here.
+var backupTables = []string{
+	// Lookup tables
+	"genres", "developers", "composers", "publishers", "producers",
+	"manufacturers", "console_types", "accessory_types", "rating_systems",
+	// Main entities
+	"consoles", "games", "accessories",
+	// Junction tables
+	"game_developers", "game_composers", "game_publishers", "game_producers", "accessory_consoles",
+	// Added by later migrations
+	"images", "saved_searches", "market_values", "game_copies",
+	"completeness_components", "copy_components", "loans",
+}
+
+// BackupManifest describes the content of a backup archive
+type BackupManifest struct {
+	Format        int            `json:"format"`
+	SchemaVersion int            `json:"schema_version"`
+	CreatedAt     time.Time      `json:"created_at"`
+	Driver        string         `json:"driver"` // Backend the backup was made from
+	Tables        map[string]int `json:"tables"` // Number of rows of each table
+	Images        int            `json:"images"` // Number of picture files
+}
+
+// backupTable is the content of a table in the archive
+type backupTable struct {
+	Columns []string `json:"columns"`
+	Rows    [][]any  `json:"rows"`
+}
+
+const (
+	backupManifestFile = "manifest.json"
+	backupTablesDir    = "tables/"
+	backupMediaDir     = "media/"
+)
+
+// readBackupManifest reads and checks the manifest of an archive
+func readBackupManifest(archive *zip.Reader) (*BackupManifest, error) {
+	var manifest BackupManifest
+	if err := readBackupJSON(archive, backupManifestFile, &manifest); err != nil {
+		return nil, fmt.Errorf("ce fichier n'est pas une sauvegarde VGC: %w", err)
+	}
+	if manifest.Format != backupFormatVersion {
+		return nil, fmt.Errorf("format de sauvegarde %d non pris en charge (version %d attendue)", manifest.Format, backupFormatVersion)
+	}
+	if manifest.SchemaVersion <= 0 {
+		return nil, fmt.Errorf("version de schéma absente de la sauvegarde")
+	}
+	for table := range manifest.Tables {
+		if !slices.Contains(backupTables, table) {
+			return nil, fmt.Errorf("table '%s' inconnue dans la sauvegarde", table)
+		}
+	}
+	return &manifest, nil
+}
+
+// readBackupJSON decodes a file of the archive, keeping numbers exact
+func readBackupJSON(archive *zip.Reader, name string, v any) error {
+	f, err := archive.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	decoder := json.NewDecoder(f)
+	decoder.UseNumber()
+	if err := decoder.Decode(v); err != nil {
+		return fmt.Errorf("%s illisible: %w", name, err)
+	}
+	return nil
+}
+
+// createBackupEntry adds a file dated from the backup to the archive
+// Pictures are stored as is, they are already compressed.
+func createBackupEntry(archive *zip.Writer, name string, modified time.Time) (io.Writer, error) {
+	method := zip.Deflate
+	if strings.HasPrefix(name, backupMediaDir) {
+		method = zip.Store
+	}
+	return archive.CreateHeader(&zip.FileHeader{Name: name, Method: method, Modified: modified})
+}
+
+// writeBackupJSON adds a JSON file to the archive
+func writeBackupJSON(archive *zip.Writer, name string, modified time.Time, v any) error {
+	f, err := createBackupEntry(archive, name, modified)
+	if err != nil {
+		return err
+	}
+	return json.NewEncoder(f).Encode(v)
+}
+
+// backupSummary describes the content of a backup, e.g. "12 jeu(x), 3 console(s)..."
+func backupSummary(m *BackupManifest) string {
+	return fmt.Sprintf("%d jeu(x), %d console(s), %d accessoire(s), %d image(s)",
+		m.Tables["games"], m.Tables["consoles"], m.Tables["accessories"], m.Images)
+}
+
+// ========== Tables ==========
+
+// columnKind is the type of a column, as far as the archive is concerned
+type columnKind int
+
+const (
+	kindText columnKind = iota
+	kindInteger
+	kindNumber
+	kindBool
+	kindDate
+	kindTimestamp
+)
+
+// Layouts of the dates and timestamps in the archive (the latter in UTC, like CURRENT_TIMESTAMP in SQLite)
+const (
+	backupDateLayout      = "2006-01-02"
+	backupTimestampLayout = "2006-01-02 15:04:05"
+)
+
+// columnKindOf maps a declared SQL type ("NUMERIC(10, 2)", "timestamp with time zone"...) to its kind
+func columnKindOf(declared string) columnKind {
+	declared = strings.ToLower(declared)
+	switch {
+	case strings.Contains(declared, "timestamp"), strings.Contains(declared, "datetime"):
+		return kindTimestamp
+	case strings.Contains(declared, "date"):
+		return kindDate
+	case strings.Contains(declared, "bool"):
+		return kindBool
+	case strings.Contains(declared, "int"):
+		return kindInteger
+	case strings.Contains(declared, "numeric"), strings.Contains(declared, "decimal"),
+		strings.Contains(declared, "real"), strings.Contains(declared, "double"), strings.Contains(declared, "float"):
+		return kindNumber
+	default:
+		return kindText
+	}
+}
+
+// scanTarget returns where to scan a value of this kind (NULL leaves the pointer nil)
+func (k columnKind) scanTarget() any {
+	switch k {
+	case kindInteger:
+		return new(*int64)
+	case kindNumber:
+		return new(*float64)
+	case kindBool:
+		return new(*bool)
+	case kindDate, kindTimestamp:
+		return new(*time.Time)
+	default:
+		return new(*string)
+	}
+}
+
+// archiveValue converts a scanned value to what the archive stores
+func (k columnKind) archiveValue(target any) any {
+	switch v := target.(type) {
+	case **int64:
+		if *v != nil {
+			return **v
+		}
+	case **float64:
+		if *v != nil {
+			return **v
+		}
+	case **bool:
+		if *v != nil {
+			return **v
+		}
+	case **time.Time:
+		if *v != nil {
+			if k == kindDate {
+				return (*v).Format(backupDateLayout)
+			}
+			return (*v).UTC().Format(backupTimestampLayout)
+		}
+	case **string:
+		if *v != nil {
+			return **v
+		}
+	}
+	return nil
+}
+
+// databaseValue converts a value of the archive for a column of this kind
+// Values are decoded with UseNumber, or come straight from archiveValue (undo snapshots).
+func (k columnKind) databaseValue(value any) (any, error) {
+	if value == nil {
+		return nil, nil
+	}
+	switch k {
+	case kindInteger:
+		switch v := value.(type) {
+		case json.Number:
+			return v.Int64()
+		case int64:
+			return v, nil
+		}
+	case kindNumber:
+		switch v := value.(type) {
+		case json.Number:
+			return v.Float64()
+		case float64:
+			return v, nil
+		}
+	case kindBool:
+		switch v := value.(type) {
+		case bool:
+			return v, nil
+		case json.Number:
+			return v.String() != "0", nil
+		}
+	case kindDate, kindTimestamp:
+		if s, ok := value.(string); ok {
+			for _, layout := range []string{backupDateLayout, backupTimestampLayout, time.RFC3339Nano} {
+				if t, err := time.Parse(layout, s); err == nil {
+					return t, nil
+				}
+			}
+			return nil, fmt.Errorf("date '%s' invalide", s)
+		}
+	default:
+		switch v := value.(type) {
+		case string:
+			return v, nil
+		case json.Number:
+			return v.String(), nil
+		case bool:
+			return strconv.FormatBool(v), nil
+		}
+	}
+	return nil, fmt.Errorf("valeur %v inattendue", value)
+}
+
+// tableColumns returns the columns of a table with their kind (none if the table doesn't exist)
+func tableColumns(ctx context.Context, q querier, d dialect, table string) ([]string, map[string]columnKind, error) {
+	r, err := q.Query(ctx, d.tableColumns, table)
+	if err != nil {
+		return nil, nil, fmt.Errorf("lecture des colonnes de %s impossible: %w", table, err)
+	}
+	defer r.Close()
+
+	var names []string
+	kinds := make(map[string]columnKind)
+	for r.Next() {
+		var name, declared string
+		if err := r.Scan(&name, &declared); err != nil {
+			return nil, nil, err
+		}
+		names = append(names, name)
+		kinds[name] = columnKindOf(declared)
+	}
+	return names, kinds, r.Err()
+}
+
+// dumpTable reads the rows of a table matching an optional WHERE condition
+func dumpTable(ctx context.Context, q querier, d dialect, table string, where string, args ...any) (*backupTable, error) {
+	columns, kinds, err := tableColumns(ctx, q, d, table)
+	if err != nil {
+		return nil, err
+	}
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("table %s introuvable", table)
+	}
+
+	if where != "" {
+		where = " WHERE " + where
+	}
+	// Sorted on every column, so two dumps of the same rows are identical
+	order := make([]string, len(columns))
+	for i := range order {
+		order[i] = strconv.Itoa(i + 1)
+	}
+	r, err := q.Query(ctx, fmt.Sprintf("SELECT %s FROM %s%s ORDER BY %s",
+		strings.Join(columns, ", "), table, where, strings.Join(order, ", ")), args...)
+	if err != nil {
+		return nil, fmt.Errorf("lecture de %s impossible: %w", table, err)
+	}
+	defer r.Close()
+
+	dump := &backupTable{Columns: columns, Rows: [][]any{}}
+	targets := make([]any, len(columns))
+	for r.Next() {
+		for i, column := range columns {
+			targets[i] = kinds[column].scanTarget()
+		}
+		if err := r.Scan(targets...); err != nil {
+			return nil, fmt.Errorf("lecture de %s impossible: %w", table, err)
+		}
+		values := make([]any, len(columns))
+		for i, column := range columns {
+			values[i] = kinds[column].archiveValue(targets[i])
+		}
+		dump.Rows = append(dump.Rows, values)
+	}
+	return dump, r.Err()
+}
+
+// loadTable inserts the rows of the archive into a table, keeping their IDs
+// Columns the archive doesn't have (added by a later migration) get their default value.
+func loadTable(ctx context.Context, q querier, d dialect, table string, data *backupTable) error {
+	_, kinds, err := tableColumns(ctx, q, d, table)
+	if err != nil {
+		return err
+	}
+	for _, column := range data.Columns {
+		if _, ok := kinds[column]; !ok {
+			return fmt.Errorf("colonne %s.%s inconnue", table, column)
+		}
+	}
+
+	placeholders := make([]string, len(data.Columns))
+	for i := range placeholders {
+		placeholders[i] = fmt.Sprintf("$%d", i+1)
+	}
+	insert := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", table, strings.Join(data.Columns, ", "), strings.Join(placeholders, ", "))
+
+	for n, values := range data.Rows {
+		if len(values) != len(data.Columns) {
+			return fmt.Errorf("%s, ligne %d: %d valeur(s) pour %d colonne(s)", table, n+1, len(values), len(data.Columns))
+		}
+		args := make([]any, len(values))
+		for i, value := range values {
+			args[i], err = kinds[data.Columns[i]].databaseValue(value)
+			if err != nil {
+				return fmt.Errorf("%s, ligne %d, colonne %s: %w", table, n+1, data.Columns[i], err)
+			}
+		}
+		if _, err := q.Exec(ctx, insert, args...); err != nil {
+			return fmt.Errorf("%s, ligne %d: %w", table, n+1, err)
+		}
+	}
+	return nil
+}
+
+// updateTableRow writes the single row of data over the row selected by where ($1 = whereArg)
+// Unlike deleting and inserting it again, rows referencing it elsewhere are left alone. It
+// returns false when no row matched, for the caller to insert it with loadTable.
+func updateTableRow(ctx context.Context, q querier, d dialect, table, where string, whereArg any, data *backupTable) (bool, error) {
+	_, kinds, err := tableColumns(ctx, q, d, table)
+	if err != nil {
+		return false, err
+	}
+	if len(data.Rows) != 1 || len(data.Rows[0]) != len(data.Columns) {
+		return false, fmt.Errorf("%s: une seule ligne attendue", table)
+	}
+
+	assignments := make([]string, len(data.Columns))
+	args := []any{whereArg}
+	for i, column := range data.Columns {
+		kind, ok := kinds[column]
+		if !ok {
+			return false, fmt.Errorf("colonne %s.%s inconnue", table, column)
+		}
+		value, err := kind.databaseValue(data.Rows[0][i])
+		if err != nil {
+			return false, fmt.Errorf("%s, colonne %s: %w", table, column, err)
+		}
+		assignments[i] = fmt.Sprintf("%s = $%d", column, i+2)
+		args = append(args, value)
+	}
+
+	n, err := q.Exec(ctx, fmt.Sprintf("UPDATE %s SET %s WHERE %s", table, strings.Join(assignments, ", "), where), args...)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", table, err)
+	}
+	return n > 0, nil
+}
+
+// resetTableSequence moves the ID sequence of a table past the IDs restored by loadTable
+func resetTableSequence(ctx context.Context, q querier, d dialect, table string) error {
+	if d.resetSequence == "" {
+		return nil
+	}
+	columns, _, err := tableColumns(ctx, q, d, table)
+	if err != nil {
+		return err
+	}
+	if _, err := q.Exec(ctx, fmt.Sprintf(d.resetSequence, table, columns[0])); err != nil {
+		return fmt.Errorf("mise à jour de la séquence de %s impossible: %w", table, err)
+	}
+	return nil
+}
+
+// ========== Backup files ==========
+
+// Backups made by createBackup are named vgc-backup-2024-05-01_203000.zip, with a -2, -3...
+// suffix when several are made in the same second. The safety backups made before a restore
+// are named vgc-before-restore-... and rotated separately, so a restore never deletes the
+// archive it restores.
+const (
+	backupFilePrefix = "vgc-backup-"
+	safetyFilePrefix = "vgc-before-restore-"
+	backupFileLayout = "2006-01-02_150405"
+	backupFileExt    = ".zip"
+)
+
+// safetyBackupsKept is the number of safety backups kept in the backup folder
+const safetyBackupsKept = 3
+
+// Defaults of the BACKUP_* settings
+const (
+	defaultBackupDir  = "backups"
+	defaultBackupKeep = 10
+)
+
+// backupSettings are read from .env
+type backupSettings struct {
+	Dir      string        // BACKUP_DIR
+	Keep     int           // BACKUP_KEEP, number of backups kept in Dir
+	Interval time.Duration // BACKUP_INTERVAL ("24h", "30m"...), 0 disables the scheduled backups
+	OnExit   bool          // BACKUP_ON_EXIT
+}
+
+// loadBackupSettings reads the BACKUP_* variables, falling back to the defaults when invalid
+func loadBackupSettings() backupSettings {
+	settings := backupSettings{Dir: defaultBackupDir, Keep: defaultBackupKeep}
+	if dir := strings.TrimSpace(os.Getenv("BACKUP_DIR")); dir != "" {
+		settings.Dir = dir
+	}
+	if value := strings.TrimSpace(os.Getenv("BACKUP_KEEP")); value != "" {
+		if keep, err := strconv.Atoi(value); err == nil && keep > 0 {
+			settings.Keep = keep
+		} else {
+			log.Printf("Invalid BACKUP_KEEP %q, keeping %d backups", value, settings.Keep)
+		}
+	}
+	if value := strings.TrimSpace(os.Getenv("BACKUP_INTERVAL")); value != "" {
+		if interval, err := time.ParseDuration(value); err == nil && interval >= 0 {
+			settings.Interval = interval
+		} else {
+			log.Printf("Invalid BACKUP_INTERVAL %q, scheduled backups disabled", value)
+		}
+	}
+	if value := strings.TrimSpace(os.Getenv("BACKUP_ON_EXIT")); value != "" {
+		onExit, err := strconv.ParseBool(value)
+		if err != nil {
+			log.Printf("Invalid BACKUP_ON_EXIT %q, no backup on exit", value)
+		}
+		settings.OnExit = onExit
+	}
+	return settings
+}
+
+// backupMu keeps the scheduled, exit, manual and safety backups from running at the same time
+var backupMu sync.Mutex
+
+// createBackup writes a new backup into the folder of the settings then removes the oldest ones
+func createBackup(store CollectionStore, settings backupSettings) (string, *BackupManifest, error) {
+	backupMu.Lock()
+	defer backupMu.Unlock()
+
+	path, manifest, err := writeDatedBackup(store, settings.Dir, backupFilePrefix)
+	if err != nil {
+		return "", nil, err
+	}
+	if err := rotateBackups(settings.Dir, backupFilePrefix, settings.Keep, ""); err != nil {
+		log.Println("Unable to remove old backups:", err)
+	}
+	return path, manifest, nil
+}
+
+// createSafetyBackup writes the backup made before a restore into the folder of the settings
+// The older safety backups are removed by restoreBackupFile once the restore succeeded.
+func createSafetyBackup(store CollectionStore, settings backupSettings) (string, error) {
+	backupMu.Lock()
+	defer backupMu.Unlock()
+
+	path, _, err := writeDatedBackup(store, settings.Dir, safetyFilePrefix)
+	return path, err
+}
+
+// writeDatedBackup writes a backup into dir under prefix and the current time
+// The name is only taken once the archive is complete, and never replaces an existing backup.
+func writeDatedBackup(store CollectionStore, dir, prefix string) (string, *BackupManifest, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", nil, fmt.Errorf("création du dossier de sauvegarde impossible: %w", err)
+	}
+	tmp, manifest, err := writeBackupTemp(store, dir)
+	if err != nil {
+		return "", nil, err
+	}
+	defer os.Remove(tmp)
+
+	// Numbered after the backups of the same second, even when older ones were rotated away
+	now, seq := time.Now().Truncate(time.Second), 1
+	if backups, err := listBackups(dir, prefix); err == nil && len(backups) > 0 && backups[0].Time.Equal(now) {
+		seq = backups[0].Seq + 1
+	}
+	base := filepath.Join(dir, prefix+now.Format(backupFileLayout))
+	for ; ; seq++ {
+		path := base + backupFileExt
+		if seq > 1 {
+			path = fmt.Sprintf("%s-%d%s", base, seq, backupFileExt)
+		}
+		// The name is reserved with O_EXCL, so a backup made in the same second can't take it
+		reserved, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+		if errors.Is(err, os.ErrExist) {
+			continue
+		}
+		if err != nil {
+			return "", nil, fmt.Errorf("écriture de la sauvegarde impossible: %w", err)
+		}
+		reserved.Close()
+		if err := os.Rename(tmp, path); err != nil {
+			os.Remove(path)
+			return "", nil, fmt.Errorf("écriture de la sauvegarde impossible: %w", err)
+		}
+		return path, manifest, nil
+	}
+}
+
+// writeBackupFile writes a backup to path through a temporary file, so a failed backup
+// never leaves a truncated archive behind
+func writeBackupFile(store CollectionStore, path string) (*BackupManifest, error) {
+	tmp, manifest, err := writeBackupTemp(store, filepath.Dir(path))
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmp)
+
+	if err := os.Rename(tmp, path); err != nil {
+		return nil, fmt.Errorf("écriture de la sauvegarde impossible: %w", err)
+	}
+	return manifest, nil
+}
+
+// writeBackupTemp writes a backup to a temporary file of dir and returns its path
+// The caller renames or removes it.
+func writeBackupTemp(store CollectionStore, dir string) (string, *BackupManifest, error) {
+	tmp, err := os.CreateTemp(dir, ".tmp-*")
+	if err != nil {
+		return "", nil, fmt.Errorf("écriture de la sauvegarde impossible: %w", err)
+	}
+
+	manifest, err := store.WriteBackup(tmp)
+	if err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return "", nil, fmt.Errorf("échec de la sauvegarde: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return "", nil, fmt.Errorf("écriture de la sauvegarde impossible: %w", err)
+	}
+	return tmp.Name(), manifest, nil
+}
+
+// backupFile is a backup made by createBackup or createSafetyBackup
+type backupFile struct {
+	Path string
+	Time time.Time
+	Seq  int // 1, then 2, 3... for the backups made in the same second
+}
+
+// listBackups returns the backups of a folder named with prefix, newest first (none if the
+// folder doesn't exist)
+func listBackups(dir, prefix string) ([]backupFile, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var backups []backupFile
+	for _, entry := range entries {
+		stamp, found := strings.CutPrefix(entry.Name(), prefix)
+		stamp, hasExt := strings.CutSuffix(stamp, backupFileExt)
+		if entry.IsDir() || !found || !hasExt || len(stamp) < len(backupFileLayout) {
+			continue
+		}
+		t, err := time.ParseInLocation(backupFileLayout, stamp[:len(backupFileLayout)], time.Local)
+		if err != nil {
+			continue // Not one of ours
+		}
+		seq := 1
+		if suffix := stamp[len(backupFileLayout):]; suffix != "" {
+			n, err := strconv.Atoi(strings.TrimPrefix(suffix, "-"))
+			if err != nil || !strings.HasPrefix(suffix, "-") || n < 2 {
+				continue
+			}
+			seq = n
+		}
+		backups = append(backups, backupFile{Path: filepath.Join(dir, entry.Name()), Time: t, Seq: seq})
+	}
+	slices.SortFunc(backups, func(a, b backupFile) int {
+		if c := b.Time.Compare(a.Time); c != 0 {
+			return c
+		}
+		return b.Seq - a.Seq
+	})
+	return backups, nil
+}
+
+// rotateBackups deletes the backups of a folder named with prefix beyond the newest keep
+// spare, when not empty, is never deleted (the archive being restored).
+func rotateBackups(dir, prefix string, keep int, spare string) error {
+	backups, err := listBackups(dir, prefix)
+	if err != nil {
+		return err
+	}
+	spareInfo, _ := os.Stat(spare)
+	for _, old := range backups[min(keep, len(backups)):] {
+		if info, err := os.Stat(old.Path); err == nil && spareInfo != nil && os.SameFile(info, spareInfo) {
+			continue
+		}
+		if err := os.Remove(old.Path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+// startBackupSchedule makes a backup every settings.Interval while the app runs
+// The first one is due an interval after the newest existing backup, so an app that is
+// restarted often neither skips nor multiplies backups.
+func startBackupSchedule(store CollectionStore, settings backupSettings) {
+	if settings.Interval <= 0 {
+		return
+	}
+
+	go func() {
+		var wait time.Duration
+		if backups, err := listBackups(settings.Dir, backupFilePrefix); err == nil && len(backups) > 0 {
+			wait = max(0, time.Until(backups[0].Time.Add(settings.Interval)))
+		}
+		for {
+			time.Sleep(wait)
+			if path, _, err := createBackup(store, settings); err != nil {
+				log.Println("Scheduled backup failed:", err)
+			} else {
+				log.Println("Backup written to", path)
+			}
+			wait = settings.Interval
+		}
+	}()
+}
+
+// openBackup opens an archive and reads its manifest; the caller closes the archive
+func openBackup(path string) (*zip.ReadCloser, *BackupManifest, error) {
+	archive, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("ce fichier n'est pas une sauvegarde VGC: %w", err)
+	}
+	manifest, err := readBackupManifest(&archive.Reader)
+	if err != nil {
+		archive.Close()
+		return nil, nil, err
+	}
+	return archive, manifest, nil
+}
+
+// restoreBackupFile replaces the whole collection with a backup
+// The current data is backed up first, so a restore can itself be undone. Only the newest
+// safety backups are kept, and the archive being restored is never deleted.
+func restoreBackupFile(store CollectionStore, settings backupSettings, path string) (safetyPath string, err error) {
+	archive, _, err := openBackup(path)
+	if err != nil {
+		return "", err
+	}
+	defer archive.Close()
+
+	safetyPath, err = createSafetyBackup(store, settings)
+	if err != nil {
+		return "", fmt.Errorf("sauvegarde des données actuelles impossible, rien n'a été restauré: %w", err)
+	}
+	if err := store.RestoreBackup(&archive.Reader); err != nil {
+		return safetyPath, fmt.Errorf("échec de la restauration, les données actuelles sont conservées: %w", err)
+	}
+
+	if err := rotateBackups(settings.Dir, safetyFilePrefix, safetyBackupsKept, path); err != nil {
+		log.Println("Unable to remove old safety backups:", err)
+	}
+	return safetyPath, nil
+}
+
+// backupMediaName is the name of a picture in the archive
+func backupMediaName(hash, ext string) string {
+	return path.Join(backupMediaDir, hash+ext)
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+)
+
+// ========== BACKUP DIALOGS ==========
+
+// showBackupNow writes a backup into the backup folder and tells where
+func showBackupNow(w fyne.Window, store CollectionStore) {
+	path, manifest, err := createBackup(store, loadBackupSettings())
+	if err != nil {
+		dialog.ShowError(err, w)
+		return
+	}
+	dialog.ShowInformation("Sauvegarde terminée", fmt.Sprintf("%s sauvegardé(s) dans\n%s", backupSummary(manifest), path), w)
+}
+
+// showRestoreDialog asks for a backup, describes it and replaces the collection once confirmed
+// onRestored reloads every tab.
+func showRestoreDialog(w fyne.Window, store CollectionStore, onRestored func()) {
+	settings := loadBackupSettings()
+
+	fileDialog := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		if reader == nil {
+			return // Cancelled
+		}
+		path := reader.URI().Path()
+		reader.Close()
+
+		archive, manifest, err := openBackup(path)
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("restauration impossible: %w", err), w)
+			return
+		}
+		archive.Close()
+
+		message := fmt.Sprintf("Sauvegarde du %s (%s)\n%s\n\nToutes les données actuelles seront remplacées.\nElles sont d'abord sauvegardées dans %s.",
+			manifest.CreatedAt.Local().Format("02/01/2006 à 15:04"), manifest.Driver, backupSummary(manifest), settings.Dir)
+		dialog.ShowConfirm("Restaurer la sauvegarde ?", message, func(confirmed bool) {
+			if !confirmed {
+				return
+			}
+			safetyPath, err := restoreBackupFile(store, settings, path)
+			if err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			onRestored()
+			dialog.ShowInformation("Restauration terminée", fmt.Sprintf("Collection restaurée.\nLes données précédentes sont dans %s.", safetyPath), w)
+		}, w)
+	}, w)
+
+	// Start in the backup folder when it exists
+	if dir, err := filepath.Abs(settings.Dir); err == nil {
+		if lister, err := storage.ListerForURI(storage.NewFileURI(dir)); err == nil {
+			fileDialog.SetLocation(lister)
+		}
+	}
+	fileDialog.SetFilter(storage.NewExtensionFileFilter([]string{backupFileExt}))
+	fileDialog.Resize(fyne.NewSize(800, 600))
+	fileDialog.Show()
+}
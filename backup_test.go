@@ -0,0 +1,154 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestColumnKindOf(t *testing.T) {
+	tests := []struct {
+		declared string
+		want     columnKind
+	}{
+		{"TEXT", kindText},
+		{"VARCHAR(255)", kindText},
+		{"character varying", kindText},
+		{"INTEGER", kindInteger},
+		{"bigint", kindInteger},
+		{"SMALLINT", kindInteger},
+		{"NUMERIC(10, 2)", kindNumber},
+		{"numeric", kindNumber},
+		{"DECIMAL(7,1)", kindNumber},
+		{"REAL", kindNumber},
+		{"double precision", kindNumber},
+		{"BOOLEAN", kindBool},
+		{"DATE", kindDate},
+		{"TIMESTAMP", kindTimestamp},
+		{"timestamp with time zone", kindTimestamp},
+		{"DATETIME", kindTimestamp},
+		{"BLOB", kindText},
+		{"", kindText},
+	}
+
+	for _, tt := range tests {
+		if got := columnKindOf(tt.declared); got != tt.want {
+			t.Errorf("columnKindOf(%q) = %d, want %d", tt.declared, got, tt.want)
+		}
+	}
+}
+
+// TestColumnValueRoundTrip scans a value, writes it to an archive and reads it back
+func TestColumnValueRoundTrip(t *testing.T) {
+	paris := time.FixedZone("Paris", 2*60*60)
+	tests := []struct {
+		kind    columnKind
+		scanned any // Value set by the database driver, nil for NULL
+		archive any // Value stored in the archive
+		want    any // Value given back to the database
+	}{
+		{kindText, "Chrono Trigger", "Chrono Trigger", "Chrono Trigger"},
+		{kindText, "", "", ""},
+		{kindInteger, int64(42), int64(42), int64(42)},
+		{kindInteger, int64(-7), int64(-7), int64(-7)},
+		{kindNumber, 19.99, 19.99, 19.99},
+		{kindNumber, 999999.9, 999999.9, 999999.9},
+		{kindBool, true, true, true},
+		{kindBool, false, false, false},
+		{kindDate, time.Date(1995, 3, 11, 0, 0, 0, 0, time.UTC), "1995-03-11", time.Date(1995, 3, 11, 0, 0, 0, 0, time.UTC)},
+		// Timestamps are archived in UTC, to the second
+		{kindTimestamp, time.Date(2024, 5, 1, 22, 30, 15, 123, paris), "2024-05-01 20:30:15", time.Date(2024, 5, 1, 20, 30, 15, 0, time.UTC)},
+		{kindText, nil, nil, nil},
+		{kindInteger, nil, nil, nil},
+		{kindNumber, nil, nil, nil},
+		{kindBool, nil, nil, nil},
+		{kindDate, nil, nil, nil},
+		{kindTimestamp, nil, nil, nil},
+	}
+
+	for _, tt := range tests {
+		target := tt.kind.scanTarget()
+		if tt.scanned != nil {
+			// What rows.Scan does for a non NULL value
+			value := reflect.New(reflect.TypeOf(tt.scanned))
+			value.Elem().Set(reflect.ValueOf(tt.scanned))
+			reflect.ValueOf(target).Elem().Set(value)
+		}
+
+		archived := tt.kind.archiveValue(target)
+		if archived != tt.archive {
+			t.Errorf("kind %d: archiveValue(%v) = %#v, want %#v", tt.kind, tt.scanned, archived, tt.archive)
+			continue
+		}
+
+		// Undo snapshots keep the archived value as is
+		got, err := tt.kind.databaseValue(archived)
+		if err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("kind %d: databaseValue(%#v) = %#v, %v, want %#v", tt.kind, archived, got, err, tt.want)
+		}
+
+		// Archive files go through JSON, numbers decoded with UseNumber
+		encoded, err := json.Marshal(archived)
+		if err != nil {
+			t.Fatal(err)
+		}
+		decoder := json.NewDecoder(bytes.NewReader(encoded))
+		decoder.UseNumber()
+		var decoded any
+		if err := decoder.Decode(&decoded); err != nil {
+			t.Fatal(err)
+		}
+		got, err = tt.kind.databaseValue(decoded)
+		if err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("kind %d: databaseValue(%s) = %#v, %v, want %#v", tt.kind, encoded, got, err, tt.want)
+		}
+	}
+}
+
+func TestDatabaseValue(t *testing.T) {
+	tests := []struct {
+		kind  columnKind
+		value any
+		want  any
+	}{
+		// Booleans written as 0/1 (SQLite archives) and values of older archives
+		{kindBool, json.Number("1"), true},
+		{kindBool, json.Number("0"), false},
+		{kindText, json.Number("12"), "12"},
+		{kindText, true, "true"},
+		{kindDate, "2024-05-01T20:30:15Z", time.Date(2024, 5, 1, 20, 30, 15, 0, time.UTC)},
+		{kindTimestamp, "2024-05-01", time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)},
+		{kindNumber, json.Number("45"), 45.0},
+	}
+
+	for _, tt := range tests {
+		got, err := tt.kind.databaseValue(tt.value)
+		if err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("kind %d: databaseValue(%#v) = %#v, %v, want %#v", tt.kind, tt.value, got, err, tt.want)
+		}
+	}
+}
+
+func TestDatabaseValueErrors(t *testing.T) {
+	tests := []struct {
+		kind  columnKind
+		value any
+	}{
+		{kindInteger, json.Number("1.5")},
+		{kindInteger, "42"},
+		{kindNumber, "19.99"},
+		{kindNumber, true},
+		{kindBool, "oui"},
+		{kindDate, "11/03/1995"},
+		{kindTimestamp, json.Number("1714595415")},
+		{kindText, []any{"a"}},
+	}
+
+	for _, tt := range tests {
+		if got, err := tt.kind.databaseValue(tt.value); err == nil {
+			t.Errorf("kind %d: databaseValue(%#v) = %#v, want an error", tt.kind, tt.value, got)
+		}
+	}
+}
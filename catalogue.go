@@ -0,0 +1,414 @@
+package main
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"golang.org/x/text/collate"
+	"golang.org/x/text/language"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// ========== WEB CATALOGUE ==========
+// A read-only HTML catalogue of the owned games, grouped by platform, to show friends what is
+// in the collection. "vgc catalogue" serves it and "vgc publish" writes it as a static site;
+// both render the same pages from GetGames and GetGameByID. Pages carry their own style and
+// search script, so the only other files are the cover thumbnails:
+//
+//	index.html          every game, grouped by platform, with a search box
+//	games/<id>.html     details of a game and its pictures
+//	covers/<id>.jpg     thumbnail of the cover of a game, covers/<id>-<image>.jpg its other pictures
+//
+// Prices, notes and loans stay private.
+
+//go:embed templates/catalogue_*.html
+var catalogueTemplateFiles embed.FS
+
+var catalogueTemplates = template.Must(template.ParseFS(catalogueTemplateFiles, "templates/catalogue_*.html"))
+
+// errNotInCatalogue is returned for a game that isn't owned (or doesn't exist)
+var errNotInCatalogue = errors.New("jeu absent du catalogue")
+
+// cataloguePlatform is a section of the index page
+type cataloguePlatform struct {
+	Name   string
+	Anchor string
+	Games  []catalogueCard
+}
+
+// catalogueCard is a game on the index page
+type catalogueCard struct {
+	Page   string
+	Cover  string // "" without picture
+	Title  string
+	Meta   string // Genre and year
+	Badge  string // Completeness and number of copies
+	Search string // Text matched by the search box, folded like the page script does
+}
+
+// catalogueField is a line of the details of a game
+type catalogueField struct {
+	Label string
+	Value string
+}
+
+// renderCatalogueIndex writes the index page and returns the games it lists
+func renderCatalogueIndex(w io.Writer, store CollectionStore, generated time.Time) ([]Game, error) {
+	games, err := store.GetGames()
+	if err != nil {
+		return nil, err
+	}
+	games = slices.DeleteFunc(games, func(g Game) bool { return !g.Owned })
+
+	collator := collate.New(language.French, collate.IgnoreCase, collate.IgnoreDiacritics, collate.Numeric)
+	slices.SortStableFunc(games, func(a, b Game) int {
+		if a.ConsoleName != b.ConsoleName {
+			// Games without a platform come last
+			if a.ConsoleName == "" || b.ConsoleName == "" {
+				return strings.Compare(b.ConsoleName, a.ConsoleName)
+			}
+			return collator.CompareString(a.ConsoleName, b.ConsoleName)
+		}
+		return collator.CompareString(a.Title, b.Title)
+	})
+
+	var platforms []cataloguePlatform
+	for i := range games {
+		g := &games[i]
+		name := g.ConsoleName
+		if name == "" {
+			name = notSetLabel
+		}
+		if len(platforms) == 0 || platforms[len(platforms)-1].Name != name {
+			platforms = append(platforms, cataloguePlatform{Name: name, Anchor: fmt.Sprintf("plateforme-%d", len(platforms)+1)})
+		}
+		platform := &platforms[len(platforms)-1]
+		platform.Games = append(platform.Games, catalogueGameCard(g))
+	}
+
+	data := struct {
+		GameCount int
+		Platforms []cataloguePlatform
+		Generated string
+	}{len(games), platforms, generated.Format("02/01/2006")}
+	if err := catalogueTemplates.ExecuteTemplate(w, "index", data); err != nil {
+		return nil, err
+	}
+	return games, nil
+}
+
+// catalogueGameCard describes a game of the list query on the index page
+func catalogueGameCard(g *Game) catalogueCard {
+	card := catalogueCard{
+		Page:  fmt.Sprintf("games/%d.html", g.GameID),
+		Title: g.Title,
+	}
+	if g.CoverThumb != "" {
+		card.Cover = fmt.Sprintf("covers/%d.jpg", g.GameID)
+	}
+
+	var meta []string
+	if g.GenreName != "" {
+		meta = append(meta, g.GenreName)
+	}
+	if year, ok := earliestYear(g.JPReleaseDate, g.USReleaseDate, g.EUReleaseDate); ok {
+		meta = append(meta, strconv.Itoa(int(year)))
+	}
+	card.Meta = strings.Join(meta, " · ")
+
+	var badge []string
+	if label := completenessLabels[g.Completeness]; label != "" {
+		badge = append(badge, label)
+	}
+	if g.CopyCount > 1 {
+		badge = append(badge, fmt.Sprintf("%d exemplaires", g.CopyCount))
+	}
+	card.Badge = strings.Join(badge, " · ")
+
+	words := []string{g.Title, g.ConsoleName, g.GenreName}
+	words = append(words, g.Developers...)
+	words = append(words, g.Publishers...)
+	card.Search = foldSearchText(strings.Join(words, " "))
+	return card
+}
+
+// foldSearchText lowercases a text and strips its accents, like the search script of the index
+func foldSearchText(text string) string {
+	folded, _, err := transform.String(transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC), text)
+	if err != nil {
+		folded = text
+	}
+	return strings.ToLower(folded)
+}
+
+// renderCatalogueGame writes the page of an owned game and returns its pictures
+func renderCatalogueGame(w io.Writer, store CollectionStore, gameID int, generated time.Time) ([]ItemImage, error) {
+	game, err := store.GetGameByID(gameID)
+	if errors.Is(err, errNoRows) || (err == nil && !game.Owned) {
+		return nil, errNotInCatalogue
+	}
+	if err != nil {
+		return nil, err
+	}
+	images, err := store.GetImages("game", gameID)
+	if err != nil {
+		return nil, err
+	}
+
+	var pictures []string
+	for _, img := range images {
+		pictures = append(pictures, fmt.Sprintf("../covers/%d-%d.jpg", gameID, img.ImageID))
+	}
+
+	var fields []catalogueField
+	add := func(label, value string) {
+		if value != "" {
+			fields = append(fields, catalogueField{label, value})
+		}
+	}
+	add("Genre", game.GenreName)
+	add("Développeur(s)", strings.Join(game.Developers, ", "))
+	add("Distributeur(s)", strings.Join(game.Publishers, ", "))
+	add("Compositeur(s)", strings.Join(game.Composers, ", "))
+	add("Producteur(s)", strings.Join(game.Producers, ", "))
+	for _, release := range []struct {
+		label string
+		date  *time.Time
+	}{{"Sortie Japon", game.JPReleaseDate}, {"Sortie USA", game.USReleaseDate}, {"Sortie Europe", game.EUReleaseDate}} {
+		if release.date != nil {
+			add(release.label, release.date.Format("02/01/2006"))
+		}
+	}
+	for i := range game.Copies {
+		label := "Exemplaire"
+		if len(game.Copies) > 1 {
+			label = fmt.Sprintf("Exemplaire %d", i+1)
+		}
+		add(label, catalogueCopyText(&game.Copies[i]))
+	}
+	add("Progression", playStatusText(game.PlayStatus))
+	add("Note", ratingText(game.PersonalRating))
+
+	platform := game.ConsoleName
+	if platform == "" {
+		platform = notSetLabel
+	}
+	review := ""
+	if game.Review != nil {
+		review = *game.Review
+	}
+
+	data := struct {
+		Title     string
+		Platform  string
+		Images    []string
+		Fields    []catalogueField
+		Review    string
+		Generated string
+	}{game.Title, platform, pictures, fields, review, generated.Format("02/01/2006")}
+	if err := catalogueTemplates.ExecuteTemplate(w, "game", data); err != nil {
+		return nil, err
+	}
+	return images, nil
+}
+
+// catalogueCopyText describes a copy without its price
+func catalogueCopyText(c *GameCopy) string {
+	var parts []string
+	if c.Region != nil {
+		parts = append(parts, *c.Region)
+	}
+	if c.Edition != nil {
+		parts = append(parts, *c.Edition)
+	}
+	if c.Condition != nil {
+		parts = append(parts, conditionToStars(c.Condition))
+	}
+	if label := completenessLabels[c.Completeness]; label != "" {
+		parts = append(parts, label)
+	}
+	// A loose copy misses everything by definition, only a complete one is worth detailing
+	if len(c.Missing) > 0 && c.Completeness == GradeCIB {
+		parts = append(parts, missingComponentsText(c.Missing))
+	}
+	if len(parts) == 0 {
+		return "sans détails"
+	}
+	return strings.Join(parts, " · ")
+}
+
+// catalogueCoverPath returns the thumbnail behind a covers/ file name of an owned game
+// "12.jpg" is the cover of game 12 (its first picture), "12-34.jpg" its picture 34.
+func catalogueCoverPath(store CollectionStore, name string) (string, error) {
+	base, found := strings.CutSuffix(name, ".jpg")
+	if !found {
+		return "", errNotInCatalogue
+	}
+	gameText, imageText, specific := strings.Cut(base, "-")
+	gameID, err := strconv.Atoi(gameText)
+	if err != nil {
+		return "", errNotInCatalogue
+	}
+
+	game, err := store.GetGameByID(gameID)
+	if errors.Is(err, errNoRows) || (err == nil && !game.Owned) {
+		return "", errNotInCatalogue
+	}
+	if err != nil {
+		return "", err
+	}
+	images, err := store.GetImages("game", gameID)
+	if err != nil {
+		return "", err
+	}
+
+	for _, img := range images {
+		if !specific || strconv.Itoa(img.ImageID) == imageText {
+			return img.ThumbPath, nil
+		}
+	}
+	return "", errNotInCatalogue
+}
+
+// ========== Server ==========
+
+// newCatalogueHandler serves the catalogue, rendered on each request so it is always up to date
+func newCatalogueHandler(store CollectionStore) http.Handler {
+	mux := http.NewServeMux()
+
+	index := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		if _, err := renderCatalogueIndex(w, store, time.Now()); err != nil {
+			catalogueError(w, err)
+		}
+	}
+	mux.HandleFunc("GET /{$}", index)
+	mux.HandleFunc("GET /index.html", index)
+
+	mux.HandleFunc("GET /games/{page}", func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(strings.TrimSuffix(r.PathValue("page"), ".html"))
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		if _, err := renderCatalogueGame(w, store, id, time.Now()); err != nil {
+			catalogueError(w, err)
+		}
+	})
+
+	mux.HandleFunc("GET /covers/{name}", func(w http.ResponseWriter, r *http.Request) {
+		path, err := catalogueCoverPath(store, r.PathValue("name"))
+		if err != nil {
+			catalogueError(w, err)
+			return
+		}
+		w.Header().Set("Cache-Control", "max-age=3600")
+		http.ServeFile(w, r, path)
+	})
+
+	return mux
+}
+
+// catalogueError answers 404 for what isn't in the catalogue, 500 otherwise
+func catalogueError(w http.ResponseWriter, err error) {
+	if errors.Is(err, errNotInCatalogue) {
+		http.Error(w, "Page introuvable", http.StatusNotFound)
+		return
+	}
+	http.Error(w, "Erreur: "+err.Error(), http.StatusInternalServerError)
+}
+
+// ========== Static site ==========
+
+// publishCatalogue writes the catalogue into dir and returns the number of games
+// The games/ and covers/ folders are recreated so games sold since the last run disappear.
+func publishCatalogue(store CollectionStore, dir string, generated time.Time) (int, error) {
+	for _, sub := range []string{"games", "covers"} {
+		if err := os.RemoveAll(filepath.Join(dir, sub)); err != nil {
+			return 0, err
+		}
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0o755); err != nil {
+			return 0, err
+		}
+	}
+
+	var games []Game
+	err := writeCatalogueFile(filepath.Join(dir, "index.html"), func(w io.Writer) error {
+		var err error
+		games, err = renderCatalogueIndex(w, store, generated)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	for _, g := range games {
+		if g.CoverThumb != "" {
+			if err := copyFile(g.CoverThumb, filepath.Join(dir, "covers", fmt.Sprintf("%d.jpg", g.GameID))); err != nil {
+				return 0, err
+			}
+		}
+
+		var images []ItemImage
+		err := writeCatalogueFile(filepath.Join(dir, "games", fmt.Sprintf("%d.html", g.GameID)), func(w io.Writer) error {
+			var err error
+			images, err = renderCatalogueGame(w, store, g.GameID, generated)
+			return err
+		})
+		if err != nil {
+			return 0, fmt.Errorf("jeu '%s': %w", g.Title, err)
+		}
+		for _, img := range images {
+			if err := copyFile(img.ThumbPath, filepath.Join(dir, "covers", fmt.Sprintf("%d-%d.jpg", g.GameID, img.ImageID))); err != nil {
+				return 0, err
+			}
+		}
+	}
+	return len(games), nil
+}
+
+// writeCatalogueFile creates a file and fills it with render
+func writeCatalogueFile(path string, render func(w io.Writer) error) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := render(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// copyFile copies a file, overwriting the destination
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,1019 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"maps"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
+)
+
+// ========== COMMAND LINE ==========
+// Started with arguments, vgc runs one command against the collection and exits instead of
+// opening the window, so the collection can be scripted from a terminal or cron without a
+// display. Commands go through the same CollectionStore as the UI; lists take the search
+// syntax of query.go and print a table, JSON or CSV (the export writers of export.go).
+
+const cliUsage = `Usage: vgc <commande> [options]
+
+Commandes:
+  games list          Liste les jeux possédés (--wishlist: les jeux souhaités)
+  games show <id>     Affiche tous les détails d'un jeu
+  games add           Ajoute un jeu (--title et --console requis)
+  consoles list       Liste les consoles
+  consoles show <id>  Affiche tous les détails d'une console
+  accessories list    Liste les accessoires
+  accessories show <id>
+                      Affiche tous les détails d'un accessoire
+  export              Exporte la collection en CSV, JSON ou XLSX
+  import <fichier>    Importe un fichier CSV ou XLSX (--type requis)
+  stats               Affiche les chiffres du tableau de bord
+  serve               Lance l'API REST (JSON) pour le réseau local
+  catalogue           Sert le catalogue HTML des jeux possédés, en lecture seule
+  publish             Écrit le catalogue HTML en site statique (--output)
+  backup              Sauvegarde toute la base et les images dans une archive
+  restore <fichier>   Remplace toute la base par une sauvegarde (--yes requis)
+
+"vgc <commande> -h" détaille les options d'une commande.
+Sans argument, vgc ouvre la fenêtre.
+`
+
+// Output formats of the list, show and stats commands
+const (
+	cliTable = "table"
+	cliJSON  = "json"
+	cliCSV   = "csv"
+)
+
+// cliEntityTypes maps the spellings of --type to entity types
+var cliEntityTypes = map[string]string{
+	"game": "game", "games": "game", "jeu": "game", "jeux": "game",
+	"console": "console", "consoles": "console",
+	"accessory": "accessory", "accessories": "accessory", "accessoire": "accessory", "accessoires": "accessory",
+}
+
+// cliUsageError reports a malformed command line; the usage is printed with it
+type cliUsageError struct {
+	Message string
+}
+
+func (e *cliUsageError) Error() string {
+	return e.Message
+}
+
+func usageErrorf(format string, args ...any) error {
+	return &cliUsageError{Message: fmt.Sprintf(format, args...)}
+}
+
+// runCLI runs the command given by args and returns the exit status
+// 0 on success, 1 when the command fails, 2 when the command line is malformed.
+func runCLI(args []string, stdout, stderr io.Writer) int {
+	err := dispatchCLI(args, stdout, stderr)
+
+	var usageErr *cliUsageError
+	switch {
+	case err == nil, errors.Is(err, flag.ErrHelp):
+		return 0
+	case errors.As(err, &usageErr):
+		fmt.Fprintf(stderr, "vgc: %v\n\n%s", err, cliUsage)
+		return 2
+	case errors.Is(err, errCLIFlags):
+		return 2 // The flag package has already printed the problem and the options
+	default:
+		fmt.Fprintf(stderr, "vgc: %v\n", err)
+		return 1
+	}
+}
+
+func dispatchCLI(args []string, stdout, stderr io.Writer) error {
+	if len(args) == 0 {
+		return usageErrorf("commande manquante")
+	}
+	switch args[0] {
+	case "-h", "-help", "--help", "help":
+		fmt.Fprint(stdout, cliUsage)
+		return nil
+	case "export":
+		return runExportCommand(args[1:], stdout, stderr)
+	case "import":
+		return runImportCommand(args[1:], stdout, stderr)
+	case "stats":
+		return runStatsCommand(args[1:], stdout, stderr)
+	case "serve":
+		return runServeCommand(args[1:], stderr)
+	case "catalogue":
+		return runCatalogueCommand(args[1:], stderr)
+	case "publish":
+		return runPublishCommand(args[1:], stdout, stderr)
+	case "backup":
+		return runBackupCommand(args[1:], stdout, stderr)
+	case "restore":
+		return runRestoreCommand(args[1:], stdout, stderr)
+	case "games", "consoles", "accessories":
+	default:
+		return usageErrorf("commande inconnue '%s'", args[0])
+	}
+
+	if len(args) < 2 {
+		return usageErrorf("%s: sous-commande manquante", args[0])
+	}
+	entityType := cliEntityTypes[args[0]]
+	name := args[0] + " " + args[1]
+
+	switch {
+	case args[1] == "list":
+		return runListCommand(name, entityType, args[2:], stdout, stderr)
+	case args[1] == "show":
+		return runShowCommand(name, entityType, args[2:], stdout, stderr)
+	case args[1] == "add" && entityType == "game":
+		return runAddGameCommand(name, args[2:], stdout, stderr)
+	default:
+		return usageErrorf("commande inconnue '%s'", name)
+	}
+}
+
+// ========== Flags ==========
+
+// errCLIFlags is returned when the flag package rejected the options
+var errCLIFlags = errors.New("options invalides")
+
+// newCLIFlags creates the options of a command, printing their help to stderr
+func newCLIFlags(name, arguments string, stderr io.Writer) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: vgc %s [options]%s\n\nOptions:\n", name, arguments)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parseCLIFlags parses the options wherever they are and returns the other arguments
+// The flag package stops at the first argument, so "games show 12 --format json" needs a loop.
+func parseCLIFlags(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				return nil, err
+			}
+			return nil, errCLIFlags
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// checkCLIFormat validates the --format option against the formats a command supports
+func checkCLIFormat(format string, formats ...string) error {
+	if !slices.Contains(formats, format) {
+		return usageErrorf("format inconnu '%s' (%s attendu)", format, strings.Join(formats, ", "))
+	}
+	return nil
+}
+
+// queryTermText quotes a filter value for the search syntax when it holds spaces
+func queryTermText(field, value string) string {
+	if strings.ContainsAny(value, " \t") {
+		value = `"` + value + `"`
+	}
+	return field + ":" + value
+}
+
+// ========== Output ==========
+
+// writeCLIOutput prints a table in the chosen format
+// JSON and CSV are those of the export, so a list can be imported back or read by a script.
+func writeCLIOutput(w io.Writer, format string, table *exportTable) error {
+	switch format {
+	case cliJSON:
+		return writeExportJSON(w, []*exportTable{table})
+	case cliCSV:
+		return writeExportCSV(w, table)
+	default:
+		return writeCLITable(w, table)
+	}
+}
+
+// writeCLITable aligns the rows in columns for a terminal
+func writeCLITable(w io.Writer, table *exportTable) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(table.Labels, "\t"))
+	for _, row := range table.Rows {
+		cells := make([]string, len(row))
+		for i, value := range row {
+			cells[i] = cliCellText(value)
+		}
+		fmt.Fprintln(tw, strings.Join(cells, "\t"))
+	}
+	return tw.Flush()
+}
+
+// writeCLIDetails prints the fields of a single row one per line, leaving empty ones out
+func writeCLIDetails(w io.Writer, table *exportTable) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for i, value := range table.Rows[0] {
+		if text := cliCellText(value); text != "" {
+			fmt.Fprintf(tw, "%s:\t%s\n", table.Labels[i], text)
+		}
+	}
+	return tw.Flush()
+}
+
+// cliCellText formats a value on a single line
+func cliCellText(value any) string {
+	if list, ok := value.([]string); ok {
+		return strings.Join(list, " | ")
+	}
+	return strings.Join(strings.Fields(formatExportValue(value)), " ")
+}
+
+// ========== Lists ==========
+
+var cliGameColumns = []exportColumn[Game]{
+	{"id", "ID", func(g *Game) any { return g.GameID }},
+	{"title", "Titre", func(g *Game) any { return g.Title }},
+	{"console", "Plateforme", func(g *Game) any { return optionalString(g.ConsoleName) }},
+	{"genre", "Genre", func(g *Game) any { return optionalString(g.GenreName) }},
+	{"condition", "État", func(g *Game) any { return optional(g.Condition) }},
+	{"copies", "Exemplaires", func(g *Game) any { return g.CopyCount }},
+	{"completeness", "Complétude", func(g *Game) any { return optionalString(completenessLabels[g.Completeness]) }},
+	{"play_status", "Progression", func(g *Game) any { return optionalString(playStatusText(g.PlayStatus)) }},
+	{"personal_rating", "Note personnelle", func(g *Game) any { return optional(g.PersonalRating) }},
+	{"lent_to", "Prêté à", func(g *Game) any { return optionalString(g.LentTo) }},
+}
+
+var cliWantedGameColumns = []exportColumn[Game]{
+	{"id", "ID", func(g *Game) any { return g.GameID }},
+	{"title", "Titre", func(g *Game) any { return g.Title }},
+	{"console", "Plateforme", func(g *Game) any { return optionalString(g.ConsoleName) }},
+	{"wish_priority", "Priorité", func(g *Game) any { return optionalString(wishPriorityText(g.WishPriority)) }},
+	{"target_price", "Prix cible", func(g *Game) any { return optional(g.TargetPrice) }},
+	{"wish_edition", "Région / édition", func(g *Game) any { return optional(g.WishEdition) }},
+}
+
+var cliConsoleColumns = []exportColumn[Console]{
+	{"id", "ID", func(c *Console) any { return c.ConsoleID }},
+	{"name", "Nom", func(c *Console) any { return c.Name }},
+	{"type", "Type", func(c *Console) any { return optionalString(c.TypeName) }},
+	{"manufacturer", "Fabricant", func(c *Console) any { return optionalString(c.ManufacturerName) }},
+	{"generation", "Génération", func(c *Console) any { return optional(c.Generation) }},
+	{"condition", "État", func(c *Console) any { return optional(c.Condition) }},
+	{"lent_to", "Prêté à", func(c *Console) any { return optionalString(c.LentTo) }},
+}
+
+var cliWantedConsoleColumns = []exportColumn[Console]{
+	{"id", "ID", func(c *Console) any { return c.ConsoleID }},
+	{"name", "Nom", func(c *Console) any { return c.Name }},
+	{"manufacturer", "Fabricant", func(c *Console) any { return optionalString(c.ManufacturerName) }},
+	{"wish_priority", "Priorité", func(c *Console) any { return optionalString(wishPriorityText(c.WishPriority)) }},
+	{"target_price", "Prix cible", func(c *Console) any { return optional(c.TargetPrice) }},
+	{"wish_edition", "Région / édition", func(c *Console) any { return optional(c.WishEdition) }},
+}
+
+var cliAccessoryColumns = []exportColumn[Accessory]{
+	{"id", "ID", func(a *Accessory) any { return a.AccessoryID }},
+	{"name", "Nom", func(a *Accessory) any { return a.Name }},
+	{"type", "Type", func(a *Accessory) any { return optionalString(a.TypeName) }},
+	{"manufacturer", "Fabricant", func(a *Accessory) any { return optionalString(a.ManufacturerName) }},
+	{"quantity", "Quantité", func(a *Accessory) any { return a.Quantity }},
+	{"condition", "État", func(a *Accessory) any { return optional(a.Condition) }},
+	{"lent_to", "Prêté à", func(a *Accessory) any { return optionalString(a.LentTo) }},
+}
+
+var cliWantedAccessoryColumns = []exportColumn[Accessory]{
+	{"id", "ID", func(a *Accessory) any { return a.AccessoryID }},
+	{"name", "Nom", func(a *Accessory) any { return a.Name }},
+	{"type", "Type", func(a *Accessory) any { return optionalString(a.TypeName) }},
+	{"wish_priority", "Priorité", func(a *Accessory) any { return optionalString(wishPriorityText(a.WishPriority)) }},
+	{"target_price", "Prix cible", func(a *Accessory) any { return optional(a.TargetPrice) }},
+	{"wish_edition", "Région / édition", func(a *Accessory) any { return optional(a.WishEdition) }},
+}
+
+// cliListFilters are the filter options of a list command, turned into a search query
+type cliListFilters struct {
+	query    *string
+	wishlist *bool
+	fields   map[string]*string // Search field -> option value
+}
+
+// newCLIListFilters declares the common filter options plus one option per search field
+func newCLIListFilters(fs *flag.FlagSet, fields map[string]string) *cliListFilters {
+	f := &cliListFilters{
+		query:    fs.String("query", "", "recherche, même syntaxe que la barre de recherche (ex. 'condition>=4 box:yes')"),
+		wishlist: fs.Bool("wishlist", false, "liste les éléments souhaités au lieu des éléments possédés"),
+		fields:   make(map[string]*string),
+	}
+	for field, help := range fields {
+		f.fields[field] = fs.String(field, "", help)
+	}
+	return f
+}
+
+// searchQuery combines the options into a single search query
+func (f *cliListFilters) searchQuery() string {
+	terms := []string{"owned:" + strconv.FormatBool(!*f.wishlist)}
+	for _, field := range slices.Sorted(maps.Keys(f.fields)) {
+		if value := strings.TrimSpace(*f.fields[field]); value != "" {
+			terms = append(terms, queryTermText(field, value))
+		}
+	}
+	if *f.query != "" {
+		terms = append(terms, *f.query)
+	}
+	return strings.Join(terms, " ")
+}
+
+// cliListFields are the search fields offered as options by each list command
+var cliListFields = map[string]map[string]string{
+	"game": {
+		"console": "plateforme",
+		"genre":   "genre",
+		"play":    "progression (à faire, en cours, terminé, abandonné...)",
+	},
+	"console": {
+		"manufacturer": "fabricant",
+		"type":         "type de console",
+	},
+	"accessory": {
+		"console":      "plateforme compatible",
+		"manufacturer": "fabricant",
+		"type":         "type d'accessoire",
+	},
+}
+
+// runListCommand prints the owned (or wanted) games, consoles or accessories matching the options
+func runListCommand(name, entityType string, args []string, stdout, stderr io.Writer) error {
+	fs := newCLIFlags(name, "", stderr)
+	format := fs.String("format", cliTable, "format de sortie: table, json ou csv")
+	filters := newCLIListFilters(fs, cliListFields[entityType])
+	positional, err := parseCLIFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) > 0 {
+		return usageErrorf("%s: argument inattendu '%s'", name, positional[0])
+	}
+	if err := checkCLIFormat(*format, cliTable, cliJSON, cliCSV); err != nil {
+		return err
+	}
+
+	store, err := openStore()
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	table, err := loadCLIList(store, entityType, filters.searchQuery(), *filters.wishlist)
+	if err != nil {
+		return err
+	}
+	return writeCLIOutput(stdout, *format, table)
+}
+
+// loadCLIList runs a search and builds the table of the list command
+func loadCLIList(store CollectionStore, entityType, query string, wanted bool) (*exportTable, error) {
+	switch entityType {
+	case "game":
+		games, err := store.SearchGames(query)
+		if err != nil {
+			return nil, err
+		}
+		columns := cliGameColumns
+		if wanted {
+			columns = cliWantedGameColumns
+		}
+		return newExportTable("jeux", columns, pointers(games)), nil
+	case "console":
+		consoles, err := store.SearchConsoles(query)
+		if err != nil {
+			return nil, err
+		}
+		columns := cliConsoleColumns
+		if wanted {
+			columns = cliWantedConsoleColumns
+		}
+		return newExportTable("consoles", columns, pointers(consoles)), nil
+	default:
+		accessories, err := store.SearchAccessories(query)
+		if err != nil {
+			return nil, err
+		}
+		columns := cliAccessoryColumns
+		if wanted {
+			columns = cliWantedAccessoryColumns
+		}
+		return newExportTable("accessoires", columns, pointers(accessories)), nil
+	}
+}
+
+// pointers returns a pointer to each element of a slice
+func pointers[T any](items []T) []*T {
+	result := make([]*T, len(items))
+	for i := range items {
+		result[i] = &items[i]
+	}
+	return result
+}
+
+// ========== Details ==========
+
+// runShowCommand prints every detail of one game, console or accessory, as exported
+func runShowCommand(name, entityType string, args []string, stdout, stderr io.Writer) error {
+	fs := newCLIFlags(name, " <id>", stderr)
+	format := fs.String("format", cliTable, "format de sortie: table, json ou csv")
+	positional, err := parseCLIFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return usageErrorf("%s: un identifiant attendu", name)
+	}
+	id, err := strconv.Atoi(positional[0])
+	if err != nil {
+		return usageErrorf("%s: identifiant invalide '%s'", name, positional[0])
+	}
+	if err := checkCLIFormat(*format, cliTable, cliJSON, cliCSV); err != nil {
+		return err
+	}
+
+	store, err := openStore()
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	table, err := loadExportTable(store, entityType, []int{id})
+	if errors.Is(err, errNoRows) {
+		return fmt.Errorf("%s %d introuvable", strings.ToLower(wishlistItemTypeLabels[entityType]), id)
+	}
+	if err != nil {
+		return err
+	}
+	if *format == cliTable {
+		return writeCLIDetails(stdout, table)
+	}
+	return writeCLIOutput(stdout, *format, table)
+}
+
+// ========== Adding a game ==========
+
+// cliGameOptions maps the options of "games add" to the import fields they fill
+var cliGameOptions = []struct {
+	Option string
+	Field  string
+	Help   string
+}{
+	{"title", "title", "titre (requis)"},
+	{"console", "console", "plateforme (requise)"},
+	{"genre", "genre", "genre"},
+	{"developers", "developers", "développeur(s), séparés par des virgules"},
+	{"publishers", "publishers", "distributeur(s), séparés par des virgules"},
+	{"owned", "owned", "possédé: oui ou non (oui par défaut)"},
+	{"box", "box_owned", "boîte: oui ou non"},
+	{"collector", "collector", "édition collector: oui ou non"},
+	{"condition", "condition", "état, de 1 à 5"},
+	{"date", "purchase_date", "date d'achat (AAAA-MM-JJ)"},
+	{"price", "purchase_price", "prix d'achat"},
+	{"target-price", "target_price", "prix cible d'un jeu souhaité"},
+	{"priority", "wish_priority", "priorité d'un jeu souhaité: haute, moyenne ou basse"},
+	{"edition", "wish_edition", "région ou édition recherchée"},
+	{"notes", "notes", "notes"},
+}
+
+// runAddGameCommand adds a game from the options
+// The options are read like the cells of an imported row, with the same checks.
+func runAddGameCommand(name string, args []string, stdout, stderr io.Writer) error {
+	fs := newCLIFlags(name, "", stderr)
+	values := make([]*string, len(cliGameOptions))
+	for i, option := range cliGameOptions {
+		values[i] = fs.String(option.Option, "", option.Help)
+	}
+	createMissing := fs.Bool("create-missing", false, "crée la plateforme, le genre... s'ils n'existent pas encore")
+	positional, err := parseCLIFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) > 0 {
+		return usageErrorf("%s: argument inattendu '%s'", name, positional[0])
+	}
+
+	file := &importFile{Rows: [][]string{nil}, Lines: []int{1}}
+	for i, option := range cliGameOptions {
+		if *values[i] != "" {
+			file.Header = append(file.Header, option.Field)
+			file.Rows[0] = append(file.Rows[0], *values[i])
+		}
+	}
+
+	store, err := openStore()
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	lookups, err := loadImportLookups(store)
+	if err != nil {
+		return fmt.Errorf("échec de chargement des listes de référence: %w", err)
+	}
+	preview := prepareImport("game", file, file.Header, lookups)
+	row := &preview.Rows[0]
+	if len(row.Errors) > 0 {
+		return errors.New(strings.Join(row.Errors, "; "))
+	}
+	if !row.valid(*createMissing) {
+		var names []string
+		for _, m := range row.Missing {
+			names = append(names, fmt.Sprintf("%s '%s'", lookupKindLabels[m.Kind], m.Name))
+		}
+		return fmt.Errorf("inconnu(s): %s (--create-missing pour les créer)", strings.Join(names, ", "))
+	}
+
+	if err := store.ImportCollection(preview.batch(*createMissing)); err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "Jeu ajouté: %s (%s)\n", row.game.Title, row.game.ConsoleName)
+	return nil
+}
+
+// ========== Export and import ==========
+
+// runExportCommand writes the collection, or the matching rows of one type, like "Fichier > Exporter"
+func runExportCommand(args []string, stdout, stderr io.Writer) error {
+	fs := newCLIFlags("export", "", stderr)
+	format := fs.String("format", "json", "format: csv, json ou xlsx")
+	typeName := fs.String("type", "", "n'exporte qu'un type: game, console ou accessory (requis en CSV)")
+	query := fs.String("query", "", "n'exporte que les éléments trouvés par cette recherche (avec --type)")
+	output := fs.String("output", "", "fichier à écrire (sortie standard par défaut)")
+	positional, err := parseCLIFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) > 0 {
+		return usageErrorf("export: argument inattendu '%s'", positional[0])
+	}
+
+	exportFormat := strings.ToUpper(*format)
+	if !slices.Contains(exportFormats, exportFormat) {
+		return usageErrorf("format inconnu '%s' (csv, json ou xlsx attendu)", *format)
+	}
+	entityType := ""
+	if *typeName != "" {
+		var known bool
+		if entityType, known = cliEntityTypes[strings.ToLower(*typeName)]; !known {
+			return usageErrorf("type inconnu '%s' (game, console ou accessory attendu)", *typeName)
+		}
+	}
+	if entityType == "" && exportFormat == exportCSV {
+		return usageErrorf("export: un fichier CSV ne contient qu'un type, précisez --type")
+	}
+	if entityType == "" && *query != "" {
+		return usageErrorf("export: --query s'applique à un type, précisez --type")
+	}
+
+	store, err := openStore()
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	// Load and encode first so nothing is created on failure
+	var tables []*exportTable
+	if entityType == "" {
+		tables, err = loadCollectionExport(store)
+	} else {
+		var ids []int
+		if *query != "" {
+			if ids, err = searchIDs(store, entityType, *query); err != nil {
+				return err
+			}
+		}
+		var table *exportTable
+		table, err = loadExportTable(store, entityType, ids)
+		tables = []*exportTable{table}
+	}
+	if err != nil {
+		return fmt.Errorf("échec de l'export: %w", err)
+	}
+
+	var buf bytes.Buffer
+	switch exportFormat {
+	case exportJSON:
+		err = writeExportJSON(&buf, tables)
+	case exportXLSX:
+		err = writeExportXLSX(&buf, tables)
+	default:
+		err = writeExportCSV(&buf, tables[0])
+	}
+	if err != nil {
+		return fmt.Errorf("échec de l'export: %w", err)
+	}
+
+	if *output == "" {
+		_, err = stdout.Write(buf.Bytes())
+		return err
+	}
+	if err := os.WriteFile(*output, buf.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("échec d'écriture du fichier: %w", err)
+	}
+	fmt.Fprintf(stdout, "%s exporté(s) dans %s\n", exportRowCount(tables), *output)
+	return nil
+}
+
+// searchIDs returns the IDs of the rows found by a search, never nil
+func searchIDs(store CollectionStore, entityType, query string) ([]int, error) {
+	ids := []int{}
+	switch entityType {
+	case "game":
+		games, err := store.SearchGames(query)
+		if err != nil {
+			return nil, err
+		}
+		for _, g := range games {
+			ids = append(ids, g.GameID)
+		}
+	case "console":
+		consoles, err := store.SearchConsoles(query)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range consoles {
+			ids = append(ids, c.ConsoleID)
+		}
+	default:
+		accessories, err := store.SearchAccessories(query)
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range accessories {
+			ids = append(ids, a.AccessoryID)
+		}
+	}
+	return ids, nil
+}
+
+// runImportCommand imports a CSV or XLSX file like the "Importer" button
+// Columns are matched automatically; rows with errors are reported and skipped.
+func runImportCommand(args []string, stdout, stderr io.Writer) error {
+	fs := newCLIFlags("import", " <fichier>", stderr)
+	typeName := fs.String("type", "", "type des lignes: game, console ou accessory (requis)")
+	createMissing := fs.Bool("create-missing", false, "crée les plateformes, genres, fabricants... qui n'existent pas encore")
+	dryRun := fs.Bool("dry-run", false, "vérifie le fichier sans rien enregistrer")
+	positional, err := parseCLIFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return usageErrorf("import: un fichier attendu")
+	}
+	entityType, known := cliEntityTypes[strings.ToLower(*typeName)]
+	if !known {
+		return usageErrorf("import: --type game, console ou accessory requis")
+	}
+
+	file, err := readImportFile(positional[0])
+	if err != nil {
+		return fmt.Errorf("import impossible: %w", err)
+	}
+
+	// Every required field needs a column
+	fields := importFieldsFor(entityType)
+	mapping := autoMapColumns(file.Header, fields)
+	var missing []string
+	for _, field := range fields {
+		if field.Required && !slices.Contains(mapping, field.Key) {
+			missing = append(missing, field.Label)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("import impossible, colonne(s) introuvable(s): %s", strings.Join(missing, ", "))
+	}
+
+	store, err := openStore()
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	lookups, err := loadImportLookups(store)
+	if err != nil {
+		return fmt.Errorf("échec de chargement des listes de référence: %w", err)
+	}
+	preview := prepareImport(entityType, file, mapping, lookups)
+	for i := range preview.Rows {
+		row := &preview.Rows[i]
+		if !row.valid(*createMissing) || len(row.Missing) > 0 {
+			fmt.Fprintf(stderr, "ligne %d (%s): %s\n", row.Line, row.Label, strings.ReplaceAll(importRowDetails(row, *createMissing), "\n", " "))
+		}
+	}
+
+	valid := preview.validCount(*createMissing)
+	if *dryRun {
+		fmt.Fprintf(stdout, "%d ligne(s) à importer, %d ignorée(s).\n", valid, len(preview.Rows)-valid)
+		return nil
+	}
+	if err := store.ImportCollection(preview.batch(*createMissing)); err != nil {
+		return fmt.Errorf("import annulé, aucune ligne enregistrée: %w", err)
+	}
+	fmt.Fprintf(stdout, "%d ligne(s) importée(s), %d ignorée(s).\n", valid, len(preview.Rows)-valid)
+	return nil
+}
+
+// ========== Statistics ==========
+
+// runStatsCommand prints the figures of the dashboard
+func runStatsCommand(args []string, stdout, stderr io.Writer) error {
+	fs := newCLIFlags("stats", "", stderr)
+	format := fs.String("format", cliTable, "format de sortie: table ou json")
+	positional, err := parseCLIFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) > 0 {
+		return usageErrorf("stats: argument inattendu '%s'", positional[0])
+	}
+	if err := checkCLIFormat(*format, cliTable, cliJSON); err != nil {
+		return err
+	}
+
+	store, err := openStore()
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	stats, err := store.GetDashboardStats()
+	if err != nil {
+		return err
+	}
+	if *format == cliJSON {
+		encoder := json.NewEncoder(stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(stats)
+	}
+	return writeCLIStats(stdout, stats, time.Now())
+}
+
+// writeCLIStats prints the dashboard as text, with the labels of the Accueil tab
+func writeCLIStats(w io.Writer, stats *DashboardStats, today time.Time) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "Jeux possédés:\t%d\n", stats.GamesOwned)
+	fmt.Fprintf(tw, "Consoles possédées:\t%d\n", stats.ConsolesOwned)
+	fmt.Fprintf(tw, "Accessoires possédés:\t%d\n", stats.AccessoriesOwned)
+	fmt.Fprintf(tw, "Dépenses totales:\t%.2f\n", stats.TotalSpent)
+	fmt.Fprintf(tw, "Valeur estimée (%d élément(s) cotés):\t%.2f\n", stats.ValuedItems, stats.CollectionValue)
+	fmt.Fprintf(tw, "Plus-value:\t%+.2f\n", stats.ValueGain)
+	fmt.Fprintf(tw, "Jeux à faire:\t%d\n", stats.Backlog)
+	fmt.Fprintf(tw, "Jeux en cours:\t%d\n", stats.Playing)
+	fmt.Fprintf(tw, "Heures de jeu:\t%.0f\n", stats.HoursPlayed)
+	fmt.Fprintf(tw, "Avec boîte:\t%d\n", stats.GamesWithBox)
+	fmt.Fprintf(tw, "Édition collector:\t%d\n", stats.GamesCollector)
+
+	section := func(title string) {
+		fmt.Fprintf(tw, "\n%s\n", title)
+	}
+	labelOrNotSet := func(label string) string {
+		if label == "" {
+			return notSetLabel
+		}
+		return label
+	}
+
+	if len(stats.GamesPerConsole) > 0 {
+		section("Jeux par plateforme")
+		for _, lc := range stats.GamesPerConsole {
+			fmt.Fprintf(tw, "  %s\t%d\n", labelOrNotSet(lc.Label), lc.Count)
+		}
+	}
+	if len(stats.GamesPerGenre) > 0 {
+		section("Jeux par genre")
+		for _, lc := range stats.GamesPerGenre {
+			fmt.Fprintf(tw, "  %s\t%d\n", labelOrNotSet(lc.Label), lc.Count)
+		}
+	}
+	if len(stats.CompletionRates) > 0 {
+		section("Jeux terminés par plateforme")
+		for _, r := range stats.CompletionRates {
+			fmt.Fprintf(tw, "  %s\t%d/%d\n", labelOrNotSet(r.Label), r.Finished, r.Total)
+		}
+	}
+	if len(stats.OverdueLoans) > 0 {
+		section("Prêts en retard")
+		for i := range stats.OverdueLoans {
+			loan := &stats.OverdueLoans[i]
+			fmt.Fprintf(tw, "  %s\t%s\n", loan.ItemName, loanDescription(loan, today))
+		}
+	}
+	return tw.Flush()
+}
+
+// ========== API server ==========
+
+// runServeCommand serves the REST API of api.go until interrupted
+func runServeCommand(args []string, stderr io.Writer) error {
+	fs := newCLIFlags("serve", "", stderr)
+	addr := fs.String("addr", "localhost:8080", "adresse d'écoute (\"localhost:8080\" cette machine seulement, \":8080\" le réseau local, avec un jeton)")
+	token := fs.String("token", "", "jeton exigé dans l'en-tête \"Authorization: Bearer\" (API_TOKEN du .env par défaut)")
+	positional, err := parseCLIFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) > 0 {
+		return usageErrorf("serve: argument inattendu '%s'", positional[0])
+	}
+
+	store, err := openStore()
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	// The API can modify the whole collection: without a token it stays on this machine
+	if *token == "" {
+		*token = os.Getenv("API_TOKEN")
+	}
+	if *token == "" && !isLoopbackAddr(*addr) {
+		return fmt.Errorf("serve: sans jeton, l'API n'écoute que sur cette machine (--addr localhost:8080); définissez --token ou API_TOKEN pour l'ouvrir au réseau")
+	}
+
+	log.Printf("API disponible sur http://%s/api/ (description: /api/openapi.json)", *addr)
+	return listenAndServe(*addr, logAPIRequests(newAPIHandler(store, *token)))
+}
+
+// isLoopbackAddr tells whether a listen address only accepts connections from this machine
+// ":8080" listens on every interface.
+func isLoopbackAddr(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil || host == "" {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// listenAndServe serves handler on addr until Ctrl+C or SIGTERM
+func listenAndServe(addr string, handler http.Handler) error {
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	// Stop cleanly on Ctrl+C so running requests finish and the database is closed
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	}()
+
+	if err := server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// ========== Web catalogue ==========
+
+// runCatalogueCommand serves the read-only catalogue of catalogue.go until interrupted
+func runCatalogueCommand(args []string, stderr io.Writer) error {
+	fs := newCLIFlags("catalogue", "", stderr)
+	addr := fs.String("addr", ":8081", "adresse d'écoute (\":8081\" accepte le réseau local, \"localhost:8081\" cette machine seulement)")
+	positional, err := parseCLIFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) > 0 {
+		return usageErrorf("catalogue: argument inattendu '%s'", positional[0])
+	}
+
+	store, err := openStore()
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	log.Printf("Catalogue disponible sur http://%s/", *addr)
+	return listenAndServe(*addr, logAPIRequests(newCatalogueHandler(store)))
+}
+
+// runPublishCommand writes the catalogue as a static site
+func runPublishCommand(args []string, stdout, stderr io.Writer) error {
+	fs := newCLIFlags("publish", "", stderr)
+	output := fs.String("output", "catalogue", "dossier du site (créé au besoin, ses dossiers games et covers sont remplacés)")
+	positional, err := parseCLIFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) > 0 {
+		return usageErrorf("publish: argument inattendu '%s'", positional[0])
+	}
+
+	store, err := openStore()
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	count, err := publishCatalogue(store, *output, time.Now())
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "%d jeu(x) publié(s) dans %s\n", count, filepath.Join(*output, "index.html"))
+	return nil
+}
+
+// ========== Backups ==========
+
+// runBackupCommand writes a backup into BACKUP_DIR (oldest ones rotated out) or to --output
+func runBackupCommand(args []string, stdout, stderr io.Writer) error {
+	fs := newCLIFlags("backup", "", stderr)
+	output := fs.String("output", "", "fichier de la sauvegarde (par défaut un fichier daté dans BACKUP_DIR, où seules les BACKUP_KEEP plus récentes sont gardées)")
+	positional, err := parseCLIFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) > 0 {
+		return usageErrorf("backup: argument inattendu '%s'", positional[0])
+	}
+
+	store, err := openStore()
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	path := *output
+	var manifest *BackupManifest
+	if path == "" {
+		path, manifest, err = createBackup(store, loadBackupSettings())
+	} else {
+		manifest, err = writeBackupFile(store, path)
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "Sauvegarde écrite dans %s (%s)\n", path, backupSummary(manifest))
+	return nil
+}
+
+// runRestoreCommand replaces the collection with a backup once confirmed by --yes
+func runRestoreCommand(args []string, stdout, stderr io.Writer) error {
+	fs := newCLIFlags("restore", " <fichier>", stderr)
+	confirmed := fs.Bool("yes", false, "remplace vraiment les données (sans cette option, décrit seulement la sauvegarde)")
+	positional, err := parseCLIFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return usageErrorf("restore: un fichier de sauvegarde attendu")
+	}
+
+	archive, manifest, err := openBackup(positional[0])
+	if err != nil {
+		return err
+	}
+	archive.Close()
+	fmt.Fprintf(stdout, "Sauvegarde du %s (%s, schéma %d): %s\n",
+		manifest.CreatedAt.Local().Format("02/01/2006 15:04"), manifest.Driver, manifest.SchemaVersion, backupSummary(manifest))
+	if !*confirmed {
+		return errors.New("rien n'a été restauré: ajoutez --yes pour remplacer toutes les données actuelles")
+	}
+
+	store, err := openStore()
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	safetyPath, err := restoreBackupFile(store, loadBackupSettings(), positional[0])
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "Collection restaurée (données précédentes sauvegardées dans %s)\n", safetyPath)
+	return nil
+}
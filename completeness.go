@@ -0,0 +1,290 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// ========== COMPLETENESS ==========
+// Each copy of a game has a checklist of the parts of the original release it still has
+// (cartridge, box, manual, map, inserts, seal...). The parts are configured once, for every
+// game or for the games of one type of console, and give the status of the copy, which is
+// also the grade it is valued with: loose, complete in box (CIB) or new (sealed).
+
+// completenessLabels names the statuses in the UI
+var completenessLabels = map[string]string{
+	GradeLoose: "Loose",
+	GradeCIB:   "CIB",
+	GradeNew:   "Neuf scellé",
+}
+
+// completenessStatuses lists the statuses from the least to the most complete
+var completenessStatuses = []string{GradeLoose, GradeCIB, GradeNew}
+
+// componentRoleLabels names the roles in the configuration dialog
+var componentRoleLabels = map[string]string{
+	ComponentMedia:  "Support (cartouche, disque)",
+	ComponentBox:    "Boîte",
+	ComponentManual: "Notice",
+	ComponentSeal:   "Scellé",
+	ComponentOther:  "Autre",
+}
+
+var componentRoles = []string{ComponentMedia, ComponentBox, ComponentManual, ComponentSeal, ComponentOther}
+
+// componentApplies tells whether a component is part of the games of a console type
+func componentApplies(c *CompletenessComponent, consoleTypeID *int) bool {
+	return c.ConsoleTypeID == nil || (consoleTypeID != nil && *c.ConsoleTypeID == *consoleTypeID)
+}
+
+// applicableComponents returns the components of the games of a console type
+func applicableComponents(components []CompletenessComponent, consoleTypeID *int) []CompletenessComponent {
+	var result []CompletenessComponent
+	for i := range components {
+		if componentApplies(&components[i], consoleTypeID) {
+			result = append(result, components[i])
+		}
+	}
+	return result
+}
+
+// copyCompleteness computes the status of a copy and the components it misses
+// components are those that apply to the game; present holds the IDs the copy has.
+func copyCompleteness(components []CompletenessComponent, present map[int]bool) (string, []string) {
+	for _, c := range components {
+		if c.Role == ComponentSeal && present[c.ComponentID] {
+			return GradeNew, nil
+		}
+	}
+
+	status := GradeCIB
+	var missing []string
+	for _, c := range components {
+		if c.Role == ComponentSeal || present[c.ComponentID] {
+			continue
+		}
+		missing = append(missing, c.Name)
+		if c.RequiredForCIB {
+			status = GradeLoose
+		}
+	}
+	return status, missing
+}
+
+// bestCompleteness returns the most complete of two statuses ("" counts as none)
+func bestCompleteness(a, b string) string {
+	if slices.Index(completenessStatuses, b) > slices.Index(completenessStatuses, a) {
+		return b
+	}
+	return a
+}
+
+// missingComponentsText lists what a copy misses, for the detail dialog
+func missingComponentsText(missing []string) string {
+	if len(missing) == 0 {
+		return "rien ne manque"
+	}
+	return "manque " + strings.Join(missing, ", ")
+}
+
+// ========== SQL ==========
+// The same rules in SQL, for the completeness: and missing: search fields of the games list
+// (g is the game and c its console).
+
+// componentAppliesSQL is componentApplies for the component alias cc
+func componentAppliesSQL(cc string) string {
+	return fmt.Sprintf("(%[1]s.console_type_id IS NULL OR %[1]s.console_type_id = c.type_id)", cc)
+}
+
+// copySealedSQL tells whether the copy alias gc has a seal component checked
+// alias is the prefix of the aliases used by the subquery.
+func copySealedSQL(gc, alias string) string {
+	return fmt.Sprintf(`EXISTS (SELECT 1 FROM copy_components %[2]sp
+		JOIN completeness_components %[2]sc ON %[2]sp.component_id = %[2]sc.component_id
+		WHERE %[2]sp.copy_id = %[1]s.copy_id AND %[2]sc.role = 'seal' AND %[3]s)`, gc, alias, componentAppliesSQL(alias+"c"))
+}
+
+// gameCompletenessSQL computes the completeness of a game, the best of its copies
+// Statuses are named by names, or by their key when names is nil; "" without copies.
+func gameCompletenessSQL(names map[string]string) string {
+	name := func(status string) string {
+		if names == nil {
+			return "'" + status + "'"
+		}
+		return "'" + strings.ReplaceAll(names[status], "'", "''") + "'"
+	}
+	return fmt.Sprintf(`(CASE
+		WHEN EXISTS (SELECT 1 FROM game_copies qsg WHERE qsg.game_id = g.game_id AND %[1]s) THEN %[2]s
+		WHEN EXISTS (SELECT 1 FROM game_copies qsg WHERE qsg.game_id = g.game_id AND NOT EXISTS (
+			SELECT 1 FROM completeness_components qsr
+			WHERE qsr.required_for_cib AND qsr.role <> 'seal' AND %[5]s
+			AND NOT EXISTS (SELECT 1 FROM copy_components qsp WHERE qsp.copy_id = qsg.copy_id AND qsp.component_id = qsr.component_id))) THEN %[3]s
+		WHEN EXISTS (SELECT 1 FROM game_copies qsg WHERE qsg.game_id = g.game_id) THEN %[4]s
+		ELSE '' END)`, copySealedSQL("qsg", "qss"), name(GradeNew), name(GradeCIB), name(GradeLoose), componentAppliesSQL("qsr"))
+}
+
+// ========== Configuration dialog ==========
+
+// showCompletenessComponentsDialog lists the components with buttons to add, edit and remove them
+func showCompletenessComponentsDialog(w fyne.Window, store CollectionStore, onChanged func()) {
+	list := container.NewVBox()
+
+	var reload func()
+	reload = func() {
+		changed := func() {
+			reload()
+			if onChanged != nil {
+				onChanged()
+			}
+		}
+
+		components, err := store.GetCompletenessComponents()
+		if err != nil {
+			list.Objects = []fyne.CanvasObject{widget.NewLabel(fmt.Sprintf("Échec de chargement: %v", err))}
+			list.Refresh()
+			return
+		}
+
+		list.Objects = nil
+		for _, c := range components {
+			c := c
+			scope := "Tous les jeux"
+			if c.ConsoleTypeID != nil {
+				scope = "Consoles: " + c.ConsoleTypeName
+			}
+			text := fmt.Sprintf("%s — %s — %s", c.Name, componentRoleLabels[c.Role], scope)
+			if c.RequiredForCIB {
+				text += " — requis pour CIB"
+			}
+			label := widget.NewLabel(text)
+			label.Truncation = fyne.TextTruncateEllipsis
+
+			editBtn := widget.NewButton("Éditer", func() {
+				showCompletenessComponentForm(w, store, c, changed)
+			})
+			deleteBtn := widget.NewButton("Supprimer", func() {
+				dialog.ShowConfirm("Supprimer l'élément",
+					fmt.Sprintf("Retirer « %s » de la liste? Les exemplaires qui l'ont coché le perdent.", c.Name),
+					func(confirmed bool) {
+						if !confirmed {
+							return
+						}
+						if err := store.DeleteCompletenessComponent(c.ComponentID); err != nil {
+							dialog.ShowError(err, w)
+							return
+						}
+						changed()
+					}, w)
+			})
+			deleteBtn.Importance = widget.DangerImportance
+
+			list.Add(container.NewBorder(nil, nil, nil, container.NewHBox(editBtn, deleteBtn), label))
+		}
+		if len(components) == 0 {
+			list.Add(widget.NewLabelWithStyle("Aucun élément", fyne.TextAlignLeading, fyne.TextStyle{Italic: true}))
+		}
+		list.Refresh()
+	}
+	reload()
+
+	intro := widget.NewLabel("Éléments cochés pour chaque exemplaire. Un exemplaire est CIB quand il a tous les éléments requis, neuf quand il a un élément « Scellé ».")
+	intro.Wrapping = fyne.TextWrapWord
+
+	var d dialog.Dialog
+	addBtn := widget.NewButton("Ajouter un élément", func() {
+		showCompletenessComponentForm(w, store, CompletenessComponent{Role: ComponentOther, Position: len(list.Objects) + 1}, func() {
+			reload()
+			if onChanged != nil {
+				onChanged()
+			}
+		})
+	})
+	addBtn.Importance = widget.HighImportance
+	closeBtn := widget.NewButton("Fermer", func() { d.Hide() })
+
+	d = dialog.NewCustomWithoutButtons("Éléments de complétude",
+		container.NewBorder(intro, container.NewCenter(container.NewHBox(addBtn, closeBtn)), nil, nil, container.NewVScroll(list)), w)
+	d.Resize(fyne.NewSize(750, 500))
+	d.Show()
+}
+
+// showCompletenessComponentForm adds (ComponentID == 0) or edits a component
+func showCompletenessComponentForm(w fyne.Window, store CollectionStore, component CompletenessComponent, onSaved func()) {
+	consoleTypes, err := store.GetConsoleTypes()
+	if err != nil {
+		dialog.ShowError(err, w)
+		return
+	}
+
+	const allTypes = "Tous les jeux"
+	typeOptions := []string{allTypes}
+	typeIDs := make(map[string]int)
+	for _, t := range consoleTypes {
+		typeOptions = append(typeOptions, t.Name)
+		typeIDs[t.Name] = t.TypeID
+	}
+	typeSelect := widget.NewSelect(typeOptions, nil)
+	typeSelect.SetSelected(allTypes)
+	for _, t := range consoleTypes {
+		if component.ConsoleTypeID != nil && t.TypeID == *component.ConsoleTypeID {
+			typeSelect.SetSelected(t.Name)
+		}
+	}
+
+	var roleOptions []string
+	for _, role := range componentRoles {
+		roleOptions = append(roleOptions, componentRoleLabels[role])
+	}
+	roleSelect := widget.NewSelect(roleOptions, nil)
+	roleSelect.SetSelected(componentRoleLabels[component.Role])
+
+	nameEntry := widget.NewEntry()
+	nameEntry.SetText(component.Name)
+	requiredCheck := widget.NewCheck("Requis pour être complet (CIB)", nil)
+	requiredCheck.Checked = component.RequiredForCIB
+
+	title := "Modifier l'élément"
+	if component.ComponentID == 0 {
+		title = "Ajouter un élément"
+	}
+
+	dialog.ShowForm(title, "Enregistrer", "Annuler",
+		[]*widget.FormItem{
+			widget.NewFormItem("Nom", nameEntry),
+			widget.NewFormItem("Rôle", roleSelect),
+			widget.NewFormItem("Jeux des consoles", typeSelect),
+			widget.NewFormItem("", requiredCheck),
+		},
+		func(confirmed bool) {
+			if !confirmed {
+				return
+			}
+			component.Name = strings.TrimSpace(nameEntry.Text)
+			if component.Name == "" {
+				dialog.ShowError(fmt.Errorf("nom requis"), w)
+				return
+			}
+			for _, role := range componentRoles {
+				if componentRoleLabels[role] == roleSelect.Selected {
+					component.Role = role
+				}
+			}
+			component.ConsoleTypeID = nil
+			if id, found := typeIDs[typeSelect.Selected]; found {
+				component.ConsoleTypeID = &id
+			}
+			component.RequiredForCIB = requiredCheck.Checked
+
+			if _, err := store.SaveCompletenessComponent(&component); err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			onSaved()
+		}, w)
+}
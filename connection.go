@@ -0,0 +1,117 @@
+package main
+
+import (
+	"log"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// ========== CONNECTION STATUS ==========
+
+// connectionCheckInterval is how often the status indicator pings the database
+const connectionCheckInterval = 10 * time.Second
+
+// createConnectionIndicator shows a colored dot and a label telling whether the database answers
+// The check runs in the background; onReconnect is called when the connection comes back
+// after an outage so the tabs can reload their data.
+func createConnectionIndicator(store CollectionStore, onReconnect func()) fyne.CanvasObject {
+	dot := canvas.NewCircle(theme.Color(theme.ColorNameSuccess))
+	label := widget.NewLabel("Base de données connectée")
+
+	setConnected := func(connected bool) {
+		if connected {
+			dot.FillColor = theme.Color(theme.ColorNameSuccess)
+			label.SetText("Base de données connectée")
+		} else {
+			dot.FillColor = theme.Color(theme.ColorNameError)
+			label.SetText("Base de données injoignable - reconnexion automatique...")
+		}
+		dot.Refresh()
+	}
+
+	go func() {
+		connected := true
+		ticker := time.NewTicker(connectionCheckInterval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			err := store.Ping()
+			if (err == nil) == connected {
+				continue
+			}
+
+			// Only state changes are logged and shown
+			connected = err == nil
+			if connected {
+				log.Println("Database connection restored")
+			} else {
+				log.Println("Database unreachable:", err)
+			}
+
+			fyne.Do(func() {
+				setConnected(connected)
+				if connected && onReconnect != nil {
+					onReconnect()
+				}
+			})
+		}
+	}()
+
+	// Give the circle a fixed size, centered vertically next to the label
+	dotContainer := container.NewCenter(container.NewGridWrap(fyne.NewSize(10, 10), dot))
+	return container.NewHBox(dotContainer, label)
+}
+
+// showConnectionErrorScreen replaces the window content when the database cannot be reached
+// at startup, offering to retry instead of exiting. onConnected receives the store once
+// a retry succeeds.
+func showConnectionErrorScreen(w fyne.Window, err error, onConnected func(CollectionStore)) {
+	title := widget.NewLabelWithStyle("Connexion à la base de données impossible", fyne.TextAlignCenter, fyne.TextStyle{Bold: true})
+
+	errorLabel := widget.NewLabel(err.Error())
+	errorLabel.Wrapping = fyne.TextWrapWord
+	errorLabel.Alignment = fyne.TextAlignCenter
+
+	hint := widget.NewLabelWithStyle("Vérifiez que le serveur est démarré et les paramètres du fichier .env.", fyne.TextAlignCenter, fyne.TextStyle{Italic: true})
+
+	quitBtn := widget.NewButton("Quitter", func() {
+		fyne.CurrentApp().Quit()
+	})
+
+	retryBtn := widget.NewButton("Réessayer", nil)
+	retryBtn.Importance = widget.HighImportance
+	retryBtn.OnTapped = func() {
+		retryBtn.Disable()
+		retryBtn.SetText("Connexion...")
+
+		// Connecting can take a few seconds, keep the window responsive
+		go func() {
+			store, err := openStore()
+			fyne.Do(func() {
+				if err != nil {
+					log.Println(err)
+					errorLabel.SetText(err.Error())
+					retryBtn.SetText("Réessayer")
+					retryBtn.Enable()
+					return
+				}
+				onConnected(store)
+			})
+		}()
+	}
+
+	// Fixed width so long error messages wrap instead of stretching the window
+	message := container.NewGridWrap(fyne.NewSize(600, 120), errorLabel)
+
+	w.SetContent(container.NewCenter(container.NewVBox(
+		title,
+		message,
+		hint,
+		container.NewCenter(container.NewHBox(quitBtn, retryBtn)),
+	)))
+}
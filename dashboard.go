@@ -0,0 +1,155 @@
+package main
+
+import (
+	"fmt"
+	"math"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// ========== DASHBOARD ==========
+
+// buildDashboardTab creates the "Accueil" tab from the collection figures
+func buildDashboardTab(stats *DashboardStats) fyne.CanvasObject {
+	// Totals
+	totals := container.NewGridWithColumns(3,
+		createStatCard("Jeux possédés", fmt.Sprintf("%d", stats.GamesOwned)),
+		createStatCard("Consoles possédées", fmt.Sprintf("%d", stats.ConsolesOwned)),
+		createStatCard("Accessoires possédés", fmt.Sprintf("%d", stats.AccessoriesOwned)),
+		createStatCard("Dépenses totales", fmt.Sprintf("%.2f", stats.TotalSpent)),
+		createStatCard(fmt.Sprintf("Valeur estimée (%d élément(s) cotés)", stats.ValuedItems), fmt.Sprintf("%.2f", stats.CollectionValue)),
+		createStatCard("Plus-value", fmt.Sprintf("%+.2f", stats.ValueGain)),
+		createStatCard("Jeux à faire", fmt.Sprintf("%d", stats.Backlog)),
+		createStatCard("Jeux en cours", fmt.Sprintf("%d", stats.Playing)),
+		createStatCard("Heures de jeu", fmt.Sprintf("%.0f", stats.HoursPlayed)),
+	)
+
+	// Breakdowns
+	perConsole := widget.NewCard("Jeux par plateforme", "", createBarChart(stats.GamesPerConsole))
+	perGenre := widget.NewCard("Jeux par genre", "", createBarChart(stats.GamesPerGenre))
+
+	var conditions []LabelCount
+	for _, cc := range stats.Conditions {
+		label := conditionToStars(cc.Condition)
+		if cc.Condition == nil {
+			label = "Non renseigné"
+		}
+		conditions = append(conditions, LabelCount{Label: label, Count: cc.Count})
+	}
+	conditionCard := widget.NewCard("État de la collection", "Jeux, consoles et accessoires", createBarChart(conditions))
+
+	editions := widget.NewCard("Boîtes et éditions collector", "Part des jeux possédés", container.New(layout.NewFormLayout(),
+		widget.NewLabel("Avec boîte"), createShareBar(stats.GamesWithBox, stats.GamesOwned),
+		widget.NewLabel("Édition collector"), createShareBar(stats.GamesCollector, stats.GamesOwned),
+	))
+
+	recent := widget.NewCard("Achats récents", "", createRecentPurchasesList(stats.RecentPurchases))
+	overdue := widget.NewCard("Prêts en retard", "", createOverdueLoansList(stats.OverdueLoans))
+	completion := widget.NewCard("Jeux terminés par plateforme", "Terminés ou à 100 %, parmi les jeux possédés", createCompletionChart(stats.CompletionRates))
+
+	content := container.NewVBox(
+		totals,
+		container.NewGridWithColumns(2, perConsole, perGenre),
+		container.NewGridWithColumns(2, conditionCard, editions),
+		container.NewGridWithColumns(2, completion, overdue),
+		recent,
+	)
+	return container.NewVScroll(container.NewPadded(content))
+}
+
+// createStatCard shows a single figure in large text
+func createStatCard(title, value string) fyne.CanvasObject {
+	valueLabel := widget.NewLabelWithStyle(value, fyne.TextAlignCenter, fyne.TextStyle{Bold: true})
+	valueLabel.SizeName = theme.SizeNameHeadingText
+	return widget.NewCard("", title, valueLabel)
+}
+
+// createBarChart draws one horizontal bar per entry, scaled on the largest count
+func createBarChart(counts []LabelCount) fyne.CanvasObject {
+	if len(counts) == 0 {
+		return widget.NewLabelWithStyle("Aucune donnée", fyne.TextAlignLeading, fyne.TextStyle{Italic: true})
+	}
+
+	maxCount := 0
+	for _, c := range counts {
+		maxCount = max(maxCount, c.Count)
+	}
+
+	chart := container.New(layout.NewFormLayout())
+	for _, c := range counts {
+		label := c.Label
+		if label == "" {
+			label = "Non renseigné"
+		}
+
+		count := c.Count
+		bar := widget.NewProgressBar()
+		bar.Max = float64(maxCount)
+		bar.SetValue(float64(count))
+		bar.TextFormatter = func() string {
+			return fmt.Sprintf("%d", count)
+		}
+
+		chart.Add(widget.NewLabel(label))
+		chart.Add(bar)
+	}
+	return chart
+}
+
+// createShareBar shows part out of total as a bar with the percentage
+func createShareBar(part, total int) fyne.CanvasObject {
+	bar := widget.NewProgressBar()
+	bar.Max = math.Max(float64(total), 1)
+	bar.SetValue(float64(part))
+	bar.TextFormatter = func() string {
+		if total == 0 {
+			return "0 / 0"
+		}
+		return fmt.Sprintf("%d / %d (%.0f %%)", part, total, float64(part)*100/float64(total))
+	}
+	return bar
+}
+
+// createRecentPurchasesList lists the latest purchases with their date and price
+func createRecentPurchasesList(purchases []RecentPurchase) fyne.CanvasObject {
+	if len(purchases) == 0 {
+		return widget.NewLabelWithStyle("Aucun achat daté", fyne.TextAlignLeading, fyne.TextStyle{Italic: true})
+	}
+
+	list := container.NewVBox()
+	for _, p := range purchases {
+		kind := "Jeu"
+		switch p.ItemType {
+		case "console":
+			kind = "Console"
+		case "accessory":
+			kind = "Accessoire"
+		}
+
+		name := p.Name
+		if p.Detail != "" {
+			name = fmt.Sprintf("%s (%s)", p.Name, p.Detail)
+		}
+
+		price := ""
+		if p.PurchasePrice != nil {
+			price = fmt.Sprintf("%.2f", *p.PurchasePrice)
+		}
+
+		priceLabel := widget.NewLabelWithStyle(price, fyne.TextAlignTrailing, fyne.TextStyle{})
+		row := container.NewBorder(nil, nil,
+			container.NewHBox(
+				widget.NewLabel(p.PurchaseDate.Format("2006-01-02")),
+				widget.NewLabelWithStyle(kind, fyne.TextAlignLeading, fyne.TextStyle{Italic: true}),
+			),
+			priceLabel,
+			widget.NewLabel(name),
+		)
+		list.Add(row)
+	}
+	return list
+}
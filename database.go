@@ -1,63 +1,116 @@
 package main
 
 import (
+	"archive/zip"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"io"
+	"log"
 	"os"
+	"path"
+	"slices"
+	"sort"
 	"strings"
-
-	"github.com/jackc/pgx/v5"
-	"github.com/joho/godotenv"
+	"time"
 )
 
-func dbconnect() (*pgx.Conn, error) {
-	// Load .env file
-	err := godotenv.Load()
-	if err != nil {
-		return nil, fmt.Errorf("error loading .env file: %w", err)
-	}
+// sqlStore is the SQL implementation of CollectionStore shared by the PostgreSQL
+// and SQLite backends (see postgres.go and sqlite.go). Queries stick to portable SQL.
+type sqlStore struct {
+	db      database
+	dialect dialect
+	media   *mediaCache // Files of the images referenced by the images table
+}
 
-	// Make connection string from .env with trimming
-	connStr := fmt.Sprintf(
-		"postgresql://%s:%s@%s:%s/%s",
-		strings.TrimSpace(os.Getenv("DB_USER")),
-		strings.TrimSpace(os.Getenv("DB_PASSWORD")),
-		strings.TrimSpace(os.Getenv("DB_HOST")),
-		strings.TrimSpace(os.Getenv("DB_PORT")),
-		strings.TrimSpace(os.Getenv("DB_NAME")),
-	)
+var _ CollectionStore = (*sqlStore)(nil)
+
+// pingTimeout bounds the connection checks made by the status indicator
+const pingTimeout = 3 * time.Second
+
+// Close releases the database connection
+func (s *sqlStore) Close() {
+	s.db.Close()
+}
 
-	// Connect to database
-	conn, err := pgx.Connect(context.Background(), connStr)
+// Ping checks that the database answers, waiting at most pingTimeout
+func (s *sqlStore) Ping() error {
+	ctx, cancel := context.WithTimeout(context.Background(), pingTimeout)
+	defer cancel()
+	return s.db.Ping(ctx)
+}
+
+// withTx runs fn inside a transaction: everything is committed if fn succeeds,
+// otherwise nothing is written and fn's error is returned
+func (s *sqlStore) withTx(fn func(tx querier) error) error {
+	ctx := context.Background()
+	tx, err := s.db.Begin(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("unable to connect to database: %w", err)
+		return fmt.Errorf("unable to start transaction: %w", err)
 	}
+	defer tx.Rollback(ctx) // No-op once committed
 
-	return conn, nil
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
 }
 
 // ========== Games Functions ==========
 // NOTE: Games has two query types:
-// 1. getGames() - Fast, minimal data for displaying in tables (list view)
-// 2. getGameByID() - Complete data with all relationships for editing (detail view)
+// 1. GetGames() - Fast, minimal data for displaying in tables (list view)
+// 2. GetGameByID() - Complete data with all relationships for editing (detail view)
 // This separation optimizes performance: list queries are fast, detail queries are thorough.
 
-// getGames fetches minimal game data for table display (fast, lightweight)
-func getGames(conn *pgx.Conn) ([]Game, error) {
+// GetGames fetches minimal game data for table display (fast, lightweight)
+// Besides the table columns, it loads what the search bar can filter on.
+func (s *sqlStore) GetGames() ([]Game, error) {
+	return s.listGames("", nil)
+}
+
+// SearchGames returns the games matching a search query (see query.go)
+func (s *sqlStore) SearchGames(query string) ([]Game, error) {
+	filter, err := compileSearchQuery(&gameQuerySchema, query)
+	if err != nil {
+		return nil, err
+	}
+	where, args := filter.SQL(s.dialect, 1)
+	return s.listGames(where, args)
+}
+
+// listGames runs the list query, restricted by an optional WHERE condition
+func (s *sqlStore) listGames(where string, args []any) ([]Game, error) {
+	if where != "" {
+		where = "WHERE " + where
+	}
 	query := `
 		SELECT 
 			g.game_id,
 			g.title,
 			COALESCE(c.name, '') as console_name,
 			COALESCE(ge.name, '') as genre_name,
-			g.condition
+			g.condition,
+			g.owned, g.box_owned, g.collector, g.purchase_price,
+			g.jp_release_date, g.us_release_date, g.eu_release_date,
+			COALESCE(rj.code, ''), COALESCE(ru.code, ''), COALESCE(re.code, ''),
+			g.target_price, g.wish_priority, g.wish_edition,
+			g.play_status, g.play_started_on, g.play_finished_on, g.hours_played, g.personal_rating,
+			(SELECT COUNT(*) FROM game_copies WHERE game_id = g.game_id) as copies,
+			COALESCE((SELECT borrower FROM loans WHERE item_type = 'game' AND item_id = g.game_id AND returned_on IS NULL LIMIT 1), '') as lent_to,
+			(SELECT hash FROM images WHERE item_type = 'game' AND item_id = g.game_id ORDER BY` + imageOrder + ` LIMIT 1) as cover
 		FROM games g
 		LEFT JOIN consoles c ON g.console_id = c.console_id
 		LEFT JOIN genres ge ON g.genre_id = ge.genre_id
+		LEFT JOIN rating_systems rj ON g.jp_rating_id = rj.rating_id
+		LEFT JOIN rating_systems ru ON g.us_rating_id = ru.rating_id
+		LEFT JOIN rating_systems re ON g.eu_rating_id = re.rating_id
+		` + where + `
 		ORDER BY g.title
 	`
 
-	rows, err := conn.Query(context.Background(), query)
+	rows, err := s.db.Query(context.Background(), query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -66,23 +119,111 @@ func getGames(conn *pgx.Conn) ([]Game, error) {
 	var games []Game
 	for rows.Next() {
 		var g Game
+		var cover *string
 		err := rows.Scan(
 			&g.GameID,
 			&g.Title,
 			&g.ConsoleName,
 			&g.GenreName,
 			&g.Condition,
+			&g.Owned, &g.BoxOwned, &g.Collector, &g.PurchasePrice,
+			&g.JPReleaseDate, &g.USReleaseDate, &g.EUReleaseDate,
+			&g.JPRating, &g.USRating, &g.EURating,
+			&g.TargetPrice, &g.WishPriority, &g.WishEdition,
+			&g.PlayStatus, &g.PlayStartedOn, &g.PlayFinishedOn, &g.HoursPlayed, &g.PersonalRating,
+			&g.CopyCount,
+			&g.LentTo,
+			&cover,
 		)
 		if err != nil {
 			return nil, err
 		}
+		g.CoverThumb = s.coverThumbPath(cover)
 		games = append(games, g)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Credits searched with dev: and pub:
+	developers, err := s.getListNames(`
+		SELECT gd.game_id, d.name
+		FROM game_developers gd
+		JOIN developers d ON gd.developer_id = d.developer_id
+		ORDER BY d.name
+	`)
+	if err != nil {
+		return nil, err
+	}
+	publishers, err := s.getListNames(`
+		SELECT gp.game_id, p.name
+		FROM game_publishers gp
+		JOIN publishers p ON gp.publisher_id = p.publisher_id
+		ORDER BY p.name
+	`)
+	if err != nil {
+		return nil, err
+	}
+
+	// Regions of the copies, searched with region:
+	regions, err := s.getListNames(`
+		SELECT game_id, region
+		FROM game_copies
+		WHERE region IS NOT NULL
+		ORDER BY region
+	`)
+	if err != nil {
+		return nil, err
+	}
+
+	// Completeness of the copies, searched with completeness: and missing:
+	completeness, err := s.loadCopyCompleteness(0)
+	if err != nil {
+		return nil, err
+	}
+	status := make(map[int]string)
+	missing := make(map[int][]string)
+	for _, cc := range completeness {
+		status[cc.gameID] = bestCompleteness(status[cc.gameID], cc.status)
+		for _, name := range cc.missing {
+			if !slices.Contains(missing[cc.gameID], name) {
+				missing[cc.gameID] = append(missing[cc.gameID], name)
+			}
+		}
+	}
+
+	for i := range games {
+		games[i].Developers = developers[games[i].GameID]
+		games[i].Publishers = publishers[games[i].GameID]
+		games[i].CopyRegions = slices.Compact(regions[games[i].GameID])
+		games[i].Completeness = status[games[i].GameID]
+		games[i].MissingComponents = missing[games[i].GameID]
+	}
 	return games, nil
 }
 
-// getGameByID fetches complete game data with all relationships for editing (thorough)
-func getGameByID(conn *pgx.Conn, gameID int) (*Game, error) {
+// getListNames runs a query returning (item ID, name) pairs and groups the names by item
+func (s *sqlStore) getListNames(query string) (map[int][]string, error) {
+	rows, err := s.db.Query(context.Background(), query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	names := make(map[int][]string)
+	for rows.Next() {
+		var id int
+		var name string
+		if err := rows.Scan(&id, &name); err != nil {
+			return nil, err
+		}
+		names[id] = append(names[id], name)
+	}
+	return names, rows.Err()
+}
+
+// GetGameByID fetches complete game data with all relationships for editing (thorough)
+func (s *sqlStore) GetGameByID(gameID int) (*Game, error) {
 	// Fetch main game data
 	query := `
 		SELECT 
@@ -91,6 +232,8 @@ func getGameByID(conn *pgx.Conn, gameID int) (*Game, error) {
 			g.jp_rating_id, g.us_rating_id, g.eu_rating_id,
 			g.units_sold, g.owned, g.box_owned, g.collector, g.condition,
 			g.purchase_date, g.purchase_price, g.notes,
+			g.target_price, g.wish_priority, g.wish_edition,
+			g.play_status, g.play_started_on, g.play_finished_on, g.hours_played, g.personal_rating, g.review,
 			COALESCE(c.name, '') as console_name,
 			COALESCE(ge.name, '') as genre_name
 		FROM games g
@@ -100,12 +243,14 @@ func getGameByID(conn *pgx.Conn, gameID int) (*Game, error) {
 	`
 
 	var game Game
-	err := conn.QueryRow(context.Background(), query, gameID).Scan(
+	err := s.db.QueryRow(context.Background(), query, gameID).Scan(
 		&game.GameID, &game.Title, &game.ConsoleID, &game.GenreID,
 		&game.JPReleaseDate, &game.USReleaseDate, &game.EUReleaseDate,
 		&game.JPRatingID, &game.USRatingID, &game.EURatingID,
 		&game.UnitsSold, &game.Owned, &game.BoxOwned, &game.Collector, &game.Condition,
 		&game.PurchaseDate, &game.PurchasePrice, &game.Notes,
+		&game.TargetPrice, &game.WishPriority, &game.WishEdition,
+		&game.PlayStatus, &game.PlayStartedOn, &game.PlayFinishedOn, &game.HoursPlayed, &game.PersonalRating, &game.Review,
 		&game.ConsoleName, &game.GenreName,
 	)
 	if err != nil {
@@ -115,12 +260,15 @@ func getGameByID(conn *pgx.Conn, gameID int) (*Game, error) {
 	// Fetch many-to-many relationships
 
 	// Developers
-	devRows, _ := conn.Query(context.Background(), `
+	devRows, err := s.db.Query(context.Background(), `
 		SELECT d.name, d.developer_id
 		FROM game_developers gd
 		JOIN developers d ON gd.developer_id = d.developer_id
 		WHERE gd.game_id = $1
 	`, gameID)
+	if err != nil {
+		return nil, err
+	}
 	defer devRows.Close()
 
 	for devRows.Next() {
@@ -128,87 +276,319 @@ func getGameByID(conn *pgx.Conn, gameID int) (*Game, error) {
 		var id int
 		devRows.Scan(&name, &id)
 		game.Developers = append(game.Developers, name)
+		game.DeveloperIDs = append(game.DeveloperIDs, id)
 	}
 
 	// Composers
-	compRows, _ := conn.Query(context.Background(), `
-		SELECT c.name
+	compRows, err := s.db.Query(context.Background(), `
+		SELECT c.name, c.composer_id
 		FROM game_composers gc
 		JOIN composers c ON gc.composer_id = c.composer_id
 		WHERE gc.game_id = $1
 	`, gameID)
+	if err != nil {
+		return nil, err
+	}
 	defer compRows.Close()
 
 	for compRows.Next() {
 		var name string
-		compRows.Scan(&name)
+		var id int
+		compRows.Scan(&name, &id)
 		game.Composers = append(game.Composers, name)
+		game.ComposerIDs = append(game.ComposerIDs, id)
 	}
 
 	// Publishers
-	pubRows, _ := conn.Query(context.Background(), `
-		SELECT p.name
+	pubRows, err := s.db.Query(context.Background(), `
+		SELECT p.name, p.publisher_id
 		FROM game_publishers gp
 		JOIN publishers p ON gp.publisher_id = p.publisher_id
 		WHERE gp.game_id = $1
 	`, gameID)
+	if err != nil {
+		return nil, err
+	}
 	defer pubRows.Close()
 
 	for pubRows.Next() {
 		var name string
-		pubRows.Scan(&name)
+		var id int
+		pubRows.Scan(&name, &id)
 		game.Publishers = append(game.Publishers, name)
+		game.PublisherIDs = append(game.PublisherIDs, id)
 	}
 
 	// Producers
-	prodRows, _ := conn.Query(context.Background(), `
-		SELECT p.name
+	prodRows, err := s.db.Query(context.Background(), `
+		SELECT p.name, p.producer_id
 		FROM game_producers gpr
 		JOIN producers p ON gpr.producer_id = p.producer_id
 		WHERE gpr.game_id = $1
 	`, gameID)
+	if err != nil {
+		return nil, err
+	}
 	defer prodRows.Close()
 
 	for prodRows.Next() {
 		var name string
-		prodRows.Scan(&name)
+		var id int
+		prodRows.Scan(&name, &id)
 		game.Producers = append(game.Producers, name)
+		game.ProducerIDs = append(game.ProducerIDs, id)
+	}
+
+	// Copies
+	game.Copies, err = s.GetGameCopies(gameID)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range game.Copies {
+		game.Completeness = bestCompleteness(game.Completeness, c.Completeness)
 	}
 
 	return &game, nil
 }
 
-// deleteGame deletes a game and all its relationships from junction tables
-func deleteGame(conn *pgx.Conn, gameID int) error {
-	// Delete many-to-many relationships first (must be done before deleting the game)
-	conn.Exec(context.Background(), "DELETE FROM game_developers WHERE game_id = $1", gameID)
-	conn.Exec(context.Background(), "DELETE FROM game_composers WHERE game_id = $1", gameID)
-	conn.Exec(context.Background(), "DELETE FROM game_publishers WHERE game_id = $1", gameID)
-	conn.Exec(context.Background(), "DELETE FROM game_producers WHERE game_id = $1", gameID)
+// SaveGame inserts or updates a game along with its credits in a single transaction
+// If game.GameID == 0, performs INSERT; otherwise performs UPDATE
+func (s *sqlStore) SaveGame(game *Game) (int, error) {
+	var gameID int
+	err := s.withTx(func(tx querier) error {
+		var err error
+		gameID, err = saveGameTx(tx, game)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return gameID, nil
+}
+
+// saveGameTx writes the game row then rewrites its junction table rows
+func saveGameTx(tx querier, game *Game) (int, error) {
+	gameID := game.GameID
 
-	// Now delete the game itself
-	_, err := conn.Exec(context.Background(), "DELETE FROM games WHERE game_id = $1", gameID)
-	return err
+	// Execute INSERT or UPDATE based on gameID
+	if gameID == 0 {
+		// INSERT new game
+		query := `
+			INSERT INTO games (
+				title, console_id, genre_id, 
+				jp_release_date, us_release_date, eu_release_date,
+				jp_rating_id, us_rating_id, eu_rating_id,
+				units_sold, owned, box_owned, collector, condition,
+				purchase_date, purchase_price, notes,
+				target_price, wish_priority, wish_edition,
+				play_status, play_started_on, play_finished_on, hours_played, personal_rating, review
+			)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20,
+				$21, $22, $23, $24, $25, $26)
+			RETURNING game_id
+		`
+
+		err := tx.QueryRow(context.Background(), query,
+			game.Title, game.ConsoleID, game.GenreID,
+			game.JPReleaseDate, game.USReleaseDate, game.EUReleaseDate,
+			game.JPRatingID, game.USRatingID, game.EURatingID,
+			game.UnitsSold, game.Owned, game.BoxOwned,
+			game.Collector, game.Condition, game.PurchaseDate, game.PurchasePrice, game.Notes,
+			game.TargetPrice, game.WishPriority, game.WishEdition,
+			game.PlayStatus, game.PlayStartedOn, game.PlayFinishedOn, game.HoursPlayed, game.PersonalRating, game.Review,
+		).Scan(&gameID)
+
+		if err != nil {
+			return 0, fmt.Errorf("échec d'ajout du jeu: %w", err)
+		}
+	} else {
+		// UPDATE existing game
+		query := `
+			UPDATE games SET
+				title = $1, console_id = $2, genre_id = $3,
+				jp_release_date = $4, us_release_date = $5, eu_release_date = $6,
+				jp_rating_id = $7, us_rating_id = $8, eu_rating_id = $9,
+				units_sold = $10, owned = $11, box_owned = $12, collector = $13,
+				condition = $14, purchase_date = $15, purchase_price = $16, notes = $17,
+				target_price = $18, wish_priority = $19, wish_edition = $20,
+				play_status = $21, play_started_on = $22, play_finished_on = $23,
+				hours_played = $24, personal_rating = $25, review = $26
+			WHERE game_id = $27
+		`
+
+		_, err := tx.Exec(context.Background(), query,
+			game.Title, game.ConsoleID, game.GenreID,
+			game.JPReleaseDate, game.USReleaseDate, game.EUReleaseDate,
+			game.JPRatingID, game.USRatingID, game.EURatingID,
+			game.UnitsSold, game.Owned, game.BoxOwned,
+			game.Collector, game.Condition, game.PurchaseDate, game.PurchasePrice, game.Notes,
+			game.TargetPrice, game.WishPriority, game.WishEdition,
+			game.PlayStatus, game.PlayStartedOn, game.PlayFinishedOn, game.HoursPlayed, game.PersonalRating, game.Review,
+			gameID,
+		)
+
+		if err != nil {
+			return 0, fmt.Errorf("échec de mise à jour du jeu: %w", err)
+		}
+
+		// Delete old relationships, the new ones are saved below
+		if err := deleteGameCredits(tx, gameID); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := saveGameCredits(tx, gameID, game); err != nil {
+		return 0, err
+	}
+
+	// An owned game has at least one copy, described by the form the first time
+	if game.Owned {
+		if err := ensureGameCopy(tx, gameID); err != nil {
+			return 0, err
+		}
+	}
+	if err := syncGameCopies(tx, gameID); err != nil {
+		return 0, err
+	}
+	return gameID, nil
+}
+
+// saveGameCredits saves all many-to-many relationship entries for a game
+func saveGameCredits(tx querier, gameID int, game *Game) error {
+	// Insert developers
+	for _, devID := range game.DeveloperIDs {
+		_, err := tx.Exec(context.Background(),
+			"INSERT INTO game_developers (game_id, developer_id) VALUES ($1, $2)",
+			gameID, devID)
+		if err != nil {
+			return fmt.Errorf("échec d'ajout des développeurs: %w", err)
+		}
+	}
+
+	// Insert composers
+	for _, compID := range game.ComposerIDs {
+		_, err := tx.Exec(context.Background(),
+			"INSERT INTO game_composers (game_id, composer_id) VALUES ($1, $2)",
+			gameID, compID)
+		if err != nil {
+			return fmt.Errorf("échec d'ajout des compositeurs: %w", err)
+		}
+	}
+
+	// Insert publishers
+	for _, pubID := range game.PublisherIDs {
+		_, err := tx.Exec(context.Background(),
+			"INSERT INTO game_publishers (game_id, publisher_id) VALUES ($1, $2)",
+			gameID, pubID)
+		if err != nil {
+			return fmt.Errorf("échec d'ajout des distributeurs: %w", err)
+		}
+	}
+
+	// Insert producers
+	for _, prodID := range game.ProducerIDs {
+		_, err := tx.Exec(context.Background(),
+			"INSERT INTO game_producers (game_id, producer_id) VALUES ($1, $2)",
+			gameID, prodID)
+		if err != nil {
+			return fmt.Errorf("échec d'ajout des producteurs: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// deleteGameCredits removes every junction table row of a game
+func deleteGameCredits(tx querier, gameID int) error {
+	for _, table := range []string{"game_developers", "game_composers", "game_publishers", "game_producers"} {
+		_, err := tx.Exec(context.Background(), "DELETE FROM "+table+" WHERE game_id = $1", gameID)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteGame deletes a game, its copies, images and all its relationships from junction tables in a single transaction
+func (s *sqlStore) DeleteGame(gameID int) error {
+	var images []ItemImage
+	err := s.withTx(func(tx querier) error {
+		// Delete many-to-many relationships first (must be done before deleting the game)
+		if err := deleteGameCredits(tx, gameID); err != nil {
+			return err
+		}
+
+		var err error
+		if images, err = deleteItemImages(tx, "game", gameID); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(context.Background(), "DELETE FROM game_copies WHERE game_id = $1", gameID); err != nil {
+			return fmt.Errorf("échec de suppression des exemplaires: %w", err)
+		}
+		if err := deleteLoans(tx, "game", gameID); err != nil {
+			return err
+		}
+		if err := deleteMarketValues(tx, "game", gameID); err != nil {
+			return err
+		}
+
+		// Now delete the game itself
+		_, err = tx.Exec(context.Background(), "DELETE FROM games WHERE game_id = $1", gameID)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	s.removeUnusedImageFiles(images)
+	return nil
 }
 
 // ========== Consoles Functions ==========
 // NOTE: Same pattern as Games - separate list vs detail queries for performance
 
-// getConsoles fetches minimal console data for table display (fast, lightweight)
-func getConsoles(conn *pgx.Conn) ([]Console, error) {
+// GetConsoles fetches minimal console data for table display (fast, lightweight)
+// Besides the table columns, it loads what the search bar can filter on.
+func (s *sqlStore) GetConsoles() ([]Console, error) {
+	return s.listConsoles("", nil)
+}
+
+// SearchConsoles returns the consoles matching a search query (see query.go)
+func (s *sqlStore) SearchConsoles(query string) ([]Console, error) {
+	filter, err := compileSearchQuery(&consoleQuerySchema, query)
+	if err != nil {
+		return nil, err
+	}
+	where, args := filter.SQL(s.dialect, 1)
+	return s.listConsoles(where, args)
+}
+
+// listConsoles runs the list query, restricted by an optional WHERE condition
+func (s *sqlStore) listConsoles(where string, args []any) ([]Console, error) {
+	if where != "" {
+		where = "WHERE " + where
+	}
 	query := `
 		SELECT 
 			c.console_id,
 			c.name,
 			COALESCE(m.name, '') as manufacturer_name,
+			COALESCE(ct.name, '') as type_name,
 			COALESCE(c.generation, 0) as generation,
-			c.condition
+			c.condition,
+			c.owned, c.purchase_price,
+			c.jp_release_date, c.us_release_date, c.eu_release_date,
+			c.target_price, c.wish_priority, c.wish_edition,
+			COALESCE((SELECT borrower FROM loans WHERE item_type = 'console' AND item_id = c.console_id AND returned_on IS NULL LIMIT 1), '') as lent_to,
+			(SELECT hash FROM images WHERE item_type = 'console' AND item_id = c.console_id ORDER BY` + imageOrder + ` LIMIT 1) as cover
 		FROM consoles c
 		LEFT JOIN manufacturers m ON c.manufacturer_id = m.manufacturer_id
+		LEFT JOIN console_types ct ON c.type_id = ct.type_id
+		` + where + `
 		ORDER BY c.name
 	`
 
-	rows, err := conn.Query(context.Background(), query)
+	rows, err := s.db.Query(context.Background(), query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -218,33 +598,42 @@ func getConsoles(conn *pgx.Conn) ([]Console, error) {
 	for rows.Next() {
 		var c Console
 		var gen int
+		var cover *string
 		err := rows.Scan(
 			&c.ConsoleID,
 			&c.Name,
 			&c.ManufacturerName,
+			&c.TypeName,
 			&gen,
 			&c.Condition,
+			&c.Owned, &c.PurchasePrice,
+			&c.JPReleaseDate, &c.USReleaseDate, &c.EUReleaseDate,
+			&c.TargetPrice, &c.WishPriority, &c.WishEdition,
+			&c.LentTo,
+			&cover,
 		)
 		if err != nil {
 			return nil, err
 		}
+		c.CoverThumb = s.coverThumbPath(cover)
 		if gen != 0 {
 			c.Generation = &gen
 		}
 		consoles = append(consoles, c)
 	}
-	return consoles, nil
+	return consoles, rows.Err()
 }
 
-// getConsoleByID fetches complete console data for editing (thorough)
-func getConsoleByID(conn *pgx.Conn, consoleID int) (*Console, error) {
+// GetConsoleByID fetches complete console data for editing (thorough)
+func (s *sqlStore) GetConsoleByID(consoleID int) (*Console, error) {
 	query := `
 		SELECT 
 			c.console_id, c.name, c.generation, c.type_id, c.manufacturer_id,
 			c.jp_release_date, c.us_release_date, c.eu_release_date, c.discontinued,
 			c.price_jpy, c.price_usd, c.controllers, c.cpu, c.gpu, c.memory, c.audio,
 			c.units_sold, c.top_game, c.predecessor, c.successor,
-			c.owned, c.condition, c.notes,
+			c.owned, c.condition, c.purchase_date, c.purchase_price, c.notes,
+			c.target_price, c.wish_priority, c.wish_edition,
 			COALESCE(m.name, '') as manufacturer_name,
 			COALESCE(ct.name, '') as type_name
 		FROM consoles c
@@ -254,12 +643,13 @@ func getConsoleByID(conn *pgx.Conn, consoleID int) (*Console, error) {
 	`
 
 	var console Console
-	err := conn.QueryRow(context.Background(), query, consoleID).Scan(
+	err := s.db.QueryRow(context.Background(), query, consoleID).Scan(
 		&console.ConsoleID, &console.Name, &console.Generation, &console.TypeID, &console.ManufacturerID,
 		&console.JPReleaseDate, &console.USReleaseDate, &console.EUReleaseDate, &console.Discontinued,
 		&console.PriceJPY, &console.PriceUSD, &console.Controllers, &console.CPU, &console.GPU, &console.Memory, &console.Audio,
 		&console.UnitsSold, &console.TopGame, &console.Predecessor, &console.Successor,
-		&console.Owned, &console.Condition, &console.Notes,
+		&console.Owned, &console.Condition, &console.PurchaseDate, &console.PurchasePrice, &console.Notes,
+		&console.TargetPrice, &console.WishPriority, &console.WishEdition,
 		&console.ManufacturerName, &console.TypeName,
 	)
 	if err != nil {
@@ -269,17 +659,132 @@ func getConsoleByID(conn *pgx.Conn, consoleID int) (*Console, error) {
 	return &console, nil
 }
 
-// deleteConsole deletes a console (will fail if games reference it due to foreign key constraints)
-func deleteConsole(conn *pgx.Conn, consoleID int) error {
-	_, err := conn.Exec(context.Background(), "DELETE FROM consoles WHERE console_id = $1", consoleID)
-	return err
+// SaveConsole inserts or updates a console in a transaction (similar to SaveGame pattern)
+func (s *sqlStore) SaveConsole(console *Console) (int, error) {
+	var consoleID int
+	err := s.withTx(func(tx querier) error {
+		var err error
+		consoleID, err = saveConsoleTx(tx, console)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return consoleID, nil
+}
+
+// saveConsoleTx writes the console row
+func saveConsoleTx(tx querier, console *Console) (int, error) {
+	consoleID := console.ConsoleID
+
+	// Execute INSERT or UPDATE
+	if consoleID == 0 {
+		// INSERT
+		query := `
+			INSERT INTO consoles (
+				name, type_id, manufacturer_id, generation,
+				jp_release_date, us_release_date, eu_release_date, discontinued,
+				price_jpy, price_usd, controllers, cpu, gpu, memory, audio,
+				units_sold, top_game, predecessor, successor,
+				owned, condition, purchase_date, purchase_price, notes,
+				target_price, wish_priority, wish_edition
+			)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27)
+			RETURNING console_id
+		`
+
+		err := tx.QueryRow(context.Background(), query,
+			console.Name, console.TypeID, console.ManufacturerID, console.Generation,
+			console.JPReleaseDate, console.USReleaseDate, console.EUReleaseDate, console.Discontinued,
+			console.PriceJPY, console.PriceUSD, console.Controllers, console.CPU, console.GPU, console.Memory, console.Audio,
+			console.UnitsSold, console.TopGame, console.Predecessor, console.Successor,
+			console.Owned, console.Condition, console.PurchaseDate, console.PurchasePrice, console.Notes,
+			console.TargetPrice, console.WishPriority, console.WishEdition,
+		).Scan(&consoleID)
+
+		if err != nil {
+			return 0, fmt.Errorf("échec de l'ajout de console: %w", err)
+		}
+		return consoleID, nil
+	} else {
+		// UPDATE
+		query := `
+			UPDATE consoles SET
+				name = $1, type_id = $2, manufacturer_id = $3, generation = $4,
+				jp_release_date = $5, us_release_date = $6, eu_release_date = $7, discontinued = $8,
+				price_jpy = $9, price_usd = $10, controllers = $11, cpu = $12, gpu = $13, memory = $14, audio = $15,
+				units_sold = $16, top_game = $17, predecessor = $18, successor = $19,
+				owned = $20, condition = $21, purchase_date = $22, purchase_price = $23, notes = $24,
+				target_price = $25, wish_priority = $26, wish_edition = $27
+			WHERE console_id = $28
+		`
+
+		_, err := tx.Exec(context.Background(), query,
+			console.Name, console.TypeID, console.ManufacturerID, console.Generation,
+			console.JPReleaseDate, console.USReleaseDate, console.EUReleaseDate, console.Discontinued,
+			console.PriceJPY, console.PriceUSD, console.Controllers, console.CPU, console.GPU, console.Memory, console.Audio,
+			console.UnitsSold, console.TopGame, console.Predecessor, console.Successor,
+			console.Owned, console.Condition, console.PurchaseDate, console.PurchasePrice, console.Notes,
+			console.TargetPrice, console.WishPriority, console.WishEdition,
+			consoleID,
+		)
+
+		if err != nil {
+			return 0, fmt.Errorf("échec de la mise à jour de la console: %w", err)
+		}
+		return consoleID, nil
+	}
+}
+
+// DeleteConsole deletes a console and its images (will fail if games reference it due to foreign key constraints)
+func (s *sqlStore) DeleteConsole(consoleID int) error {
+	var images []ItemImage
+	err := s.withTx(func(tx querier) error {
+		var err error
+		if images, err = deleteItemImages(tx, "console", consoleID); err != nil {
+			return err
+		}
+		if err := deleteLoans(tx, "console", consoleID); err != nil {
+			return err
+		}
+		if err := deleteMarketValues(tx, "console", consoleID); err != nil {
+			return err
+		}
+		_, err = tx.Exec(context.Background(), "DELETE FROM consoles WHERE console_id = $1", consoleID)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	s.removeUnusedImageFiles(images)
+	return nil
 }
 
 // ========== Accessories Functions ==========
 // NOTE: Same pattern - separate list vs detail queries for performance
 
-// getAccessories fetches minimal accessory data for table display (fast, lightweight)
-func getAccessories(conn *pgx.Conn) ([]Accessory, error) {
+// GetAccessories fetches minimal accessory data for table display (fast, lightweight)
+// Besides the table columns, it loads what the search bar can filter on.
+func (s *sqlStore) GetAccessories() ([]Accessory, error) {
+	return s.listAccessories("", nil)
+}
+
+// SearchAccessories returns the accessories matching a search query (see query.go)
+func (s *sqlStore) SearchAccessories(query string) ([]Accessory, error) {
+	filter, err := compileSearchQuery(&accessoryQuerySchema, query)
+	if err != nil {
+		return nil, err
+	}
+	where, args := filter.SQL(s.dialect, 1)
+	return s.listAccessories(where, args)
+}
+
+// listAccessories runs the list query, restricted by an optional WHERE condition
+func (s *sqlStore) listAccessories(where string, args []any) ([]Accessory, error) {
+	if where != "" {
+		where = "WHERE " + where
+	}
 	query := `
 		SELECT 
 			a.accessory_id,
@@ -288,14 +793,19 @@ func getAccessories(conn *pgx.Conn) ([]Accessory, error) {
 			COALESCE(m.name, '') as manufacturer_name,
 			COALESCE(at.name, '') as type_name,
 			COALESCE(a.quantity, 1) as quantity,
-			a.condition
+			a.condition,
+			a.owned, a.purchase_price,
+			a.target_price, a.wish_priority, a.wish_edition,
+			COALESCE((SELECT borrower FROM loans WHERE item_type = 'accessory' AND item_id = a.accessory_id AND returned_on IS NULL LIMIT 1), '') as lent_to,
+			(SELECT hash FROM images WHERE item_type = 'accessory' AND item_id = a.accessory_id ORDER BY` + imageOrder + ` LIMIT 1) as cover
 		FROM accessories a
 		LEFT JOIN manufacturers m ON a.manufacturer_id = m.manufacturer_id
 		LEFT JOIN accessory_types at ON a.type_id = at.type_id
+		` + where + `
 		ORDER BY a.name
 	`
 
-	rows, err := conn.Query(context.Background(), query)
+	rows, err := s.db.Query(context.Background(), query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -305,6 +815,7 @@ func getAccessories(conn *pgx.Conn) ([]Accessory, error) {
 	for rows.Next() {
 		var a Accessory
 		var color string
+		var cover *string
 		err := rows.Scan(
 			&a.AccessoryID,
 			&a.Name,
@@ -313,25 +824,48 @@ func getAccessories(conn *pgx.Conn) ([]Accessory, error) {
 			&a.TypeName,
 			&a.Quantity,
 			&a.Condition,
+			&a.Owned, &a.PurchasePrice,
+			&a.TargetPrice, &a.WishPriority, &a.WishEdition,
+			&a.LentTo,
+			&cover,
 		)
 		if err != nil {
 			return nil, err
 		}
+		a.CoverThumb = s.coverThumbPath(cover)
 		if color != "" {
 			a.Color = &color
 		}
 		accessories = append(accessories, a)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Compatible consoles searched with console:
+	consoles, err := s.getListNames(`
+		SELECT ac.accessory_id, c.name
+		FROM accessory_consoles ac
+		JOIN consoles c ON ac.console_id = c.console_id
+		ORDER BY c.name
+	`)
+	if err != nil {
+		return nil, err
+	}
+	for i := range accessories {
+		accessories[i].Consoles = consoles[accessories[i].AccessoryID]
+	}
 	return accessories, nil
 }
 
-// getAccessoryByID fetches complete accessory data with console relationships for editing (thorough)
-func getAccessoryByID(conn *pgx.Conn, accessoryID int) (*Accessory, error) {
+// GetAccessoryByID fetches complete accessory data with console relationships for editing (thorough)
+func (s *sqlStore) GetAccessoryByID(accessoryID int) (*Accessory, error) {
 	query := `
 		SELECT 
 			a.accessory_id, a.name, a.color, a.type_id, a.manufacturer_id,
 			a.condition, a.owned, a.purchase_date, a.purchase_price,
 			a.quantity, a.notes,
+			a.target_price, a.wish_priority, a.wish_edition,
 			COALESCE(m.name, '') as manufacturer_name,
 			COALESCE(at.name, '') as type_name
 		FROM accessories a
@@ -341,11 +875,12 @@ func getAccessoryByID(conn *pgx.Conn, accessoryID int) (*Accessory, error) {
 	`
 
 	var accessory Accessory
-	err := conn.QueryRow(context.Background(), query, accessoryID).Scan(
+	err := s.db.QueryRow(context.Background(), query, accessoryID).Scan(
 		&accessory.AccessoryID, &accessory.Name, &accessory.Color,
 		&accessory.TypeID, &accessory.ManufacturerID,
 		&accessory.Condition, &accessory.Owned, &accessory.PurchaseDate,
 		&accessory.PurchasePrice, &accessory.Quantity, &accessory.Notes,
+		&accessory.TargetPrice, &accessory.WishPriority, &accessory.WishEdition,
 		&accessory.ManufacturerName, &accessory.TypeName,
 	)
 	if err != nil {
@@ -353,35 +888,144 @@ func getAccessoryByID(conn *pgx.Conn, accessoryID int) (*Accessory, error) {
 	}
 
 	// Fetch associated consoles (many-to-many relationship)
-	consoleRows, _ := conn.Query(context.Background(), `
-		SELECT c.name
+	consoleRows, err := s.db.Query(context.Background(), `
+		SELECT c.name, c.console_id
 		FROM accessory_consoles ac
 		JOIN consoles c ON ac.console_id = c.console_id
 		WHERE ac.accessory_id = $1
 	`, accessoryID)
+	if err != nil {
+		return nil, err
+	}
 	defer consoleRows.Close()
 
 	for consoleRows.Next() {
 		var consoleName string
-		consoleRows.Scan(&consoleName)
+		var consoleID int
+		consoleRows.Scan(&consoleName, &consoleID)
 		accessory.Consoles = append(accessory.Consoles, consoleName)
+		accessory.ConsoleIDs = append(accessory.ConsoleIDs, consoleID)
 	}
 
 	return &accessory, nil
 }
 
-// deleteAccessory deletes an accessory and its console relationships (cascades to junction table)
-func deleteAccessory(conn *pgx.Conn, accessoryID int) error {
-	conn.Exec(context.Background(), "DELETE FROM accessory_consoles WHERE accessory_id = $1", accessoryID)
-	_, err := conn.Exec(context.Background(), "DELETE FROM accessories WHERE accessory_id = $1", accessoryID)
-	return err
+// SaveAccessory inserts or updates an accessory and its compatible consoles in a single transaction
+// (similar to SaveGame pattern)
+func (s *sqlStore) SaveAccessory(accessory *Accessory) (int, error) {
+	var accessoryID int
+	err := s.withTx(func(tx querier) error {
+		var err error
+		accessoryID, err = saveAccessoryTx(tx, accessory)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return accessoryID, nil
+}
+
+// saveAccessoryTx writes the accessory row then rewrites its accessory_consoles rows
+func saveAccessoryTx(tx querier, accessory *Accessory) (int, error) {
+	accessoryID := accessory.AccessoryID
+
+	// Execute INSERT or UPDATE
+	if accessoryID == 0 {
+		// INSERT
+		query := `
+			INSERT INTO accessories (
+				name, color, type_id, manufacturer_id, quantity,
+				condition, owned, purchase_date, purchase_price, notes,
+				target_price, wish_priority, wish_edition
+			)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+			RETURNING accessory_id
+		`
+
+		err := tx.QueryRow(context.Background(), query,
+			accessory.Name, accessory.Color, accessory.TypeID, accessory.ManufacturerID, accessory.Quantity,
+			accessory.Condition, accessory.Owned, accessory.PurchaseDate, accessory.PurchasePrice, accessory.Notes,
+			accessory.TargetPrice, accessory.WishPriority, accessory.WishEdition,
+		).Scan(&accessoryID)
+
+		if err != nil {
+			return 0, fmt.Errorf("échec de l'ajout de l'accessoire: %w", err)
+		}
+	} else {
+		// UPDATE
+		query := `
+			UPDATE accessories SET
+				name = $1, color = $2, type_id = $3, manufacturer_id = $4,
+				quantity = $5, condition = $6, owned = $7,
+				purchase_date = $8, purchase_price = $9, notes = $10,
+				target_price = $11, wish_priority = $12, wish_edition = $13
+			WHERE accessory_id = $14
+		`
+
+		_, err := tx.Exec(context.Background(), query,
+			accessory.Name, accessory.Color, accessory.TypeID, accessory.ManufacturerID, accessory.Quantity,
+			accessory.Condition, accessory.Owned, accessory.PurchaseDate, accessory.PurchasePrice, accessory.Notes,
+			accessory.TargetPrice, accessory.WishPriority, accessory.WishEdition,
+			accessoryID,
+		)
+
+		if err != nil {
+			return 0, fmt.Errorf("échec de la mise à jour de l'accessoire: %w", err)
+		}
+
+		// Delete old console relationships, the new ones are saved below
+		_, err = tx.Exec(context.Background(), "DELETE FROM accessory_consoles WHERE accessory_id = $1", accessoryID)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	// Save console relationships
+	for _, consoleID := range accessory.ConsoleIDs {
+		_, err := tx.Exec(context.Background(),
+			"INSERT INTO accessory_consoles (accessory_id, console_id) VALUES ($1, $2)",
+			accessoryID, consoleID)
+		if err != nil {
+			return 0, fmt.Errorf("échec d'ajout des plateformes: %w", err)
+		}
+	}
+
+	return accessoryID, nil
+}
+
+// DeleteAccessory deletes an accessory, its images and its console relationships in a single transaction
+func (s *sqlStore) DeleteAccessory(accessoryID int) error {
+	var images []ItemImage
+	err := s.withTx(func(tx querier) error {
+		_, err := tx.Exec(context.Background(), "DELETE FROM accessory_consoles WHERE accessory_id = $1", accessoryID)
+		if err != nil {
+			return err
+		}
+		if images, err = deleteItemImages(tx, "accessory", accessoryID); err != nil {
+			return err
+		}
+		if err := deleteLoans(tx, "accessory", accessoryID); err != nil {
+			return err
+		}
+		if err := deleteMarketValues(tx, "accessory", accessoryID); err != nil {
+			return err
+		}
+		_, err = tx.Exec(context.Background(), "DELETE FROM accessories WHERE accessory_id = $1", accessoryID)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	s.removeUnusedImageFiles(images)
+	return nil
 }
 
 // ========== Lookup Tables Functions ==========
 // These are simple reference data used in dropdowns - no detail queries needed
 
-func getGenres(conn *pgx.Conn) ([]Genre, error) {
-	rows, err := conn.Query(context.Background(), "SELECT genre_id, name FROM genres ORDER BY name")
+func (s *sqlStore) GetGenres() ([]Genre, error) {
+	rows, err := s.db.Query(context.Background(), "SELECT genre_id, name FROM genres ORDER BY name")
 	if err != nil {
 		return nil, err
 	}
@@ -399,8 +1043,8 @@ func getGenres(conn *pgx.Conn) ([]Genre, error) {
 	return genres, nil
 }
 
-func getDevelopers(conn *pgx.Conn) ([]Developer, error) {
-	rows, err := conn.Query(context.Background(), "SELECT developer_id, name FROM developers ORDER BY name")
+func (s *sqlStore) GetDevelopers() ([]Developer, error) {
+	rows, err := s.db.Query(context.Background(), "SELECT developer_id, name FROM developers ORDER BY name")
 	if err != nil {
 		return nil, err
 	}
@@ -418,8 +1062,8 @@ func getDevelopers(conn *pgx.Conn) ([]Developer, error) {
 	return developers, nil
 }
 
-func getComposers(conn *pgx.Conn) ([]Composer, error) {
-	rows, err := conn.Query(context.Background(), "SELECT composer_id, name FROM composers ORDER BY name")
+func (s *sqlStore) GetComposers() ([]Composer, error) {
+	rows, err := s.db.Query(context.Background(), "SELECT composer_id, name FROM composers ORDER BY name")
 	if err != nil {
 		return nil, err
 	}
@@ -437,8 +1081,8 @@ func getComposers(conn *pgx.Conn) ([]Composer, error) {
 	return composers, nil
 }
 
-func getPublishers(conn *pgx.Conn) ([]Publisher, error) {
-	rows, err := conn.Query(context.Background(), "SELECT publisher_id, name FROM publishers ORDER BY name")
+func (s *sqlStore) GetPublishers() ([]Publisher, error) {
+	rows, err := s.db.Query(context.Background(), "SELECT publisher_id, name FROM publishers ORDER BY name")
 	if err != nil {
 		return nil, err
 	}
@@ -456,8 +1100,8 @@ func getPublishers(conn *pgx.Conn) ([]Publisher, error) {
 	return publishers, nil
 }
 
-func getProducers(conn *pgx.Conn) ([]Producer, error) {
-	rows, err := conn.Query(context.Background(), "SELECT producer_id, name FROM producers ORDER BY name")
+func (s *sqlStore) GetProducers() ([]Producer, error) {
+	rows, err := s.db.Query(context.Background(), "SELECT producer_id, name FROM producers ORDER BY name")
 	if err != nil {
 		return nil, err
 	}
@@ -475,8 +1119,8 @@ func getProducers(conn *pgx.Conn) ([]Producer, error) {
 	return producers, nil
 }
 
-func getManufacturers(conn *pgx.Conn) ([]Manufacturer, error) {
-	rows, err := conn.Query(context.Background(), "SELECT manufacturer_id, name FROM manufacturers ORDER BY name")
+func (s *sqlStore) GetManufacturers() ([]Manufacturer, error) {
+	rows, err := s.db.Query(context.Background(), "SELECT manufacturer_id, name FROM manufacturers ORDER BY name")
 	if err != nil {
 		return nil, err
 	}
@@ -494,8 +1138,8 @@ func getManufacturers(conn *pgx.Conn) ([]Manufacturer, error) {
 	return manufacturers, nil
 }
 
-func getConsoleTypes(conn *pgx.Conn) ([]ConsoleType, error) {
-	rows, err := conn.Query(context.Background(), "SELECT type_id, name FROM console_types ORDER BY name")
+func (s *sqlStore) GetConsoleTypes() ([]ConsoleType, error) {
+	rows, err := s.db.Query(context.Background(), "SELECT type_id, name FROM console_types ORDER BY name")
 	if err != nil {
 		return nil, err
 	}
@@ -513,8 +1157,8 @@ func getConsoleTypes(conn *pgx.Conn) ([]ConsoleType, error) {
 	return types, nil
 }
 
-func getAccessoryTypes(conn *pgx.Conn) ([]AccessoryType, error) {
-	rows, err := conn.Query(context.Background(), "SELECT type_id, name FROM accessory_types ORDER BY name")
+func (s *sqlStore) GetAccessoryTypes() ([]AccessoryType, error) {
+	rows, err := s.db.Query(context.Background(), "SELECT type_id, name FROM accessory_types ORDER BY name")
 	if err != nil {
 		return nil, err
 	}
@@ -532,8 +1176,8 @@ func getAccessoryTypes(conn *pgx.Conn) ([]AccessoryType, error) {
 	return types, nil
 }
 
-func getRatingSystems(conn *pgx.Conn) ([]RatingSystem, error) {
-	rows, err := conn.Query(context.Background(), "SELECT rating_id, region, code, description FROM rating_systems ORDER BY region, code")
+func (s *sqlStore) GetRatingSystems() ([]RatingSystem, error) {
+	rows, err := s.db.Query(context.Background(), "SELECT rating_id, region, code, description FROM rating_systems ORDER BY region, code")
 	if err != nil {
 		return nil, err
 	}
@@ -550,3 +1194,1711 @@ func getRatingSystems(conn *pgx.Conn) ([]RatingSystem, error) {
 	}
 	return ratings, nil
 }
+
+// ========== Lookup Entry Functions ==========
+// Used by the "+" buttons of the autocomplete selectors to add a new name on the fly, and by
+// the lookup endpoints of the REST API
+
+func (s *sqlStore) AddDeveloper(name string) (int, error) {
+	return s.addLookupEntry("INSERT INTO developers (name) VALUES ($1) RETURNING developer_id", name)
+}
+
+func (s *sqlStore) AddComposer(name string) (int, error) {
+	return s.addLookupEntry("INSERT INTO composers (name) VALUES ($1) RETURNING composer_id", name)
+}
+
+func (s *sqlStore) AddPublisher(name string) (int, error) {
+	return s.addLookupEntry("INSERT INTO publishers (name) VALUES ($1) RETURNING publisher_id", name)
+}
+
+func (s *sqlStore) AddProducer(name string) (int, error) {
+	return s.addLookupEntry("INSERT INTO producers (name) VALUES ($1) RETURNING producer_id", name)
+}
+
+// addLookupEntry runs an INSERT ... RETURNING query and returns the new ID
+func (s *sqlStore) addLookupEntry(query string, name string) (int, error) {
+	var id int
+	err := s.db.QueryRow(context.Background(), query, name).Scan(&id)
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// lookupEntryTables gives the ID column of each lookup table and the columns referencing it
+// Table names are fixed here, never taken from user input
+var lookupEntryTables = map[string]struct {
+	idColumn string
+	usedBy   []string // "table.column"
+}{
+	"genres":          {"genre_id", []string{"games.genre_id"}},
+	"developers":      {"developer_id", []string{"game_developers.developer_id"}},
+	"publishers":      {"publisher_id", []string{"game_publishers.publisher_id"}},
+	"composers":       {"composer_id", []string{"game_composers.composer_id"}},
+	"producers":       {"producer_id", []string{"game_producers.producer_id"}},
+	"manufacturers":   {"manufacturer_id", []string{"consoles.manufacturer_id", "accessories.manufacturer_id"}},
+	"console_types":   {"type_id", []string{"consoles.type_id", "completeness_components.console_type_id"}},
+	"accessory_types": {"type_id", []string{"accessories.type_id"}},
+	"rating_systems":  {"rating_id", []string{"games.jp_rating_id", "games.us_rating_id", "games.eu_rating_id"}},
+}
+
+// errLookupEntryInUse is returned when deleting a lookup entry that items still refer to
+var errLookupEntryInUse = errors.New("entrée utilisée par la collection")
+
+// SaveLookupEntry adds (id 0) or renames an entry of a lookup table with a name column
+// Renaming an entry that doesn't exist returns errNoRows.
+func (s *sqlStore) SaveLookupEntry(table string, id int, name string) (int, error) {
+	t, ok := lookupEntryTables[table]
+	if !ok || table == "rating_systems" {
+		return 0, fmt.Errorf("table de référence inconnue: %q", table)
+	}
+	if id == 0 {
+		return s.addLookupEntry("INSERT INTO "+table+" (name) VALUES ($1) RETURNING "+t.idColumn, name)
+	}
+
+	n, err := s.db.Exec(context.Background(), "UPDATE "+table+" SET name = $1 WHERE "+t.idColumn+" = $2", name, id)
+	if err != nil {
+		return 0, err
+	}
+	if n == 0 {
+		return 0, errNoRows
+	}
+	return id, nil
+}
+
+// SaveRatingSystem adds (RatingID 0) or updates a rating
+func (s *sqlStore) SaveRatingSystem(rating *RatingSystem) (int, error) {
+	ctx := context.Background()
+	if rating.RatingID == 0 {
+		var id int
+		err := s.db.QueryRow(ctx, "INSERT INTO rating_systems (region, code, description) VALUES ($1, $2, $3) RETURNING rating_id",
+			rating.Region, rating.Code, rating.Description).Scan(&id)
+		return id, err
+	}
+
+	n, err := s.db.Exec(ctx, "UPDATE rating_systems SET region = $1, code = $2, description = $3 WHERE rating_id = $4",
+		rating.Region, rating.Code, rating.Description, rating.RatingID)
+	if err != nil {
+		return 0, err
+	}
+	if n == 0 {
+		return 0, errNoRows
+	}
+	return rating.RatingID, nil
+}
+
+// DeleteLookupEntry removes an entry of a lookup table no game, console or accessory uses
+// It returns errLookupEntryInUse otherwise, and errNoRows when the entry doesn't exist.
+func (s *sqlStore) DeleteLookupEntry(table string, id int) error {
+	t, ok := lookupEntryTables[table]
+	if !ok {
+		return fmt.Errorf("table de référence inconnue: %q", table)
+	}
+
+	ctx := context.Background()
+	return s.withTx(func(tx querier) error {
+		uses := 0
+		for _, ref := range t.usedBy {
+			refTable, column, _ := strings.Cut(ref, ".")
+			var n int
+			if err := tx.QueryRow(ctx, "SELECT COUNT(*) FROM "+refTable+" WHERE "+column+" = $1", id).Scan(&n); err != nil {
+				return err
+			}
+			uses += n
+		}
+		if uses > 0 {
+			return fmt.Errorf("%w (%d référence(s))", errLookupEntryInUse, uses)
+		}
+
+		n, err := tx.Exec(ctx, "DELETE FROM "+table+" WHERE "+t.idColumn+" = $1", id)
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return errNoRows
+		}
+		return nil
+	})
+}
+
+// ========== Dashboard Functions ==========
+// Aggregates are computed by the database so the dashboard stays fast on large collections
+
+// recentPurchasesLimit is the number of purchases listed on the dashboard
+const recentPurchasesLimit = 10
+
+// GetDashboardStats computes the figures shown on the "Accueil" tab
+func (s *sqlStore) GetDashboardStats() (*DashboardStats, error) {
+	ctx := context.Background()
+	stats := &DashboardStats{}
+
+	// Totals (money spent covers every game, console and accessory with a purchase price)
+	err := s.db.QueryRow(ctx, `
+		SELECT
+			(SELECT COUNT(*) FROM games WHERE owned),
+			(SELECT COUNT(*) FROM consoles WHERE owned),
+			(SELECT COALESCE(SUM(quantity), 0) FROM accessories WHERE owned),
+			(SELECT COALESCE(SUM(purchase_price), 0) FROM games)
+				+ (SELECT COALESCE(SUM(purchase_price), 0) FROM consoles)
+				+ (SELECT COALESCE(SUM(purchase_price), 0) FROM accessories),
+			(SELECT COUNT(*) FROM games WHERE owned AND box_owned),
+			(SELECT COUNT(*) FROM games WHERE owned AND collector)
+	`).Scan(
+		&stats.GamesOwned,
+		&stats.ConsolesOwned,
+		&stats.AccessoriesOwned,
+		&stats.TotalSpent,
+		&stats.GamesWithBox,
+		&stats.GamesCollector,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("échec du calcul des totaux: %w", err)
+	}
+
+	stats.GamesPerConsole, err = s.getLabelCounts(`
+		SELECT COALESCE(c.name, ''), COUNT(*)
+		FROM games g
+		LEFT JOIN consoles c ON g.console_id = c.console_id
+		WHERE g.owned
+		GROUP BY c.name
+		ORDER BY COUNT(*) DESC, c.name
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("échec du calcul des jeux par console: %w", err)
+	}
+
+	stats.GamesPerGenre, err = s.getLabelCounts(`
+		SELECT COALESCE(ge.name, ''), COUNT(*)
+		FROM games g
+		LEFT JOIN genres ge ON g.genre_id = ge.genre_id
+		WHERE g.owned
+		GROUP BY ge.name
+		ORDER BY COUNT(*) DESC, ge.name
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("échec du calcul des jeux par genre: %w", err)
+	}
+
+	stats.Conditions, err = s.getConditionCounts()
+	if err != nil {
+		return nil, fmt.Errorf("échec du calcul des états: %w", err)
+	}
+
+	stats.RecentPurchases, err = s.getRecentPurchases(recentPurchasesLimit)
+	if err != nil {
+		return nil, fmt.Errorf("échec de lecture des achats récents: %w", err)
+	}
+
+	if err := s.computeCollectionValue(stats); err != nil {
+		return nil, fmt.Errorf("échec du calcul de la valeur de la collection: %w", err)
+	}
+
+	loans, err := s.GetOpenLoans()
+	if err != nil {
+		return nil, fmt.Errorf("échec de lecture des prêts: %w", err)
+	}
+	stats.OverdueLoans = overdueLoans(loans, time.Now())
+
+	if err := s.computePlayStats(stats); err != nil {
+		return nil, fmt.Errorf("échec du calcul du suivi de jeu: %w", err)
+	}
+
+	return stats, nil
+}
+
+// computePlayStats counts the owned games by play status and their completion rate per platform
+func (s *sqlStore) computePlayStats(stats *DashboardStats) error {
+	ctx := context.Background()
+	err := s.db.QueryRow(ctx, `
+		SELECT
+			(SELECT COUNT(*) FROM games WHERE owned AND play_status = 'backlog'),
+			(SELECT COUNT(*) FROM games WHERE owned AND play_status = 'playing'),
+			(SELECT COALESCE(SUM(hours_played), 0) FROM games WHERE owned)
+	`).Scan(&stats.Backlog, &stats.Playing, &stats.HoursPlayed)
+	if err != nil {
+		return err
+	}
+
+	rows, err := s.db.Query(ctx, `
+		SELECT COALESCE(c.name, ''),
+			SUM(CASE WHEN g.play_status IN ('completed', 'full') THEN 1 ELSE 0 END),
+			COUNT(*)
+		FROM games g
+		LEFT JOIN consoles c ON g.console_id = c.console_id
+		WHERE g.owned
+		GROUP BY c.name
+		ORDER BY COUNT(*) DESC, c.name
+	`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var rate CompletionRate
+		if err := rows.Scan(&rate.Label, &rate.Finished, &rate.Total); err != nil {
+			return err
+		}
+		stats.CompletionRates = append(stats.CompletionRates, rate)
+	}
+	return rows.Err()
+}
+
+// getLabelCounts runs a query returning (label, count) rows
+func (s *sqlStore) getLabelCounts(query string) ([]LabelCount, error) {
+	rows, err := s.db.Query(context.Background(), query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var counts []LabelCount
+	for rows.Next() {
+		var lc LabelCount
+		if err := rows.Scan(&lc.Label, &lc.Count); err != nil {
+			return nil, err
+		}
+		counts = append(counts, lc)
+	}
+	return counts, rows.Err()
+}
+
+// getConditionCounts counts owned games, consoles and accessories by condition
+func (s *sqlStore) getConditionCounts() ([]ConditionCount, error) {
+	query := `
+		SELECT condition, COUNT(*)
+		FROM (
+			SELECT condition FROM games WHERE owned
+			UNION ALL
+			SELECT condition FROM consoles WHERE owned
+			UNION ALL
+			SELECT condition FROM accessories WHERE owned
+		) AS items
+		GROUP BY condition
+		ORDER BY condition IS NULL, condition DESC
+	`
+
+	rows, err := s.db.Query(context.Background(), query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var counts []ConditionCount
+	for rows.Next() {
+		var cc ConditionCount
+		if err := rows.Scan(&cc.Condition, &cc.Count); err != nil {
+			return nil, err
+		}
+		counts = append(counts, cc)
+	}
+	return counts, rows.Err()
+}
+
+// getRecentPurchases returns the latest dated game, console and accessory purchases, newest first
+// The tables are queried separately (a UNION would lose the DATE column type in SQLite)
+func (s *sqlStore) getRecentPurchases(limit int) ([]RecentPurchase, error) {
+	queries := []struct {
+		itemType string
+		query    string
+	}{
+		{"game", `
+			SELECT g.title, COALESCE(c.name, ''), g.purchase_date, g.purchase_price
+			FROM games g
+			LEFT JOIN consoles c ON g.console_id = c.console_id
+			WHERE g.purchase_date IS NOT NULL
+			ORDER BY g.purchase_date DESC
+			LIMIT $1
+		`},
+		{"console", `
+			SELECT c.name, COALESCE(m.name, ''), c.purchase_date, c.purchase_price
+			FROM consoles c
+			LEFT JOIN manufacturers m ON c.manufacturer_id = m.manufacturer_id
+			WHERE c.purchase_date IS NOT NULL
+			ORDER BY c.purchase_date DESC
+			LIMIT $1
+		`},
+		{"accessory", `
+			SELECT a.name, COALESCE(at.name, ''), a.purchase_date, a.purchase_price
+			FROM accessories a
+			LEFT JOIN accessory_types at ON a.type_id = at.type_id
+			WHERE a.purchase_date IS NOT NULL
+			ORDER BY a.purchase_date DESC
+			LIMIT $1
+		`},
+	}
+
+	var purchases []RecentPurchase
+	for _, q := range queries {
+		rows, err := s.db.Query(context.Background(), q.query, limit)
+		if err != nil {
+			return nil, err
+		}
+		for rows.Next() {
+			p := RecentPurchase{ItemType: q.itemType}
+			if err := rows.Scan(&p.Name, &p.Detail, &p.PurchaseDate, &p.PurchasePrice); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			purchases = append(purchases, p)
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Merge the lists
+	sort.SliceStable(purchases, func(i, j int) bool {
+		return purchases[i].PurchaseDate.After(purchases[j].PurchaseDate)
+	})
+	if len(purchases) > limit {
+		purchases = purchases[:limit]
+	}
+	return purchases, nil
+}
+
+// ========== Import Functions ==========
+// Imported rows reference lookup entries by name; names are matched case-insensitively
+
+// lookupTables gives the table and ID column of each lookup kind
+// Table names are fixed here, never taken from user input
+var lookupTables = map[LookupKind]struct{ table, idColumn string }{
+	LookupConsole:       {"consoles", "console_id"},
+	LookupGenre:         {"genres", "genre_id"},
+	LookupDeveloper:     {"developers", "developer_id"},
+	LookupComposer:      {"composers", "composer_id"},
+	LookupPublisher:     {"publishers", "publisher_id"},
+	LookupProducer:      {"producers", "producer_id"},
+	LookupManufacturer:  {"manufacturers", "manufacturer_id"},
+	LookupConsoleType:   {"console_types", "type_id"},
+	LookupAccessoryType: {"accessory_types", "type_id"},
+}
+
+// ImportCollection creates the missing lookup entries then inserts every imported row
+// in a single transaction: if one row fails, nothing is written
+func (s *sqlStore) ImportCollection(batch *ImportBatch) error {
+	return s.withTx(func(tx querier) error {
+		for _, entry := range batch.NewEntries {
+			t := lookupTables[entry.Kind]
+			_, err := tx.Exec(context.Background(),
+				"INSERT INTO "+t.table+" (name) VALUES ($1)", entry.Name)
+			if err != nil {
+				return fmt.Errorf("échec de création de '%s': %w", entry.Name, err)
+			}
+		}
+
+		for i := range batch.Consoles {
+			console := batch.Consoles[i]
+			err := resolveConsoleNames(tx, &console)
+			if err == nil {
+				_, err = saveConsoleTx(tx, &console)
+			}
+			if err != nil {
+				return fmt.Errorf("console '%s': %w", console.Name, err)
+			}
+		}
+
+		for i := range batch.Games {
+			game := batch.Games[i]
+			err := resolveGameNames(tx, &game)
+			if err == nil {
+				_, err = saveGameTx(tx, &game)
+			}
+			if err != nil {
+				return fmt.Errorf("jeu '%s': %w", game.Title, err)
+			}
+		}
+
+		for i := range batch.Accessories {
+			accessory := batch.Accessories[i]
+			err := resolveAccessoryNames(tx, &accessory)
+			if err == nil {
+				_, err = saveAccessoryTx(tx, &accessory)
+			}
+			if err != nil {
+				return fmt.Errorf("accessoire '%s': %w", accessory.Name, err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// resolveGameNames fills the console, genre and credit IDs of a game from their names
+func resolveGameNames(tx querier, game *Game) error {
+	var err error
+	if game.ConsoleID, err = findLookupID(tx, LookupConsole, game.ConsoleName); err != nil {
+		return err
+	}
+	if game.GenreID, err = findLookupID(tx, LookupGenre, game.GenreName); err != nil {
+		return err
+	}
+	if game.DeveloperIDs, err = findLookupIDs(tx, LookupDeveloper, game.Developers); err != nil {
+		return err
+	}
+	if game.ComposerIDs, err = findLookupIDs(tx, LookupComposer, game.Composers); err != nil {
+		return err
+	}
+	if game.PublisherIDs, err = findLookupIDs(tx, LookupPublisher, game.Publishers); err != nil {
+		return err
+	}
+	game.ProducerIDs, err = findLookupIDs(tx, LookupProducer, game.Producers)
+	return err
+}
+
+// resolveConsoleNames fills the type and manufacturer IDs of a console from their names
+func resolveConsoleNames(tx querier, console *Console) error {
+	var err error
+	if console.TypeID, err = findLookupID(tx, LookupConsoleType, console.TypeName); err != nil {
+		return err
+	}
+	console.ManufacturerID, err = findLookupID(tx, LookupManufacturer, console.ManufacturerName)
+	return err
+}
+
+// resolveAccessoryNames fills the type, manufacturer and console IDs of an accessory from their names
+func resolveAccessoryNames(tx querier, accessory *Accessory) error {
+	var err error
+	if accessory.TypeID, err = findLookupID(tx, LookupAccessoryType, accessory.TypeName); err != nil {
+		return err
+	}
+	if accessory.ManufacturerID, err = findLookupID(tx, LookupManufacturer, accessory.ManufacturerName); err != nil {
+		return err
+	}
+	accessory.ConsoleIDs, err = findLookupIDs(tx, LookupConsole, accessory.Consoles)
+	return err
+}
+
+// findLookupID returns the ID of a lookup entry by name, or nil for an empty name
+func findLookupID(tx querier, kind LookupKind, name string) (*int, error) {
+	if name == "" {
+		return nil, nil
+	}
+
+	t := lookupTables[kind]
+	var id int
+	err := tx.QueryRow(context.Background(),
+		"SELECT "+t.idColumn+" FROM "+t.table+" WHERE LOWER(name) = LOWER($1)", name).Scan(&id)
+	if err == errNoRows {
+		return nil, fmt.Errorf("'%s' introuvable dans %s", name, t.table)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &id, nil
+}
+
+// findLookupIDs resolves a list of names, skipping duplicates
+func findLookupIDs(tx querier, kind LookupKind, names []string) ([]int, error) {
+	var ids []int
+	for _, name := range names {
+		id, err := findLookupID(tx, kind, name)
+		if err != nil {
+			return nil, err
+		}
+		if id != nil && !slices.Contains(ids, *id) {
+			ids = append(ids, *id)
+		}
+	}
+	return ids, nil
+}
+
+// ========== Image Functions ==========
+// Rows of the images table point to files of the media cache (see images.go)
+
+// imageOrder lists box fronts first: the first image of an item is its cover
+const imageOrder = `
+	CASE kind WHEN 'box_front' THEN 1 WHEN 'box_back' THEN 2 WHEN 'cartridge' THEN 3 ELSE 4 END, image_id`
+
+// GetImages returns the images of a game, console or accessory, cover first
+func (s *sqlStore) GetImages(itemType string, itemID int) ([]ItemImage, error) {
+	rows, err := s.db.Query(context.Background(), `
+		SELECT image_id, item_type, item_id, kind, hash, extension
+		FROM images
+		WHERE item_type = $1 AND item_id = $2
+		ORDER BY`+imageOrder, itemType, itemID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var images []ItemImage
+	for rows.Next() {
+		var img ItemImage
+		if err := rows.Scan(&img.ImageID, &img.ItemType, &img.ItemID, &img.Kind, &img.Hash, &img.Extension); err != nil {
+			return nil, err
+		}
+		s.fillImagePaths(&img)
+		images = append(images, img)
+	}
+	return images, rows.Err()
+}
+
+// AddImage copies a picture into the media cache and attaches it to an item
+func (s *sqlStore) AddImage(itemType string, itemID int, kind string, sourcePath string) (*ItemImage, error) {
+	hash, ext, err := s.media.add(sourcePath)
+	if err != nil {
+		return nil, err
+	}
+
+	img := &ItemImage{ItemType: itemType, ItemID: itemID, Kind: kind, Hash: hash, Extension: ext}
+	err = s.db.QueryRow(context.Background(), `
+		INSERT INTO images (item_type, item_id, kind, hash, extension)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING image_id
+	`, itemType, itemID, kind, hash, ext).Scan(&img.ImageID)
+	if err != nil {
+		return nil, fmt.Errorf("échec d'ajout de l'image: %w", err)
+	}
+
+	s.fillImagePaths(img)
+	return img, nil
+}
+
+// DeleteImage detaches an image, removing its files once no item uses them anymore
+func (s *sqlStore) DeleteImage(imageID int) error {
+	var img ItemImage
+	err := s.db.QueryRow(context.Background(),
+		"DELETE FROM images WHERE image_id = $1 RETURNING hash, extension", imageID).Scan(&img.Hash, &img.Extension)
+	if err != nil {
+		return fmt.Errorf("échec de suppression de l'image: %w", err)
+	}
+
+	s.removeUnusedImageFiles([]ItemImage{img})
+	return nil
+}
+
+// fillImagePaths sets the file locations of an image, regenerating a missing thumbnail
+func (s *sqlStore) fillImagePaths(img *ItemImage) {
+	img.Path = s.media.imagePath(img.Hash, img.Extension)
+	img.ThumbPath = s.media.thumbPath(img.Hash)
+	if err := s.media.ensureThumbnail(img.Hash, img.Extension); err != nil {
+		log.Println("Unable to create thumbnail:", err)
+	}
+}
+
+// coverThumbPath returns the thumbnail of a cover hash read by the list queries ("" without image)
+func (s *sqlStore) coverThumbPath(hash *string) string {
+	if hash == nil {
+		return ""
+	}
+	return s.media.thumbPath(*hash)
+}
+
+// deleteItemImages removes the image rows of an item and returns them
+// The files are removed by removeUnusedImageFiles once the transaction is committed.
+func deleteItemImages(tx querier, itemType string, itemID int) ([]ItemImage, error) {
+	rows, err := tx.Query(context.Background(),
+		"SELECT hash, extension FROM images WHERE item_type = $1 AND item_id = $2", itemType, itemID)
+	if err != nil {
+		return nil, err
+	}
+
+	var images []ItemImage
+	for rows.Next() {
+		var img ItemImage
+		if err := rows.Scan(&img.Hash, &img.Extension); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		images = append(images, img)
+	}
+	err = rows.Err()
+	rows.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec(context.Background(),
+		"DELETE FROM images WHERE item_type = $1 AND item_id = $2", itemType, itemID)
+	if err != nil {
+		return nil, fmt.Errorf("échec de suppression des images: %w", err)
+	}
+	return images, nil
+}
+
+// removeUnusedImageFiles deletes the cached files of images no longer referenced
+// Failures are only logged: a leftover file wastes space but breaks nothing.
+func (s *sqlStore) removeUnusedImageFiles(images []ItemImage) {
+	for _, img := range images {
+		var count int
+		err := s.db.QueryRow(context.Background(), "SELECT COUNT(*) FROM images WHERE hash = $1", img.Hash).Scan(&count)
+		if err != nil {
+			log.Println("Unable to check image usage:", err)
+			continue
+		}
+		if count == 0 {
+			s.media.remove(img.Hash, img.Extension)
+		}
+	}
+}
+
+// ========== Saved Searches ==========
+
+// GetSavedSearches returns the saved searches of a tab, sorted by name
+func (s *sqlStore) GetSavedSearches(entityType string) ([]SavedSearch, error) {
+	rows, err := s.db.Query(context.Background(), `
+		SELECT search_id, entity_type, name, query
+		FROM saved_searches
+		WHERE entity_type = $1
+		ORDER BY LOWER(name)
+	`, entityType)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var searches []SavedSearch
+	for rows.Next() {
+		var search SavedSearch
+		if err := rows.Scan(&search.SearchID, &search.EntityType, &search.Name, &search.Query); err != nil {
+			return nil, err
+		}
+		searches = append(searches, search)
+	}
+	return searches, rows.Err()
+}
+
+// SaveSavedSearch inserts (SearchID == 0) or updates a saved search and returns its ID
+func (s *sqlStore) SaveSavedSearch(search *SavedSearch) (int, error) {
+	if search.SearchID == 0 {
+		var id int
+		err := s.db.QueryRow(context.Background(),
+			"INSERT INTO saved_searches (entity_type, name, query) VALUES ($1, $2, $3) RETURNING search_id",
+			search.EntityType, search.Name, search.Query).Scan(&id)
+		if err != nil {
+			return 0, fmt.Errorf("échec d'enregistrement de la recherche: %w", err)
+		}
+		return id, nil
+	}
+
+	_, err := s.db.Exec(context.Background(),
+		"UPDATE saved_searches SET name = $1, query = $2 WHERE search_id = $3",
+		search.Name, search.Query, search.SearchID)
+	if err != nil {
+		return 0, fmt.Errorf("échec d'enregistrement de la recherche: %w", err)
+	}
+	return search.SearchID, nil
+}
+
+// DeleteSavedSearch removes a saved search
+func (s *sqlStore) DeleteSavedSearch(searchID int) error {
+	if _, err := s.db.Exec(context.Background(), "DELETE FROM saved_searches WHERE search_id = $1", searchID); err != nil {
+		return fmt.Errorf("échec de suppression de la recherche: %w", err)
+	}
+	return nil
+}
+
+// ========== Wishlist Functions ==========
+
+// itemTables gives the table and ID column of each item type
+// Table names are fixed here, never taken from user input
+var itemTables = map[string]struct{ table, idColumn string }{
+	"game":      {"games", "game_id"},
+	"console":   {"consoles", "console_id"},
+	"accessory": {"accessories", "accessory_id"},
+}
+
+// MarkAsBought moves a wishlist item into the collection with its purchase details
+// The wishlist fields are kept, so the target price can still be compared with the price paid.
+// A nil condition keeps the condition already recorded.
+// A game gets a new copy for the purchase, with the wanted edition as its edition, unless it
+// still has copies from before it went back to the wishlist.
+func (s *sqlStore) MarkAsBought(itemType string, itemID int, purchase Purchase) error {
+	t, ok := itemTables[itemType]
+	if !ok {
+		return fmt.Errorf("type d'élément inconnu: %q", itemType)
+	}
+
+	err := s.withTx(func(tx querier) error {
+		n, err := tx.Exec(context.Background(),
+			"UPDATE "+t.table+" SET owned = TRUE, purchase_date = $1, purchase_price = $2, condition = COALESCE($3, condition) WHERE "+t.idColumn+" = $4",
+			purchase.Date, purchase.Price, purchase.Condition, itemID)
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return fmt.Errorf("élément introuvable (%s %d)", itemType, itemID)
+		}
+		if itemType != "game" {
+			return nil
+		}
+
+		// A game put back on the wishlist keeps its copies: buying it again updates the latest
+		// one rather than stacking a second copy
+		n, err = tx.Exec(context.Background(), `
+			UPDATE game_copies SET purchase_date = $1, purchase_price = $2, condition = COALESCE($3, condition)
+			WHERE copy_id = (SELECT MAX(copy_id) FROM game_copies WHERE game_id = $4)
+		`, purchase.Date, purchase.Price, purchase.Condition, itemID)
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			_, err = tx.Exec(context.Background(), `
+				INSERT INTO game_copies (game_id, edition, condition, box_owned, purchase_date, purchase_price)
+				SELECT game_id, wish_edition, condition, box_owned, purchase_date, purchase_price
+				FROM games
+				WHERE game_id = $1
+			`, itemID)
+			if err != nil {
+				return err
+			}
+		}
+		if err := fillCopyComponents(tx, itemID); err != nil {
+			return err
+		}
+		return syncGameCopies(tx, itemID)
+	})
+	if err != nil {
+		return fmt.Errorf("échec d'enregistrement de l'achat: %w", err)
+	}
+	return nil
+}
+
+// ========== Market Values Functions ==========
+
+// GetMarketValues returns the market values recorded for an item, newest first
+func (s *sqlStore) GetMarketValues(itemType string, itemID int) ([]MarketValue, error) {
+	rows, err := s.db.Query(context.Background(), `
+		SELECT value_id, item_type, item_id, grade, value, recorded_on, source
+		FROM market_values
+		WHERE item_type = $1 AND item_id = $2
+		ORDER BY recorded_on DESC, value_id DESC
+	`, itemType, itemID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var values []MarketValue
+	for rows.Next() {
+		var v MarketValue
+		if err := rows.Scan(&v.ValueID, &v.ItemType, &v.ItemID, &v.Grade, &v.Value, &v.RecordedOn, &v.Source); err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+	}
+	return values, rows.Err()
+}
+
+// AddMarketValues records market values in a single transaction
+// A price guide import is either recorded entirely or not at all.
+func (s *sqlStore) AddMarketValues(values []MarketValue) error {
+	err := s.withTx(func(tx querier) error {
+		for _, v := range values {
+			_, err := tx.Exec(context.Background(), `
+				INSERT INTO market_values (item_type, item_id, grade, value, recorded_on, source)
+				VALUES ($1, $2, $3, $4, $5, $6)
+			`, v.ItemType, v.ItemID, v.Grade, v.Value, v.RecordedOn, v.Source)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("échec d'enregistrement des cotes: %w", err)
+	}
+	return nil
+}
+
+// DeleteMarketValue removes a market value from the history of an item
+func (s *sqlStore) DeleteMarketValue(valueID int) error {
+	if _, err := s.db.Exec(context.Background(), "DELETE FROM market_values WHERE value_id = $1", valueID); err != nil {
+		return fmt.Errorf("échec de suppression de la cote: %w", err)
+	}
+	return nil
+}
+
+// deleteMarketValues removes the market values of an item being deleted
+func deleteMarketValues(tx querier, itemType string, itemID int) error {
+	_, err := tx.Exec(context.Background(),
+		"DELETE FROM market_values WHERE item_type = $1 AND item_id = $2", itemType, itemID)
+	if err != nil {
+		return fmt.Errorf("échec de suppression des cotes: %w", err)
+	}
+	return nil
+}
+
+// computeCollectionValue sums the current market value of the owned items into stats
+// Each item is valued with the latest value of its grade (the completeness of each copy for
+// games, loose for the others); the gain only covers the items that have both a value and a
+// purchase price.
+// Accessories are valued per unit, their purchase price covers the whole quantity.
+func (s *sqlStore) computeCollectionValue(stats *DashboardStats) error {
+	ctx := context.Background()
+
+	// Latest value of each item and grade: rows come oldest first, so the last one wins
+	type valueKey struct {
+		itemType string
+		itemID   int
+		grade    string
+	}
+	latest := make(map[valueKey]float64)
+	rows, err := s.db.Query(ctx, `
+		SELECT item_type, item_id, grade, value
+		FROM market_values
+		ORDER BY recorded_on, value_id
+	`)
+	if err != nil {
+		return err
+	}
+	for rows.Next() {
+		var key valueKey
+		var value float64
+		if err := rows.Scan(&key.itemType, &key.itemID, &key.grade, &value); err != nil {
+			rows.Close()
+			return err
+		}
+		latest[key] = value
+	}
+	err = rows.Err()
+	rows.Close()
+	if err != nil {
+		return err
+	}
+	if len(latest) == 0 {
+		return nil
+	}
+
+	// Owned items, queried separately for the same reason as getRecentPurchases
+	queries := []struct {
+		itemType string
+		query    string
+	}{
+		{"game", "SELECT c.game_id, c.copy_id, c.purchase_price, 1 FROM game_copies c JOIN games g ON c.game_id = g.game_id WHERE g.owned"},
+		{"console", "SELECT console_id, 0, purchase_price, 1 FROM consoles WHERE owned"},
+		{"accessory", "SELECT accessory_id, 0, purchase_price, quantity FROM accessories WHERE owned"},
+	}
+	completeness, err := s.loadCopyCompleteness(0)
+	if err != nil {
+		return err
+	}
+	for _, q := range queries {
+		rows, err := s.db.Query(ctx, q.query)
+		if err != nil {
+			return err
+		}
+		for rows.Next() {
+			var itemID, copyID, quantity int
+			var purchasePrice *float64
+			if err := rows.Scan(&itemID, &copyID, &purchasePrice, &quantity); err != nil {
+				rows.Close()
+				return err
+			}
+
+			grade := GradeLoose
+			if cc, found := completeness[copyID]; found && q.itemType == "game" {
+				grade = cc.status
+			}
+			value, found := latest[valueKey{q.itemType, itemID, grade}]
+			if !found {
+				value, found = latest[valueKey{q.itemType, itemID, GradeLoose}]
+			}
+			if !found {
+				continue
+			}
+			value *= float64(max(quantity, 1))
+			stats.CollectionValue += value
+			stats.ValuedItems++
+			if purchasePrice != nil {
+				stats.ValueGain += value - *purchasePrice
+			}
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ========== Game Copies Functions ==========
+
+// GetGameCopies returns the copies of a game in the order they were added
+func (s *sqlStore) GetGameCopies(gameID int) ([]GameCopy, error) {
+	rows, err := s.db.Query(context.Background(), `
+		SELECT copy_id, game_id, region, edition, condition, box_owned, manual_owned,
+			purchase_date, purchase_price, notes
+		FROM game_copies
+		WHERE game_id = $1
+		ORDER BY copy_id
+	`, gameID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var copies []GameCopy
+	for rows.Next() {
+		var c GameCopy
+		err := rows.Scan(&c.CopyID, &c.GameID, &c.Region, &c.Edition, &c.Condition, &c.BoxOwned, &c.ManualOwned,
+			&c.PurchaseDate, &c.PurchasePrice, &c.Notes)
+		if err != nil {
+			return nil, err
+		}
+		copies = append(copies, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	completeness, err := s.loadCopyCompleteness(gameID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range copies {
+		if cc, found := completeness[copies[i].CopyID]; found {
+			copies[i].Components = cc.components
+			copies[i].Completeness = cc.status
+			copies[i].Missing = cc.missing
+		}
+	}
+	return copies, nil
+}
+
+// SaveGameCopy inserts (CopyID == 0) or updates a copy and refreshes the summary of its game
+func (s *sqlStore) SaveGameCopy(gameCopy *GameCopy) (int, error) {
+	copyID := gameCopy.CopyID
+	err := s.withTx(func(tx querier) error {
+		if copyID == 0 {
+			err := tx.QueryRow(context.Background(), `
+				INSERT INTO game_copies (game_id, region, edition, condition, box_owned, manual_owned,
+					purchase_date, purchase_price, notes)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+				RETURNING copy_id
+			`, gameCopy.GameID, gameCopy.Region, gameCopy.Edition, gameCopy.Condition, gameCopy.BoxOwned, gameCopy.ManualOwned,
+				gameCopy.PurchaseDate, gameCopy.PurchasePrice, gameCopy.Notes).Scan(&copyID)
+			if err != nil {
+				return err
+			}
+		} else {
+			_, err := tx.Exec(context.Background(), `
+				UPDATE game_copies SET
+					region = $1, edition = $2, condition = $3, box_owned = $4, manual_owned = $5,
+					purchase_date = $6, purchase_price = $7, notes = $8
+				WHERE copy_id = $9
+			`, gameCopy.Region, gameCopy.Edition, gameCopy.Condition, gameCopy.BoxOwned, gameCopy.ManualOwned,
+				gameCopy.PurchaseDate, gameCopy.PurchasePrice, gameCopy.Notes, copyID)
+			if err != nil {
+				return err
+			}
+		}
+
+		var err error
+		switch {
+		case gameCopy.Components != nil:
+			err = saveCopyComponents(tx, copyID, gameCopy.Components)
+		case gameCopy.CopyID == 0:
+			err = fillCopyComponents(tx, gameCopy.GameID)
+		default:
+			err = syncCopyFlagComponents(tx, copyID)
+		}
+		if err != nil {
+			return err
+		}
+		return syncGameCopies(tx, gameCopy.GameID)
+	})
+	if err != nil {
+		return 0, fmt.Errorf("échec d'enregistrement de l'exemplaire: %w", err)
+	}
+	return copyID, nil
+}
+
+// DeleteGameCopy removes a copy and refreshes the summary of its game
+// The last copy of an owned game is kept: the game leaves the collection with "Possédé" instead.
+func (s *sqlStore) DeleteGameCopy(copyID int) error {
+	return s.withTx(func(tx querier) error {
+		var gameID, count int
+		var owned bool
+		err := tx.QueryRow(context.Background(), `
+			SELECT g.game_id, g.owned, (SELECT COUNT(*) FROM game_copies WHERE game_id = g.game_id)
+			FROM game_copies c
+			JOIN games g ON c.game_id = g.game_id
+			WHERE c.copy_id = $1
+		`, copyID).Scan(&gameID, &owned, &count)
+		if err != nil {
+			return fmt.Errorf("exemplaire introuvable: %w", err)
+		}
+		if owned && count == 1 {
+			return fmt.Errorf("un jeu possédé garde au moins un exemplaire: décochez « Possédé » ou supprimez le jeu")
+		}
+
+		if _, err := tx.Exec(context.Background(), "DELETE FROM game_copies WHERE copy_id = $1", copyID); err != nil {
+			return fmt.Errorf("échec de suppression de l'exemplaire: %w", err)
+		}
+		return syncGameCopies(tx, gameID)
+	})
+}
+
+// ensureGameCopy gives an owned game without copies a first copy taken from its own columns
+func ensureGameCopy(tx querier, gameID int) error {
+	_, err := tx.Exec(context.Background(), `
+		INSERT INTO game_copies (game_id, condition, box_owned, purchase_date, purchase_price)
+		SELECT game_id, condition, box_owned, purchase_date, purchase_price
+		FROM games
+		WHERE game_id = $1 AND NOT EXISTS (SELECT 1 FROM game_copies WHERE game_id = $1)
+	`, gameID)
+	if err != nil {
+		return fmt.Errorf("échec d'ajout de l'exemplaire: %w", err)
+	}
+	return fillCopyComponents(tx, gameID)
+}
+
+// syncGameCopies rewrites the copy columns of a game from its copies
+// Best condition, box when any copy has one, latest purchase date and total price paid;
+// a game without copies keeps its columns as they are.
+func syncGameCopies(tx querier, gameID int) error {
+	_, err := tx.Exec(context.Background(), `
+		UPDATE games SET
+			condition = (SELECT MAX(condition) FROM game_copies WHERE game_id = $1),
+			box_owned = EXISTS (SELECT 1 FROM game_copies WHERE game_id = $1 AND box_owned),
+			purchase_date = (SELECT MAX(purchase_date) FROM game_copies WHERE game_id = $1),
+			purchase_price = (SELECT SUM(purchase_price) FROM game_copies WHERE game_id = $1)
+		WHERE game_id = $1 AND EXISTS (SELECT 1 FROM game_copies WHERE game_id = $1)
+	`, gameID)
+	if err != nil {
+		return fmt.Errorf("échec de mise à jour des exemplaires: %w", err)
+	}
+	return nil
+}
+
+// ========== Completeness Functions ==========
+
+// GetCompletenessComponents returns the components of every console type, in checklist order
+func (s *sqlStore) GetCompletenessComponents() ([]CompletenessComponent, error) {
+	rows, err := s.db.Query(context.Background(), `
+		SELECT cc.component_id, cc.console_type_id, COALESCE(ct.name, ''), cc.name, cc.role, cc.required_for_cib, cc.position
+		FROM completeness_components cc
+		LEFT JOIN console_types ct ON cc.console_type_id = ct.type_id
+		ORDER BY cc.position, cc.component_id
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var components []CompletenessComponent
+	for rows.Next() {
+		var c CompletenessComponent
+		if err := rows.Scan(&c.ComponentID, &c.ConsoleTypeID, &c.ConsoleTypeName, &c.Name, &c.Role, &c.RequiredForCIB, &c.Position); err != nil {
+			return nil, err
+		}
+		components = append(components, c)
+	}
+	return components, rows.Err()
+}
+
+// GetGameComponents returns the components that apply to a game, from the type of its console
+func (s *sqlStore) GetGameComponents(gameID int) ([]CompletenessComponent, error) {
+	var consoleTypeID *int
+	err := s.db.QueryRow(context.Background(), `
+		SELECT c.type_id
+		FROM games g
+		LEFT JOIN consoles c ON g.console_id = c.console_id
+		WHERE g.game_id = $1
+	`, gameID).Scan(&consoleTypeID)
+	if err != nil {
+		return nil, err
+	}
+
+	components, err := s.GetCompletenessComponents()
+	if err != nil {
+		return nil, err
+	}
+	return applicableComponents(components, consoleTypeID), nil
+}
+
+// SaveCompletenessComponent inserts (ComponentID == 0) or updates a component and returns its ID
+func (s *sqlStore) SaveCompletenessComponent(component *CompletenessComponent) (int, error) {
+	if component.ComponentID == 0 {
+		var id int
+		err := s.db.QueryRow(context.Background(), `
+			INSERT INTO completeness_components (console_type_id, name, role, required_for_cib, position)
+			VALUES ($1, $2, $3, $4, $5)
+			RETURNING component_id
+		`, component.ConsoleTypeID, component.Name, component.Role, component.RequiredForCIB, component.Position).Scan(&id)
+		if err != nil {
+			return 0, fmt.Errorf("échec d'enregistrement de l'élément: %w", err)
+		}
+		return id, nil
+	}
+
+	_, err := s.db.Exec(context.Background(), `
+		UPDATE completeness_components
+		SET console_type_id = $1, name = $2, role = $3, required_for_cib = $4, position = $5
+		WHERE component_id = $6
+	`, component.ConsoleTypeID, component.Name, component.Role, component.RequiredForCIB, component.Position, component.ComponentID)
+	if err != nil {
+		return 0, fmt.Errorf("échec d'enregistrement de l'élément: %w", err)
+	}
+	return component.ComponentID, nil
+}
+
+// DeleteCompletenessComponent removes a component and unchecks it from every copy
+func (s *sqlStore) DeleteCompletenessComponent(componentID int) error {
+	err := s.withTx(func(tx querier) error {
+		if _, err := tx.Exec(context.Background(), "DELETE FROM copy_components WHERE component_id = $1", componentID); err != nil {
+			return err
+		}
+		_, err := tx.Exec(context.Background(), "DELETE FROM completeness_components WHERE component_id = $1", componentID)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("échec de suppression de l'élément: %w", err)
+	}
+	return nil
+}
+
+// copyCompletenessRow is the computed completeness of a copy
+type copyCompletenessRow struct {
+	gameID     int
+	components []int
+	status     string
+	missing    []string
+}
+
+// loadCopyCompleteness computes the completeness of the copies of a game (every copy for 0), by copy ID
+func (s *sqlStore) loadCopyCompleteness(gameID int) (map[int]*copyCompletenessRow, error) {
+	ctx := context.Background()
+	components, err := s.GetCompletenessComponents()
+	if err != nil {
+		return nil, err
+	}
+
+	// Copies with the console type of their game
+	copies := make(map[int]*copyCompletenessRow)
+	consoleTypes := make(map[int]*int)
+	rows, err := s.db.Query(ctx, `
+		SELECT gc.copy_id, gc.game_id, c.type_id
+		FROM game_copies gc
+		JOIN games g ON gc.game_id = g.game_id
+		LEFT JOIN consoles c ON g.console_id = c.console_id
+		WHERE $1 = 0 OR gc.game_id = $1
+	`, gameID)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var copyID int
+		row := &copyCompletenessRow{}
+		var consoleTypeID *int
+		if err := rows.Scan(&copyID, &row.gameID, &consoleTypeID); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		copies[copyID] = row
+		consoleTypes[copyID] = consoleTypeID
+	}
+	err = rows.Err()
+	rows.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	// Checked components
+	rows, err = s.db.Query(ctx, `
+		SELECT cp.copy_id, cp.component_id
+		FROM copy_components cp
+		JOIN game_copies gc ON cp.copy_id = gc.copy_id
+		WHERE $1 = 0 OR gc.game_id = $1
+		ORDER BY cp.component_id
+	`, gameID)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var copyID, componentID int
+		if err := rows.Scan(&copyID, &componentID); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		if row, found := copies[copyID]; found {
+			row.components = append(row.components, componentID)
+		}
+	}
+	err = rows.Err()
+	rows.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	for copyID, row := range copies {
+		present := make(map[int]bool)
+		for _, id := range row.components {
+			present[id] = true
+		}
+		row.status, row.missing = copyCompleteness(applicableComponents(components, consoleTypes[copyID]), present)
+	}
+	return copies, nil
+}
+
+// saveCopyComponents replaces the checklist of a copy; box_owned and manual_owned follow it
+func saveCopyComponents(tx querier, copyID int, componentIDs []int) error {
+	ctx := context.Background()
+	if _, err := tx.Exec(ctx, "DELETE FROM copy_components WHERE copy_id = $1", copyID); err != nil {
+		return err
+	}
+	for _, componentID := range componentIDs {
+		_, err := tx.Exec(ctx, "INSERT INTO copy_components (copy_id, component_id) VALUES ($1, $2)", copyID, componentID)
+		if err != nil {
+			return fmt.Errorf("échec d'enregistrement de la complétude: %w", err)
+		}
+	}
+
+	_, err := tx.Exec(ctx, `
+		UPDATE game_copies SET
+			box_owned = EXISTS (
+				SELECT 1 FROM copy_components cp
+				JOIN completeness_components cc ON cp.component_id = cc.component_id
+				WHERE cp.copy_id = $1 AND cc.role = 'box'),
+			manual_owned = EXISTS (
+				SELECT 1 FROM copy_components cp
+				JOIN completeness_components cc ON cp.component_id = cc.component_id
+				WHERE cp.copy_id = $1 AND cc.role = 'manual')
+		WHERE copy_id = $1
+	`, copyID)
+	return err
+}
+
+// syncCopyFlagComponents checks the box and manual components of a copy from box_owned and manual_owned
+// Used when a copy is saved without a checklist; the other components are left alone.
+func syncCopyFlagComponents(tx querier, copyID int) error {
+	ctx := context.Background()
+	_, err := tx.Exec(ctx, `
+		DELETE FROM copy_components
+		WHERE copy_id = $1 AND component_id IN (
+			SELECT component_id FROM completeness_components WHERE role IN ('box', 'manual'))
+	`, copyID)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(ctx, `
+		INSERT INTO copy_components (copy_id, component_id)
+		SELECT gc.copy_id, cc.component_id
+		FROM game_copies gc
+		JOIN completeness_components cc ON (cc.role = 'box' AND gc.box_owned) OR (cc.role = 'manual' AND gc.manual_owned)
+		WHERE gc.copy_id = $1
+	`, copyID)
+	return err
+}
+
+// fillCopyComponents gives the copies of a game without checklist their cartridge or disc,
+// plus the box and manual they are marked with
+func fillCopyComponents(tx querier, gameID int) error {
+	_, err := tx.Exec(context.Background(), `
+		INSERT INTO copy_components (copy_id, component_id)
+		SELECT gc.copy_id, cc.component_id
+		FROM game_copies gc
+		JOIN completeness_components cc ON cc.role = 'media'
+			OR (cc.role = 'box' AND gc.box_owned)
+			OR (cc.role = 'manual' AND gc.manual_owned)
+		WHERE gc.game_id = $1 AND NOT EXISTS (SELECT 1 FROM copy_components cp WHERE cp.copy_id = gc.copy_id)
+	`, gameID)
+	if err != nil {
+		return fmt.Errorf("échec d'enregistrement de la complétude: %w", err)
+	}
+	return nil
+}
+
+// ========== Loans Functions ==========
+
+// GetLoans returns the loan history of an item, newest first
+func (s *sqlStore) GetLoans(itemType string, itemID int) ([]Loan, error) {
+	return s.listLoans(`
+		SELECT loan_id, item_type, item_id, '', borrower, lent_on, due_on, returned_on, notes
+		FROM loans
+		WHERE item_type = $1 AND item_id = $2
+		ORDER BY lent_on DESC, loan_id DESC
+	`, itemType, itemID)
+}
+
+// GetOpenLoans returns the items currently lent with their name, oldest loan first
+func (s *sqlStore) GetOpenLoans() ([]Loan, error) {
+	return s.listLoans(`
+		SELECT l.loan_id, l.item_type, l.item_id,
+			COALESCE(g.title, c.name, a.name, ''),
+			l.borrower, l.lent_on, l.due_on, l.returned_on, l.notes
+		FROM loans l
+		LEFT JOIN games g ON l.item_type = 'game' AND l.item_id = g.game_id
+		LEFT JOIN consoles c ON l.item_type = 'console' AND l.item_id = c.console_id
+		LEFT JOIN accessories a ON l.item_type = 'accessory' AND l.item_id = a.accessory_id
+		WHERE l.returned_on IS NULL
+		ORDER BY l.lent_on, l.loan_id
+	`)
+}
+
+func (s *sqlStore) listLoans(query string, args ...any) ([]Loan, error) {
+	rows, err := s.db.Query(context.Background(), query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var loans []Loan
+	for rows.Next() {
+		var l Loan
+		if err := rows.Scan(&l.LoanID, &l.ItemType, &l.ItemID, &l.ItemName, &l.Borrower, &l.LentOn, &l.DueOn, &l.ReturnedOn, &l.Notes); err != nil {
+			return nil, err
+		}
+		loans = append(loans, l)
+	}
+	return loans, rows.Err()
+}
+
+// SaveLoan records a new loan (LoanID == 0) or updates one, e.g. when the item comes back
+// An item can only be lent once at a time.
+func (s *sqlStore) SaveLoan(loan *Loan) (int, error) {
+	loanID := loan.LoanID
+	err := s.withTx(func(tx querier) error {
+		if loan.ReturnedOn == nil {
+			var borrower string
+			err := tx.QueryRow(context.Background(), `
+				SELECT borrower FROM loans
+				WHERE item_type = $1 AND item_id = $2 AND returned_on IS NULL AND loan_id <> $3
+			`, loan.ItemType, loan.ItemID, loanID).Scan(&borrower)
+			if err == nil {
+				return fmt.Errorf("déjà prêté à %s: marquez d'abord ce prêt comme rendu", borrower)
+			}
+			if err != errNoRows {
+				return err
+			}
+		}
+
+		if loanID == 0 {
+			return tx.QueryRow(context.Background(), `
+				INSERT INTO loans (item_type, item_id, borrower, lent_on, due_on, returned_on, notes)
+				VALUES ($1, $2, $3, $4, $5, $6, $7)
+				RETURNING loan_id
+			`, loan.ItemType, loan.ItemID, loan.Borrower, loan.LentOn, loan.DueOn, loan.ReturnedOn, loan.Notes).Scan(&loanID)
+		}
+		_, err := tx.Exec(context.Background(), `
+			UPDATE loans SET borrower = $1, lent_on = $2, due_on = $3, returned_on = $4, notes = $5
+			WHERE loan_id = $6
+		`, loan.Borrower, loan.LentOn, loan.DueOn, loan.ReturnedOn, loan.Notes, loanID)
+		return err
+	})
+	if err != nil {
+		return 0, fmt.Errorf("échec d'enregistrement du prêt: %w", err)
+	}
+	return loanID, nil
+}
+
+// DeleteLoan removes a loan from the history of an item
+func (s *sqlStore) DeleteLoan(loanID int) error {
+	if _, err := s.db.Exec(context.Background(), "DELETE FROM loans WHERE loan_id = $1", loanID); err != nil {
+		return fmt.Errorf("échec de suppression du prêt: %w", err)
+	}
+	return nil
+}
+
+// deleteLoans removes the loans of an item being deleted
+func deleteLoans(tx querier, itemType string, itemID int) error {
+	_, err := tx.Exec(context.Background(),
+		"DELETE FROM loans WHERE item_type = $1 AND item_id = $2", itemType, itemID)
+	if err != nil {
+		return fmt.Errorf("échec de suppression des prêts: %w", err)
+	}
+	return nil
+}
+
+// ========== Backup Functions ==========
+
+// WriteBackup writes every table of backupTables and the pictures they use as a zip archive
+// (see backup.go). The tables are read in one transaction, so they are consistent.
+func (s *sqlStore) WriteBackup(w io.Writer) (*BackupManifest, error) {
+	ctx := context.Background()
+	manifest := &BackupManifest{
+		Format:    backupFormatVersion,
+		CreatedAt: time.Now(),
+		Driver:    s.dialect.name,
+		Tables:    make(map[string]int),
+	}
+	archive := zip.NewWriter(w)
+
+	var images []ItemImage
+	err := s.withTx(func(tx querier) error {
+		if s.dialect.snapshot != "" {
+			if _, err := tx.Exec(ctx, s.dialect.snapshot); err != nil {
+				return err
+			}
+		}
+
+		var err error
+		if manifest.SchemaVersion, err = schemaVersion(tx); err != nil {
+			return err
+		}
+		for _, table := range backupTables {
+			dump, err := dumpTable(ctx, tx, s.dialect, table, "")
+			if err != nil {
+				return err
+			}
+			if err := writeBackupJSON(archive, backupTablesDir+table+".json", manifest.CreatedAt, dump); err != nil {
+				return err
+			}
+			manifest.Tables[table] = len(dump.Rows)
+		}
+
+		r, err := tx.Query(ctx, "SELECT DISTINCT hash, extension FROM images")
+		if err != nil {
+			return err
+		}
+		defer r.Close()
+		for r.Next() {
+			var img ItemImage
+			if err := r.Scan(&img.Hash, &img.Extension); err != nil {
+				return err
+			}
+			images = append(images, img)
+		}
+		return r.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, img := range images {
+		content, err := os.ReadFile(s.media.imagePath(img.Hash, img.Extension))
+		if err != nil {
+			log.Println("Picture missing from the backup:", err)
+			continue
+		}
+		f, err := createBackupEntry(archive, backupMediaName(img.Hash, img.Extension), manifest.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := f.Write(content); err != nil {
+			return nil, err
+		}
+		manifest.Images++
+	}
+
+	if err := writeBackupJSON(archive, backupManifestFile, manifest.CreatedAt, manifest); err != nil {
+		return nil, err
+	}
+	if err := archive.Close(); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// RestoreBackup replaces every table with the content of an archive made by WriteBackup
+// The tables are dropped and rebuilt up to the schema version of the archive, filled with
+// its rows, then migrated to the current version, all in one transaction: on error the
+// database is left untouched.
+func (s *sqlStore) RestoreBackup(archive *zip.Reader) error {
+	ctx := context.Background()
+	manifest, err := readBackupManifest(archive)
+	if err != nil {
+		return err
+	}
+
+	migrations, err := loadMigrations(s.dialect.migrations, s.dialect.dir)
+	if err != nil {
+		return err
+	}
+	latest := 0
+	if len(migrations) > 0 {
+		latest = migrations[len(migrations)-1].Version
+	}
+	if manifest.SchemaVersion > latest {
+		return fmt.Errorf("sauvegarde faite par une version plus récente de VGC (schéma %d, %d au plus pris en charge)", manifest.SchemaVersion, latest)
+	}
+
+	// Read everything before touching the database
+	tables := make(map[string]*backupTable)
+	for table, count := range manifest.Tables {
+		var data backupTable
+		if err := readBackupJSON(archive, backupTablesDir+table+".json", &data); err != nil {
+			return fmt.Errorf("sauvegarde incomplète: %w", err)
+		}
+		if len(data.Rows) != count {
+			return fmt.Errorf("sauvegarde incomplète: %d ligne(s) dans %s au lieu de %d", len(data.Rows), table, count)
+		}
+		tables[table] = &data
+	}
+
+	// Pictures are named after their content: extra files are harmless if the restore fails
+	for _, f := range archive.File {
+		name, found := strings.CutPrefix(f.Name, backupMediaDir)
+		if !found {
+			continue
+		}
+		if err := s.restoreMediaFile(f, name); err != nil {
+			return err
+		}
+	}
+
+	return s.withTx(func(tx querier) error {
+		for _, table := range slices.Backward(backupTables) {
+			if _, err := tx.Exec(ctx, fmt.Sprintf(s.dialect.dropTable, table)); err != nil {
+				return fmt.Errorf("suppression de %s impossible: %w", table, err)
+			}
+		}
+		if _, err := tx.Exec(ctx, "DELETE FROM schema_migrations"); err != nil {
+			return err
+		}
+
+		for _, m := range migrations {
+			if m.Version <= manifest.SchemaVersion {
+				if err := runMigration(ctx, tx, m); err != nil {
+					return err
+				}
+			}
+		}
+
+		// The archive has its own copy of the reference data seeded by the migrations
+		for _, table := range slices.Backward(backupTables) {
+			if tables[table] == nil {
+				continue // Not in the schema of the archive
+			}
+			if _, err := tx.Exec(ctx, "DELETE FROM "+table); err != nil {
+				return err
+			}
+		}
+		for _, table := range backupTables {
+			if data := tables[table]; data != nil {
+				if err := loadTable(ctx, tx, s.dialect, table, data); err != nil {
+					return err
+				}
+				// New rows must not reuse the restored IDs
+				if len(data.Rows) > 0 {
+					if err := resetTableSequence(ctx, tx, s.dialect, table); err != nil {
+						return err
+					}
+				}
+			}
+		}
+
+		for _, m := range migrations {
+			if m.Version > manifest.SchemaVersion {
+				if err := runMigration(ctx, tx, m); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// restoreMediaFile copies a picture of an archive into the media cache
+func (s *sqlStore) restoreMediaFile(f *zip.File, name string) error {
+	ext := path.Ext(name)
+	hash := strings.TrimSuffix(name, ext)
+	if len(hash) != sha256.Size*2 {
+		return fmt.Errorf("fichier %s inattendu dans la sauvegarde", f.Name)
+	}
+
+	r, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	content, err := io.ReadAll(io.LimitReader(r, maxImageFileSize+1))
+	if err != nil {
+		return fmt.Errorf("lecture de %s impossible: %w", f.Name, err)
+	}
+
+	sum := sha256.Sum256(content)
+	if hex.EncodeToString(sum[:]) != hash {
+		return fmt.Errorf("image %s endommagée dans la sauvegarde", f.Name)
+	}
+	return writeCacheFile(s.media.imagePath(hash, ext), content)
+}
+
+// ========== Undo Functions ==========
+
+// SnapshotItem reads every row of an item listed by itemRowSets (see undo.go)
+// withMedia also keeps the picture files of its images, for a deletion to be undone.
+func (s *sqlStore) SnapshotItem(itemType string, itemID int, withMedia bool) (*ItemSnapshot, error) {
+	sets, ok := itemRowSets[itemType]
+	if !ok {
+		return nil, fmt.Errorf("type d'élément inconnu: %q", itemType)
+	}
+
+	ctx := context.Background()
+	snapshot := &ItemSnapshot{Tables: make(map[string]*backupTable)}
+	err := s.withTx(func(tx querier) error {
+		for _, set := range sets {
+			rows, err := dumpTable(ctx, tx, s.dialect, set.Table, set.Where, itemID)
+			if err != nil {
+				return err
+			}
+			snapshot.Tables[set.Table] = rows
+		}
+		return nil
+	})
+	if err != nil || !withMedia {
+		return snapshot, err
+	}
+
+	images := snapshot.Tables["images"]
+	hash, ext := slices.Index(images.Columns, "hash"), slices.Index(images.Columns, "extension")
+	snapshot.Media = make(map[string][]byte)
+	for _, row := range images.Rows {
+		img := ItemImage{Hash: fmt.Sprint(row[hash]), Extension: fmt.Sprint(row[ext])}
+		content, err := os.ReadFile(s.media.imagePath(img.Hash, img.Extension))
+		if err != nil {
+			log.Println("Picture missing from the undo history:", err)
+			continue
+		}
+		snapshot.Media[img.Hash+img.Extension] = content
+	}
+	return snapshot, nil
+}
+
+// RestoreItem replaces the rows of an item with those of a snapshot, keeping their IDs
+// The main row is updated in place, so the games of a console keep pointing to it; the other
+// rows are deleted and inserted again. An empty snapshot deletes the item; its picture files
+// stay in the cache for a redo.
+func (s *sqlStore) RestoreItem(itemType string, itemID int, snapshot *ItemSnapshot) error {
+	sets, ok := itemRowSets[itemType]
+	if !ok {
+		return fmt.Errorf("type d'élément inconnu: %q", itemType)
+	}
+
+	for name, content := range snapshot.Media {
+		ext := path.Ext(name)
+		if err := writeCacheFile(s.media.imagePath(strings.TrimSuffix(name, ext), ext), content); err != nil {
+			return fmt.Errorf("restauration des images impossible: %w", err)
+		}
+	}
+
+	ctx := context.Background()
+	main, children := sets[0], sets[1:]
+	return s.withTx(func(tx querier) error {
+		for _, set := range slices.Backward(children) {
+			if _, err := tx.Exec(ctx, "DELETE FROM "+set.Table+" WHERE "+set.Where, itemID); err != nil {
+				return err
+			}
+		}
+
+		if snapshot.exists(itemType) {
+			rows := snapshot.Tables[main.Table]
+			updated, err := updateTableRow(ctx, tx, s.dialect, main.Table, main.Where, itemID, rows)
+			if err != nil {
+				return err
+			}
+			if !updated {
+				if err := loadTable(ctx, tx, s.dialect, main.Table, rows); err != nil {
+					return err
+				}
+			}
+		} else if _, err := tx.Exec(ctx, "DELETE FROM "+main.Table+" WHERE "+main.Where, itemID); err != nil {
+			return err
+		}
+
+		for _, set := range children {
+			if rows := snapshot.Tables[set.Table]; rows != nil {
+				if err := loadTable(ctx, tx, s.dialect, set.Table, rows); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+)
+
+// ========== Database Drivers ==========
+// The SQL store runs the same queries on PostgreSQL (pgx) and SQLite (database/sql).
+// These small interfaces hide the differences between the two driver APIs.
+// Queries always use $N placeholders, which both drivers understand.
+
+// errNoRows is returned by row.Scan when a query matched nothing, whatever the driver
+var errNoRows = sql.ErrNoRows
+
+// querier runs statements on a connection or inside a transaction
+type querier interface {
+	Exec(ctx context.Context, query string, args ...any) (int64, error) // returns rows affected
+	Query(ctx context.Context, query string, args ...any) (rows, error)
+	QueryRow(ctx context.Context, query string, args ...any) row
+}
+
+// rows is a result set being iterated
+type rows interface {
+	Next() bool
+	Scan(dest ...any) error
+	Err() error
+	Close()
+}
+
+// row is a single-row result
+type row interface {
+	Scan(dest ...any) error
+}
+
+// database is an open connection to one of the supported backends
+type database interface {
+	querier
+	Begin(ctx context.Context) (transaction, error)
+	Ping(ctx context.Context) error
+	Close()
+}
+
+// transaction is a querier whose statements are committed or rolled back together
+type transaction interface {
+	querier
+	Commit(ctx context.Context) error
+	Rollback(ctx context.Context) error
+}
@@ -1,17 +1,17 @@
 package main
 
 import (
-	"context"
 	"fmt"
 	"image/color"
+	"slices"
 	"strings"
+	"time"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/canvas"
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/dialog"
 	"fyne.io/fyne/v2/widget"
-	"github.com/jackc/pgx/v5"
 )
 
 // ========== DIALOG HELPERS ==========
@@ -49,13 +49,13 @@ func createDialogButtons(cancelFunc, saveFunc func()) (*widget.Button, *widget.B
 // Returns: entry field and a container wrapping entry + suggestions list
 func createAutocompleteSelector(
 	w fyne.Window,
-	conn *pgx.Conn,
+	store CollectionStore,
 	options []string,
 	nameToID map[string]int,
 	selectedItems *[]string,
 	selectedIDs *[]int,
 	displayLabel *widget.Label,
-	addNewDialog func(fyne.Window, *pgx.Conn, func()),
+	addNewDialog func(fyne.Window, CollectionStore, func()),
 	refreshCallback func() ([]string, map[string]int),
 ) (*widget.Entry, *fyne.Container) {
 
@@ -134,7 +134,7 @@ func createAutocompleteSelector(
 		}
 
 		// Show add dialog
-		addNewDialog(w, conn, func() {
+		addNewDialog(w, store, func() {
 			// Refresh the options after adding
 			newOptions, newNameToID := refreshCallback()
 			options = newOptions
@@ -188,6 +188,9 @@ type gameFormData struct {
 	purchaseDateEntry  *widget.Entry
 	purchasePriceEntry *widget.Entry
 	notesEntry         *widget.Entry
+	wishlist           *wishlistFields
+	play               *playFields
+	copiesHint         *widget.Label
 
 	// Many-to-many relationship data
 	selectedDevelopers   []string
@@ -216,7 +219,7 @@ type gameFormData struct {
 
 // buildGameForm creates the complete game form with all fields and many-to-many relationships
 // If existingGame is provided, the form is pre-populated for editing
-func buildGameForm(w fyne.Window, conn *pgx.Conn, existingGame *Game) *gameFormData {
+func buildGameForm(w fyne.Window, store CollectionStore, existingGame *Game) *gameFormData {
 	formData := &gameFormData{
 		consoleMap: make(map[string]int),
 		genreMap:   make(map[string]int),
@@ -224,13 +227,13 @@ func buildGameForm(w fyne.Window, conn *pgx.Conn, existingGame *Game) *gameFormD
 	}
 
 	// Fetch all lookup data needed for dropdowns
-	consoles, _ := getConsoles(conn)
-	genres, _ := getGenres(conn)
-	ratings, _ := getRatingSystems(conn)
-	developers, _ := getDevelopers(conn)
-	composers, _ := getComposers(conn)
-	publishers, _ := getPublishers(conn)
-	producers, _ := getProducers(conn)
+	consoles, _ := store.GetConsoles()
+	genres, _ := store.GetGenres()
+	ratings, _ := store.GetRatingSystems()
+	developers, _ := store.GetDevelopers()
+	composers, _ := store.GetComposers()
+	publishers, _ := store.GetPublishers()
+	producers, _ := store.GetProducers()
 
 	// ========== Basic Info Fields ==========
 
@@ -371,6 +374,16 @@ func buildGameForm(w fyne.Window, conn *pgx.Conn, existingGame *Game) *gameFormD
 		formData.collectorCheck.Checked = *existingGame.Collector
 	}
 
+	// Wishlist fields, shown while the game is not owned
+	if existingGame != nil {
+		formData.wishlist = newWishlistFields(formData.ownedCheck, existingGame.TargetPrice, existingGame.WishPriority, existingGame.WishEdition)
+	} else {
+		formData.wishlist = newWishlistFields(formData.ownedCheck, nil, nil, nil)
+	}
+
+	// Play tracking
+	formData.play = newPlayFields(existingGame)
+
 	// Condition slider (1-5 stars)
 	formData.conditionSlider = widget.NewSlider(1, 5)
 	formData.conditionSlider.Step = 1
@@ -400,6 +413,19 @@ func buildGameForm(w fyne.Window, conn *pgx.Conn, existingGame *Game) *gameFormD
 		formData.purchasePriceEntry.SetText(fmt.Sprintf("%.2f", *existingGame.PurchasePrice))
 	}
 
+	// Once the game has copies, these fields summarize them and are edited per copy
+	formData.copiesHint = widget.NewLabelWithStyle("", fyne.TextAlignLeading, fyne.TextStyle{Italic: true})
+	formData.copiesHint.Wrapping = fyne.TextWrapWord
+	if existingGame != nil && len(existingGame.Copies) > 0 {
+		formData.copiesHint.SetText(fmt.Sprintf("%d exemplaire(s): la boîte, l'état et l'achat se modifient par exemplaire dans les détails du jeu.", len(existingGame.Copies)))
+		formData.boxOwnedCheck.Disable()
+		formData.conditionSlider.Disable()
+		formData.purchaseDateEntry.Disable()
+		formData.purchasePriceEntry.Disable()
+	} else {
+		formData.copiesHint.Hide()
+	}
+
 	// ========== Notes Field ==========
 
 	formData.notesEntry = widget.NewMultiLineEntry()
@@ -434,7 +460,7 @@ func buildGameForm(w fyne.Window, conn *pgx.Conn, existingGame *Game) *gameFormD
 
 	// Create autocomplete widget
 	_, developerAutocomplete := createAutocompleteSelector(
-		w, conn,
+		w, store,
 		developerOptions,
 		developerNameToID,
 		&formData.selectedDevelopers,
@@ -442,7 +468,7 @@ func buildGameForm(w fyne.Window, conn *pgx.Conn, existingGame *Game) *gameFormD
 		formData.developersList,
 		showAddDeveloperDialog,
 		func() ([]string, map[string]int) {
-			developers, _ := getDevelopers(conn)
+			developers, _ := store.GetDevelopers()
 			opts := []string{}
 			nameToID := make(map[string]int)
 			for _, d := range developers {
@@ -482,7 +508,7 @@ func buildGameForm(w fyne.Window, conn *pgx.Conn, existingGame *Game) *gameFormD
 	}
 
 	_, composerAutocomplete := createAutocompleteSelector(
-		w, conn,
+		w, store,
 		composerOptions,
 		composerNameToID,
 		&formData.selectedComposers,
@@ -490,7 +516,7 @@ func buildGameForm(w fyne.Window, conn *pgx.Conn, existingGame *Game) *gameFormD
 		formData.composersList,
 		showAddComposerDialog,
 		func() ([]string, map[string]int) {
-			composers, _ := getComposers(conn)
+			composers, _ := store.GetComposers()
 			opts := []string{}
 			nameToID := make(map[string]int)
 			for _, c := range composers {
@@ -530,7 +556,7 @@ func buildGameForm(w fyne.Window, conn *pgx.Conn, existingGame *Game) *gameFormD
 	}
 
 	_, publisherAutocomplete := createAutocompleteSelector(
-		w, conn,
+		w, store,
 		publisherOptions,
 		publisherNameToID,
 		&formData.selectedPublishers,
@@ -538,7 +564,7 @@ func buildGameForm(w fyne.Window, conn *pgx.Conn, existingGame *Game) *gameFormD
 		formData.publishersList,
 		showAddPublisherDialog,
 		func() ([]string, map[string]int) {
-			publishers, _ := getPublishers(conn)
+			publishers, _ := store.GetPublishers()
 			opts := []string{}
 			nameToID := make(map[string]int)
 			for _, p := range publishers {
@@ -578,7 +604,7 @@ func buildGameForm(w fyne.Window, conn *pgx.Conn, existingGame *Game) *gameFormD
 	}
 
 	_, producerAutocomplete := createAutocompleteSelector(
-		w, conn,
+		w, store,
 		producerOptions,
 		producerNameToID,
 		&formData.selectedProducers,
@@ -586,7 +612,7 @@ func buildGameForm(w fyne.Window, conn *pgx.Conn, existingGame *Game) *gameFormD
 		formData.producersList,
 		showAddProducerDialog,
 		func() ([]string, map[string]int) {
-			producers, _ := getProducers(conn)
+			producers, _ := store.GetProducers()
 			opts := []string{}
 			nameToID := make(map[string]int)
 			for _, p := range producers {
@@ -659,11 +685,13 @@ func buildGameForm(w fyne.Window, conn *pgx.Conn, existingGame *Game) *gameFormD
 
 		widget.NewSeparator(),
 		widget.NewLabel("Informations de collection"),
+		formData.copiesHint,
 		formData.ownedCheck,
 		formData.boxOwnedCheck,
 		formData.collectorCheck,
 		formData.conditionLabel,
 		formData.conditionSlider,
+		formData.wishlist.section,
 
 		widget.NewSeparator(),
 		widget.NewLabel("Informations d'achat"),
@@ -672,6 +700,8 @@ func buildGameForm(w fyne.Window, conn *pgx.Conn, existingGame *Game) *gameFormD
 		widget.NewLabel("Prix d'achat:"),
 		formData.purchasePriceEntry,
 
+		formData.play.section,
+
 		widget.NewSeparator(),
 		widget.NewLabel("Notes"),
 		formData.notesEntry,
@@ -682,9 +712,10 @@ func buildGameForm(w fyne.Window, conn *pgx.Conn, existingGame *Game) *gameFormD
 
 // ========== GAME DIALOG FUNCTIONS ==========
 
-// showAddGameDialog displays a dialog to add a new game to the database
-func showAddGameDialog(w fyne.Window, conn *pgx.Conn, onSuccess func()) {
-	formData := buildGameForm(w, conn, nil) // nil = new game, not editing
+// showAddGameDialog displays a dialog to add a new game to the collection (owned) or the wishlist
+func showAddGameDialog(w fyne.Window, store CollectionStore, owned bool, onSuccess func()) {
+	formData := buildGameForm(w, store, nil) // nil = new game, not editing
+	formData.ownedCheck.SetChecked(owned)    // Unchecked when adding to the wishlist
 
 	var d dialog.Dialog // Declare early so buttons can reference it
 
@@ -693,14 +724,12 @@ func showAddGameDialog(w fyne.Window, conn *pgx.Conn, onSuccess func()) {
 		func() { d.Hide() }, // Cancel action
 		func() {
 			// Save action
-			gameID, err := saveGame(conn, formData, 0) // 0 = INSERT mode
+			_, err := saveGame(store, formData, 0) // 0 = INSERT mode
 			if err != nil {
 				dialog.ShowError(err, w)
 				return
 			}
 
-			saveManyToManyRelationships(conn, gameID, formData)
-
 			dialog.ShowInformation("Enregistré", "Jeu ajouté à la base de données", w)
 			if onSuccess != nil {
 				onSuccess()
@@ -730,35 +759,27 @@ func showAddGameDialog(w fyne.Window, conn *pgx.Conn, onSuccess func()) {
 }
 
 // showEditGameDialog displays a dialog to edit an existing game
-func showEditGameDialog(w fyne.Window, conn *pgx.Conn, gameID int, onSuccess func()) {
+func showEditGameDialog(w fyne.Window, store CollectionStore, gameID int, onSuccess func()) {
 	// Fetch existing game data
-	existingGame, err := getGameByID(conn, gameID)
+	existingGame, err := store.GetGameByID(gameID)
 	if err != nil {
 		dialog.ShowError(fmt.Errorf("échec de chargement du jeu: %w", err), w)
 		return
 	}
 
-	formData := buildGameForm(w, conn, existingGame) // Pre-populate form
+	formData := buildGameForm(w, store, existingGame) // Pre-populate form
 
 	var d dialog.Dialog
 
 	_, _, buttonBar := createDialogButtons(
 		func() { d.Hide() },
 		func() {
-			_, err := saveGame(conn, formData, gameID) // gameID != 0 = UPDATE mode
+			_, err := saveGame(store, formData, gameID) // gameID != 0 = UPDATE mode
 			if err != nil {
 				dialog.ShowError(err, w)
 				return
 			}
 
-			// Delete old relationships and save new ones
-			conn.Exec(context.Background(), "DELETE FROM game_developers WHERE game_id = $1", gameID)
-			conn.Exec(context.Background(), "DELETE FROM game_composers WHERE game_id = $1", gameID)
-			conn.Exec(context.Background(), "DELETE FROM game_publishers WHERE game_id = $1", gameID)
-			conn.Exec(context.Background(), "DELETE FROM game_producers WHERE game_id = $1", gameID)
-
-			saveManyToManyRelationships(conn, gameID, formData)
-
 			dialog.ShowInformation("Mise à jour", "Jeu mis à jour dans la base de données.", w)
 			if onSuccess != nil {
 				onSuccess()
@@ -788,171 +809,94 @@ func showEditGameDialog(w fyne.Window, conn *pgx.Conn, gameID int, onSuccess fun
 
 // ========== GAME SAVE FUNCTIONS ==========
 
-// saveGame inserts or updates a game in the database
+// saveGame converts the form into a Game and hands it to the store
 // If gameID == 0, performs INSERT; otherwise performs UPDATE
-func saveGame(conn *pgx.Conn, formData *gameFormData, gameID int) (int, error) {
-	// Validate required fields
-	if formData.titleEntry.Text == "" {
-		return 0, fmt.Errorf("titre requis")
-	}
-	if formData.consoleSelect.Selected == "" {
-		return 0, fmt.Errorf("plateforme requise")
+func saveGame(store CollectionStore, formData *gameFormData, gameID int) (int, error) {
+	game := &Game{
+		GameID: gameID,
+		Title:  formData.titleEntry.Text,
+		Owned:  formData.ownedCheck.Checked,
 	}
 
 	// Convert dropdown selections to IDs
-	var consoleID *int
 	if formData.consoleSelect.Selected != "" {
 		id := formData.consoleMap[formData.consoleSelect.Selected]
-		consoleID = &id
+		game.ConsoleID = &id
 	}
 
-	var genreID *int
 	if formData.genreSelect.Selected != "" {
 		id := formData.genreMap[formData.genreSelect.Selected]
-		genreID = &id
+		game.GenreID = &id
 	}
 
 	// Parse date fields (convert to nil if empty)
-	var jpReleaseDate, usReleaseDate, euReleaseDate, purchaseDate *string
-	if formData.jpReleaseDateEntry.Text != "" {
-		jpReleaseDate = &formData.jpReleaseDateEntry.Text
+	var err error
+	if game.JPReleaseDate, err = parseDateEntry(formData.jpReleaseDateEntry.Text); err != nil {
+		return 0, err
 	}
-	if formData.usReleaseDateEntry.Text != "" {
-		usReleaseDate = &formData.usReleaseDateEntry.Text
+	if game.USReleaseDate, err = parseDateEntry(formData.usReleaseDateEntry.Text); err != nil {
+		return 0, err
 	}
-	if formData.euReleaseDateEntry.Text != "" {
-		euReleaseDate = &formData.euReleaseDateEntry.Text
+	if game.EUReleaseDate, err = parseDateEntry(formData.euReleaseDateEntry.Text); err != nil {
+		return 0, err
 	}
-	if formData.purchaseDateEntry.Text != "" {
-		purchaseDate = &formData.purchaseDateEntry.Text
+	if game.PurchaseDate, err = parseDateEntry(formData.purchaseDateEntry.Text); err != nil {
+		return 0, err
 	}
 
 	// Parse rating selections
-	var jpRatingID, usRatingID, euRatingID *int
 	if formData.jpRatingSelect.Selected != "" {
 		id := formData.ratingMap[formData.jpRatingSelect.Selected]
-		jpRatingID = &id
+		game.JPRatingID = &id
 	}
 	if formData.usRatingSelect.Selected != "" {
 		id := formData.ratingMap[formData.usRatingSelect.Selected]
-		usRatingID = &id
+		game.USRatingID = &id
 	}
 	if formData.euRatingSelect.Selected != "" {
 		id := formData.ratingMap[formData.euRatingSelect.Selected]
-		euRatingID = &id
+		game.EURatingID = &id
 	}
 
 	// Parse numeric fields
-	var unitsSold *int
-	if formData.unitsSoldEntry.Text != "" {
-		var units int
-		fmt.Sscanf(formData.unitsSoldEntry.Text, "%d", &units)
-		unitsSold = &units
-	}
+	game.UnitsSold = parseIntEntry(formData.unitsSoldEntry.Text)
 
-	var purchasePrice *float64
 	if formData.purchasePriceEntry.Text != "" {
 		var price float64
 		fmt.Sscanf(formData.purchasePriceEntry.Text, "%f", &price)
-		purchasePrice = &price
+		game.PurchasePrice = &price
 	}
 
-	var condition *int
 	if formData.conditionSlider.Value > 0 {
 		c := int(formData.conditionSlider.Value)
-		condition = &c
+		game.Condition = &c
 	}
 
+	// Checkboxes always have a value
+	boxOwned := formData.boxOwnedCheck.Checked
+	collector := formData.collectorCheck.Checked
+	game.BoxOwned = &boxOwned
+	game.Collector = &collector
+
 	// Parse notes
-	var notes *string
-	if formData.notesEntry.Text != "" {
-		notes = &formData.notesEntry.Text
-	}
-
-	// Execute INSERT or UPDATE based on gameID
-	if gameID == 0 {
-		// INSERT new game
-		query := `
-			INSERT INTO games (
-				title, console_id, genre_id, 
-				jp_release_date, us_release_date, eu_release_date,
-				jp_rating_id, us_rating_id, eu_rating_id,
-				units_sold, owned, box_owned, collector, condition,
-				purchase_date, purchase_price, notes
-			)
-			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
-			RETURNING game_id
-		`
-
-		err := conn.QueryRow(context.Background(), query,
-			formData.titleEntry.Text, consoleID, genreID,
-			jpReleaseDate, usReleaseDate, euReleaseDate,
-			jpRatingID, usRatingID, euRatingID,
-			unitsSold, formData.ownedCheck.Checked, formData.boxOwnedCheck.Checked,
-			formData.collectorCheck.Checked, condition, purchaseDate, purchasePrice, notes,
-		).Scan(&gameID)
-
-		if err != nil {
-			return 0, fmt.Errorf("échec d'ajout du jeu: %w", err)
-		}
-		return gameID, nil
-	} else {
-		// UPDATE existing game
-		query := `
-			UPDATE games SET
-				title = $1, console_id = $2, genre_id = $3,
-				jp_release_date = $4, us_release_date = $5, eu_release_date = $6,
-				jp_rating_id = $7, us_rating_id = $8, eu_rating_id = $9,
-				units_sold = $10, owned = $11, box_owned = $12, collector = $13,
-				condition = $14, purchase_date = $15, purchase_price = $16, notes = $17
-			WHERE game_id = $18
-		`
-
-		_, err := conn.Exec(context.Background(), query,
-			formData.titleEntry.Text, consoleID, genreID,
-			jpReleaseDate, usReleaseDate, euReleaseDate,
-			jpRatingID, usRatingID, euRatingID,
-			unitsSold, formData.ownedCheck.Checked, formData.boxOwnedCheck.Checked,
-			formData.collectorCheck.Checked, condition, purchaseDate, purchasePrice, notes,
-			gameID,
-		)
-
-		if err != nil {
-			return 0, fmt.Errorf("échec de mise à jour du jeu: %w", err)
-		}
-		return gameID, nil
-	}
-}
+	game.Notes = optionalText(formData.notesEntry.Text)
 
-// saveManyToManyRelationships saves all many-to-many relationship entries for a game
-func saveManyToManyRelationships(conn *pgx.Conn, gameID int, formData *gameFormData) {
-	// Insert developers
-	for _, devID := range formData.selectedDeveloperIDs {
-		conn.Exec(context.Background(),
-			"INSERT INTO game_developers (game_id, developer_id) VALUES ($1, $2)",
-			gameID, devID)
-	}
+	game.TargetPrice, game.WishPriority, game.WishEdition = formData.wishlist.values()
 
-	// Insert composers
-	for _, compID := range formData.selectedComposerIDs {
-		conn.Exec(context.Background(),
-			"INSERT INTO game_composers (game_id, composer_id) VALUES ($1, $2)",
-			gameID, compID)
+	if err := formData.play.apply(game); err != nil {
+		return 0, err
 	}
 
-	// Insert publishers
-	for _, pubID := range formData.selectedPublisherIDs {
-		conn.Exec(context.Background(),
-			"INSERT INTO game_publishers (game_id, publisher_id) VALUES ($1, $2)",
-			gameID, pubID)
-	}
+	// Many-to-many relationships
+	game.DeveloperIDs = formData.selectedDeveloperIDs
+	game.ComposerIDs = formData.selectedComposerIDs
+	game.PublisherIDs = formData.selectedPublisherIDs
+	game.ProducerIDs = formData.selectedProducerIDs
 
-	// Insert producers
-	for _, prodID := range formData.selectedProducerIDs {
-		conn.Exec(context.Background(),
-			"INSERT INTO game_producers (game_id, producer_id) VALUES ($1, $2)",
-			gameID, prodID)
+	if err := validateGame(game); err != nil {
+		return 0, err
 	}
+	return store.SaveGame(game)
 }
 
 // ========== HELPER FUNCTIONS ==========
@@ -982,10 +926,52 @@ func formatList(items []string) string {
 	return result
 }
 
+// parseDateEntry parses an AAAA-MM-JJ entry, returning nil for an empty field
+func parseDateEntry(text string) (*time.Time, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, nil
+	}
+	date, err := time.Parse("2006-01-02", text)
+	if err != nil {
+		return nil, fmt.Errorf("date invalide '%s' (format attendu: AAAA-MM-JJ)", text)
+	}
+	return &date, nil
+}
+
+// parseIntEntry parses an integer entry, returning nil for an empty field
+func parseIntEntry(text string) *int {
+	if text == "" {
+		return nil
+	}
+	var value int
+	fmt.Sscanf(text, "%d", &value)
+	return &value
+}
+
+// parsePriceEntry parses a price entry, returning nil for an empty field
+func parsePriceEntry(text string) *float64 {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil
+	}
+	var value float64
+	fmt.Sscanf(strings.Replace(text, ",", ".", 1), "%f", &value)
+	return &value
+}
+
+// optionalText returns nil for an empty field, or a pointer to a copy of the text
+func optionalText(text string) *string {
+	if text == "" {
+		return nil
+	}
+	return &text
+}
+
 // ========== LOOKUP ENTRY DIALOGS ==========
 // Simple dialogs for adding new entries to lookup tables
 
-func showAddDeveloperDialog(w fyne.Window, conn *pgx.Conn, onSuccess func()) {
+func showAddDeveloperDialog(w fyne.Window, store CollectionStore, onSuccess func()) {
 	nameEntry := widget.NewEntry()
 	nameEntry.SetPlaceHolder("Nom du développeur")
 
@@ -996,9 +982,7 @@ func showAddDeveloperDialog(w fyne.Window, conn *pgx.Conn, onSuccess func()) {
 
 	d := dialog.NewCustomConfirm("Ajouter", "Enregistrer", "Annuler", form, func(save bool) {
 		if save && nameEntry.Text != "" {
-			_, err := conn.Exec(context.Background(),
-				"INSERT INTO developers (name) VALUES ($1)",
-				nameEntry.Text)
+			_, err := store.AddDeveloper(nameEntry.Text)
 			if err != nil {
 				dialog.ShowError(err, w)
 				return
@@ -1013,7 +997,7 @@ func showAddDeveloperDialog(w fyne.Window, conn *pgx.Conn, onSuccess func()) {
 	d.Show()
 }
 
-func showAddComposerDialog(w fyne.Window, conn *pgx.Conn, onSuccess func()) {
+func showAddComposerDialog(w fyne.Window, store CollectionStore, onSuccess func()) {
 	nameEntry := widget.NewEntry()
 	nameEntry.SetPlaceHolder("Nom du compositeur")
 
@@ -1024,9 +1008,7 @@ func showAddComposerDialog(w fyne.Window, conn *pgx.Conn, onSuccess func()) {
 
 	d := dialog.NewCustomConfirm("Ajouter", "Enregistrer", "Annuler", form, func(save bool) {
 		if save && nameEntry.Text != "" {
-			_, err := conn.Exec(context.Background(),
-				"INSERT INTO composers (name) VALUES ($1)",
-				nameEntry.Text)
+			_, err := store.AddComposer(nameEntry.Text)
 			if err != nil {
 				dialog.ShowError(err, w)
 				return
@@ -1041,7 +1023,7 @@ func showAddComposerDialog(w fyne.Window, conn *pgx.Conn, onSuccess func()) {
 	d.Show()
 }
 
-func showAddPublisherDialog(w fyne.Window, conn *pgx.Conn, onSuccess func()) {
+func showAddPublisherDialog(w fyne.Window, store CollectionStore, onSuccess func()) {
 	nameEntry := widget.NewEntry()
 	nameEntry.SetPlaceHolder("Nom de l'éditeur")
 
@@ -1052,9 +1034,7 @@ func showAddPublisherDialog(w fyne.Window, conn *pgx.Conn, onSuccess func()) {
 
 	d := dialog.NewCustomConfirm("Ajouter", "Enregistrer", "Annuler", form, func(save bool) {
 		if save && nameEntry.Text != "" {
-			_, err := conn.Exec(context.Background(),
-				"INSERT INTO publishers (name) VALUES ($1)",
-				nameEntry.Text)
+			_, err := store.AddPublisher(nameEntry.Text)
 			if err != nil {
 				dialog.ShowError(err, w)
 				return
@@ -1069,7 +1049,7 @@ func showAddPublisherDialog(w fyne.Window, conn *pgx.Conn, onSuccess func()) {
 	d.Show()
 }
 
-func showAddProducerDialog(w fyne.Window, conn *pgx.Conn, onSuccess func()) {
+func showAddProducerDialog(w fyne.Window, store CollectionStore, onSuccess func()) {
 	nameEntry := widget.NewEntry()
 	nameEntry.SetPlaceHolder("Nom du producteur")
 
@@ -1080,9 +1060,7 @@ func showAddProducerDialog(w fyne.Window, conn *pgx.Conn, onSuccess func()) {
 
 	d := dialog.NewCustomConfirm("Ajouter", "Enregistrer", "Annuler", form, func(save bool) {
 		if save && nameEntry.Text != "" {
-			_, err := conn.Exec(context.Background(),
-				"INSERT INTO producers (name) VALUES ($1)",
-				nameEntry.Text)
+			_, err := store.AddProducer(nameEntry.Text)
 			if err != nil {
 				dialog.ShowError(err, w)
 				return
@@ -1101,9 +1079,10 @@ func showAddProducerDialog(w fyne.Window, conn *pgx.Conn, onSuccess func()) {
 // These dialogs display all information about an item in a read-only card format
 
 // showGameDetailDialog displays all game information in a read-only view
-func showGameDetailDialog(w fyne.Window, conn *pgx.Conn, gameID int, onEdit func()) {
+// onChanged is called when images, copies or loans are changed from the dialog
+func showGameDetailDialog(w fyne.Window, store CollectionStore, gameID int, onEdit func(), onChanged func()) {
 	// Fetch game data
-	game, err := getGameByID(conn, gameID)
+	game, err := store.GetGameByID(gameID)
 	if err != nil {
 		dialog.ShowError(fmt.Errorf("échec de chargement: %w", err), w)
 		return
@@ -1118,6 +1097,9 @@ func showGameDetailDialog(w fyne.Window, conn *pgx.Conn, gameID int, onEdit func
 		widget.NewSeparator(),
 	)
 
+	// Box art and photos
+	content = append(content, createImageSection(w, store, "game", gameID, onChanged), widget.NewSeparator())
+
 	// Basic Info
 	content = append(content, widget.NewLabel("Informations générales"))
 	content = append(content, widget.NewLabel(fmt.Sprintf("Plateforme: %s", game.ConsoleName)))
@@ -1179,17 +1161,37 @@ func showGameDetailDialog(w fyne.Window, conn *pgx.Conn, gameID int, onEdit func
 		content = append(content, widget.NewLabel(fmt.Sprintf("État: %s", conditionToStars(game.Condition))))
 	}
 
-	// Purchase Info
+	content = append(content, wishlistDetails(game.Owned, game.TargetPrice, game.WishPriority, game.WishEdition)...)
+
+	// Purchase Info (summary of the copies: latest date, total price)
 	if game.PurchaseDate != nil || game.PurchasePrice != nil {
 		content = append(content, widget.NewSeparator(), widget.NewLabel("Achat"))
 		if game.PurchaseDate != nil {
 			content = append(content, widget.NewLabel(fmt.Sprintf("Date: %s", game.PurchaseDate.Format("2006-01-02"))))
 		}
-		if game.PurchasePrice != nil {
+		if game.PurchasePrice != nil && len(game.Copies) > 1 {
+			content = append(content, widget.NewLabel(fmt.Sprintf("Prix total: %.2f", *game.PurchasePrice)))
+		} else if game.PurchasePrice != nil {
 			content = append(content, widget.NewLabel(fmt.Sprintf("Prix: %.2f", *game.PurchasePrice)))
 		}
 	}
 
+	// Play tracking
+	content = append(content, playDetails(game)...)
+
+	// Copies
+	if game.Owned || len(game.Copies) > 0 {
+		content = append(content, widget.NewSeparator(), createGameCopiesSection(w, store, gameID, onChanged))
+	}
+
+	// Loans (only owned items can be lent)
+	if game.Owned {
+		content = append(content, widget.NewSeparator(), createLoanSection(w, store, "game", gameID, onChanged))
+	}
+
+	// Market value
+	content = append(content, widget.NewSeparator(), createMarketValueSection(w, store, "game", gameID, gameValuationGrades(game), game.PurchasePrice))
+
 	// Notes
 	if game.Notes != nil && *game.Notes != "" {
 		content = append(content, widget.NewSeparator(), widget.NewLabel("Notes"))
@@ -1229,8 +1231,9 @@ func showGameDetailDialog(w fyne.Window, conn *pgx.Conn, gameID int, onEdit func
 }
 
 // showConsoleDetailDialog displays all console information in a read-only view
-func showConsoleDetailDialog(w fyne.Window, conn *pgx.Conn, consoleID int, onEdit func()) {
-	console, err := getConsoleByID(conn, consoleID)
+// onChanged is called when images or loans are changed from the dialog
+func showConsoleDetailDialog(w fyne.Window, store CollectionStore, consoleID int, onEdit func(), onChanged func()) {
+	console, err := store.GetConsoleByID(consoleID)
 	if err != nil {
 		dialog.ShowError(fmt.Errorf("échec de chargement: %w", err), w)
 		return
@@ -1244,6 +1247,9 @@ func showConsoleDetailDialog(w fyne.Window, conn *pgx.Conn, consoleID int, onEdi
 		widget.NewSeparator(),
 	)
 
+	// Box art and photos
+	content = append(content, createImageSection(w, store, "console", consoleID, onChanged), widget.NewSeparator())
+
 	// Basic Info
 	content = append(content, widget.NewLabel("Informations générales"))
 	content = append(content, widget.NewLabel(fmt.Sprintf("Type: %s", console.TypeName)))
@@ -1327,6 +1333,26 @@ func showConsoleDetailDialog(w fyne.Window, conn *pgx.Conn, consoleID int, onEdi
 	if console.Condition != nil {
 		content = append(content, widget.NewLabel(fmt.Sprintf("État: %s", conditionToStars(console.Condition))))
 	}
+	content = append(content, wishlistDetails(console.Owned, console.TargetPrice, console.WishPriority, console.WishEdition)...)
+
+	// Purchase Info
+	if console.PurchaseDate != nil || console.PurchasePrice != nil {
+		content = append(content, widget.NewSeparator(), widget.NewLabel("Achat"))
+		if console.PurchaseDate != nil {
+			content = append(content, widget.NewLabel(fmt.Sprintf("Date: %s", console.PurchaseDate.Format("2006-01-02"))))
+		}
+		if console.PurchasePrice != nil {
+			content = append(content, widget.NewLabel(fmt.Sprintf("Prix: %.2f", *console.PurchasePrice)))
+		}
+	}
+
+	// Loans (only owned items can be lent)
+	if console.Owned {
+		content = append(content, widget.NewSeparator(), createLoanSection(w, store, "console", consoleID, onChanged))
+	}
+
+	// Market value
+	content = append(content, widget.NewSeparator(), createMarketValueSection(w, store, "console", consoleID, []string{GradeLoose}, console.PurchasePrice))
 
 	// Notes
 	if console.Notes != nil && *console.Notes != "" {
@@ -1362,8 +1388,9 @@ func showConsoleDetailDialog(w fyne.Window, conn *pgx.Conn, consoleID int, onEdi
 }
 
 // showAccessoryDetailDialog displays all accessory information in a read-only view
-func showAccessoryDetailDialog(w fyne.Window, conn *pgx.Conn, accessoryID int, onEdit func()) {
-	accessory, err := getAccessoryByID(conn, accessoryID)
+// onChanged is called when images or loans are changed from the dialog
+func showAccessoryDetailDialog(w fyne.Window, store CollectionStore, accessoryID int, onEdit func(), onChanged func()) {
+	accessory, err := store.GetAccessoryByID(accessoryID)
 	if err != nil {
 		dialog.ShowError(fmt.Errorf("échec de chargement: %w", err), w)
 		return
@@ -1377,6 +1404,9 @@ func showAccessoryDetailDialog(w fyne.Window, conn *pgx.Conn, accessoryID int, o
 		widget.NewSeparator(),
 	)
 
+	// Box art and photos
+	content = append(content, createImageSection(w, store, "accessory", accessoryID, onChanged), widget.NewSeparator())
+
 	// Basic Info
 	content = append(content, widget.NewLabel("Informations générales"))
 	content = append(content, widget.NewLabel(fmt.Sprintf("Type: %s", accessory.TypeName)))
@@ -1401,6 +1431,8 @@ func showAccessoryDetailDialog(w fyne.Window, conn *pgx.Conn, accessoryID int, o
 		content = append(content, widget.NewLabel(fmt.Sprintf("État: %s", conditionToStars(accessory.Condition))))
 	}
 
+	content = append(content, wishlistDetails(accessory.Owned, accessory.TargetPrice, accessory.WishPriority, accessory.WishEdition)...)
+
 	// Purchase Info
 	if accessory.PurchaseDate != nil || accessory.PurchasePrice != nil {
 		content = append(content, widget.NewSeparator(), widget.NewLabel("Achat"))
@@ -1412,6 +1444,14 @@ func showAccessoryDetailDialog(w fyne.Window, conn *pgx.Conn, accessoryID int, o
 		}
 	}
 
+	// Loans (only owned items can be lent)
+	if accessory.Owned {
+		content = append(content, widget.NewSeparator(), createLoanSection(w, store, "accessory", accessoryID, onChanged))
+	}
+
+	// Market value
+	content = append(content, widget.NewSeparator(), createMarketValueSection(w, store, "accessory", accessoryID, slices.Repeat([]string{GradeLoose}, max(accessory.Quantity, 1)), accessory.PurchasePrice))
+
 	// Notes
 	if accessory.Notes != nil && *accessory.Notes != "" {
 		content = append(content, widget.NewSeparator(), widget.NewLabel("Notes"))
@@ -1461,6 +1501,7 @@ type accessoryFormData struct {
 	purchaseDateEntry  *widget.Entry
 	purchasePriceEntry *widget.Entry
 	notesEntry         *widget.Entry
+	wishlist           *wishlistFields
 
 	// Many-to-many: compatible consoles
 	selectedConsoles   []string
@@ -1475,16 +1516,16 @@ type accessoryFormData struct {
 }
 
 // buildAccessoryForm creates the accessory form (similar to buildGameForm pattern)
-func buildAccessoryForm(w fyne.Window, conn *pgx.Conn, existingAccessory *Accessory) *accessoryFormData {
+func buildAccessoryForm(w fyne.Window, store CollectionStore, existingAccessory *Accessory) *accessoryFormData {
 	formData := &accessoryFormData{
 		typeMap:         make(map[string]int),
 		manufacturerMap: make(map[string]int),
 	}
 
 	// Fetch lookup data
-	types, _ := getAccessoryTypes(conn)
-	manufacturers, _ := getManufacturers(conn)
-	consoles, _ := getConsoles(conn)
+	types, _ := store.GetAccessoryTypes()
+	manufacturers, _ := store.GetManufacturers()
+	consoles, _ := store.GetConsoles()
 
 	// Name field (required)
 	formData.nameEntry = widget.NewEntry()
@@ -1585,8 +1626,10 @@ func buildAccessoryForm(w fyne.Window, conn *pgx.Conn, existingAccessory *Access
 	formData.ownedCheck = widget.NewCheck("Possédé", nil)
 	if existingAccessory != nil {
 		formData.ownedCheck.Checked = existingAccessory.Owned
+		formData.wishlist = newWishlistFields(formData.ownedCheck, existingAccessory.TargetPrice, existingAccessory.WishPriority, existingAccessory.WishEdition)
 	} else {
 		formData.ownedCheck.Checked = true
+		formData.wishlist = newWishlistFields(formData.ownedCheck, nil, nil, nil)
 	}
 
 	// Condition slider
@@ -1653,6 +1696,7 @@ func buildAccessoryForm(w fyne.Window, conn *pgx.Conn, existingAccessory *Access
 		formData.ownedCheck,
 		formData.conditionLabel,
 		formData.conditionSlider,
+		formData.wishlist.section,
 
 		widget.NewSeparator(),
 		widget.NewLabel("Informations d'achat"),
@@ -1669,27 +1713,21 @@ func buildAccessoryForm(w fyne.Window, conn *pgx.Conn, existingAccessory *Access
 	return formData
 }
 
-func showAddAccessoryDialog(w fyne.Window, conn *pgx.Conn, onSuccess func()) {
-	formData := buildAccessoryForm(w, conn, nil)
+func showAddAccessoryDialog(w fyne.Window, store CollectionStore, owned bool, onSuccess func()) {
+	formData := buildAccessoryForm(w, store, nil)
+	formData.ownedCheck.SetChecked(owned) // Unchecked when adding to the wishlist
 
 	var d dialog.Dialog
 
 	_, _, buttonBar := createDialogButtons(
 		func() { d.Hide() },
 		func() {
-			accessoryID, err := saveAccessory(conn, formData, 0)
+			_, err := saveAccessory(store, formData, 0)
 			if err != nil {
 				dialog.ShowError(err, w)
 				return
 			}
 
-			// Save console relationships
-			for _, consoleID := range formData.selectedConsoleIDs {
-				conn.Exec(context.Background(),
-					"INSERT INTO accessory_consoles (accessory_id, console_id) VALUES ($1, $2)",
-					accessoryID, consoleID)
-			}
-
 			dialog.ShowInformation("Ajouté", "Accessoire ajouté à la base de données.", w)
 			if onSuccess != nil {
 				onSuccess()
@@ -1717,34 +1755,26 @@ func showAddAccessoryDialog(w fyne.Window, conn *pgx.Conn, onSuccess func()) {
 	d.Show()
 }
 
-func showEditAccessoryDialog(w fyne.Window, conn *pgx.Conn, accessoryID int, onSuccess func()) {
-	existingAccessory, err := getAccessoryByID(conn, accessoryID)
+func showEditAccessoryDialog(w fyne.Window, store CollectionStore, accessoryID int, onSuccess func()) {
+	existingAccessory, err := store.GetAccessoryByID(accessoryID)
 	if err != nil {
 		dialog.ShowError(fmt.Errorf("échec du chargement de l'accessoire: %w", err), w)
 		return
 	}
 
-	formData := buildAccessoryForm(w, conn, existingAccessory)
+	formData := buildAccessoryForm(w, store, existingAccessory)
 
 	var d dialog.Dialog
 
 	_, _, buttonBar := createDialogButtons(
 		func() { d.Hide() },
 		func() {
-			_, err := saveAccessory(conn, formData, accessoryID)
+			_, err := saveAccessory(store, formData, accessoryID)
 			if err != nil {
 				dialog.ShowError(err, w)
 				return
 			}
 
-			// Delete old and save new console relationships
-			conn.Exec(context.Background(), "DELETE FROM accessory_consoles WHERE accessory_id = $1", accessoryID)
-			for _, consoleID := range formData.selectedConsoleIDs {
-				conn.Exec(context.Background(),
-					"INSERT INTO accessory_consoles (accessory_id, console_id) VALUES ($1, $2)",
-					accessoryID, consoleID)
-			}
-
 			dialog.ShowInformation("Enregistré", "Accessoire mis à jour dans la base de données.", w)
 			if onSuccess != nil {
 				onSuccess()
@@ -1772,107 +1802,60 @@ func showEditAccessoryDialog(w fyne.Window, conn *pgx.Conn, accessoryID int, onS
 	d.Show()
 }
 
-// saveAccessory inserts or updates an accessory (similar to saveGame pattern)
-func saveAccessory(conn *pgx.Conn, formData *accessoryFormData, accessoryID int) (int, error) {
-	// Validate required fields
-	if formData.nameEntry.Text == "" {
-		return 0, fmt.Errorf("nom requis")
-	}
-	if formData.typeSelect.Selected == "" {
-		return 0, fmt.Errorf("type requis")
+// saveAccessory converts the form into an Accessory and hands it to the store (similar to saveGame pattern)
+func saveAccessory(store CollectionStore, formData *accessoryFormData, accessoryID int) (int, error) {
+	accessory := &Accessory{
+		AccessoryID: accessoryID,
+		Name:        formData.nameEntry.Text,
+		Owned:       formData.ownedCheck.Checked,
+		ConsoleIDs:  formData.selectedConsoleIDs,
 	}
 
 	// Convert dropdown selections to IDs
-	var typeID *int
 	if formData.typeSelect.Selected != "" {
 		id := formData.typeMap[formData.typeSelect.Selected]
-		typeID = &id
+		accessory.TypeID = &id
 	}
 
-	var manufacturerID *int
 	if formData.manufacturerSelect.Selected != "" {
 		id := formData.manufacturerMap[formData.manufacturerSelect.Selected]
-		manufacturerID = &id
+		accessory.ManufacturerID = &id
 	}
 
-	var color *string
-	if formData.colorEntry.Text != "" {
-		color = &formData.colorEntry.Text
-	}
+	accessory.Color = optionalText(formData.colorEntry.Text)
 
 	// Parse quantity
-	var quantity int = 1
+	accessory.Quantity = 1
 	if formData.quantityEntry.Text != "" {
-		fmt.Sscanf(formData.quantityEntry.Text, "%d", &quantity)
+		fmt.Sscanf(formData.quantityEntry.Text, "%d", &accessory.Quantity)
 	}
 
 	// Parse condition
-	var condition *int
 	if formData.conditionSlider.Value > 0 {
 		c := int(formData.conditionSlider.Value)
-		condition = &c
+		accessory.Condition = &c
 	}
 
 	// Parse purchase info
-	var purchaseDate *string
-	if formData.purchaseDateEntry.Text != "" {
-		purchaseDate = &formData.purchaseDateEntry.Text
+	var err error
+	if accessory.PurchaseDate, err = parseDateEntry(formData.purchaseDateEntry.Text); err != nil {
+		return 0, err
 	}
 
-	var purchasePrice *float64
 	if formData.purchasePriceEntry.Text != "" {
 		var price float64
 		fmt.Sscanf(formData.purchasePriceEntry.Text, "%f", &price)
-		purchasePrice = &price
-	}
-
-	var notes *string
-	if formData.notesEntry.Text != "" {
-		notes = &formData.notesEntry.Text
+		accessory.PurchasePrice = &price
 	}
 
-	// Execute INSERT or UPDATE
-	if accessoryID == 0 {
-		// INSERT
-		query := `
-			INSERT INTO accessories (
-				name, color, type_id, manufacturer_id, quantity,
-				condition, owned, purchase_date, purchase_price, notes
-			)
-			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
-			RETURNING accessory_id
-		`
+	accessory.Notes = optionalText(formData.notesEntry.Text)
 
-		err := conn.QueryRow(context.Background(), query,
-			formData.nameEntry.Text, color, typeID, manufacturerID, quantity,
-			condition, formData.ownedCheck.Checked, purchaseDate, purchasePrice, notes,
-		).Scan(&accessoryID)
+	accessory.TargetPrice, accessory.WishPriority, accessory.WishEdition = formData.wishlist.values()
 
-		if err != nil {
-			return 0, fmt.Errorf("échec de l'ajout de l'accessoire: %w", err)
-		}
-		return accessoryID, nil
-	} else {
-		// UPDATE
-		query := `
-			UPDATE accessories SET
-				name = $1, color = $2, type_id = $3, manufacturer_id = $4,
-				quantity = $5, condition = $6, owned = $7,
-				purchase_date = $8, purchase_price = $9, notes = $10
-			WHERE accessory_id = $11
-		`
-
-		_, err := conn.Exec(context.Background(), query,
-			formData.nameEntry.Text, color, typeID, manufacturerID, quantity,
-			condition, formData.ownedCheck.Checked, purchaseDate, purchasePrice, notes,
-			accessoryID,
-		)
-
-		if err != nil {
-			return 0, fmt.Errorf("échec de la mise à jour de l'accessoire: %w", err)
-		}
-		return accessoryID, nil
+	if err := validateAccessory(accessory); err != nil {
+		return 0, err
 	}
+	return store.SaveAccessory(accessory)
 }
 
 // ========== CONSOLES CRUD ==========
@@ -1900,7 +1883,10 @@ type consoleFormData struct {
 	ownedCheck         *widget.Check
 	conditionSlider    *widget.Slider
 	conditionLabel     *widget.Label
+	purchaseDateEntry  *widget.Entry
+	purchasePriceEntry *widget.Entry
 	notesEntry         *widget.Entry
+	wishlist           *wishlistFields
 	typeSelect         *widget.Select
 	manufacturerSelect *widget.Select
 
@@ -1912,15 +1898,15 @@ type consoleFormData struct {
 }
 
 // buildConsoleForm creates the console form (similar pattern to other build functions)
-func buildConsoleForm(w fyne.Window, conn *pgx.Conn, existingConsole *Console) *consoleFormData {
+func buildConsoleForm(w fyne.Window, store CollectionStore, existingConsole *Console) *consoleFormData {
 	formData := &consoleFormData{
 		typeMap:         make(map[string]int),
 		manufacturerMap: make(map[string]int),
 	}
 
 	// Fetch lookup data
-	types, _ := getConsoleTypes(conn)
-	manufacturers, _ := getManufacturers(conn)
+	types, _ := store.GetConsoleTypes()
+	manufacturers, _ := store.GetManufacturers()
 
 	// Name field (required)
 	formData.nameEntry = widget.NewEntry()
@@ -2066,8 +2052,10 @@ func buildConsoleForm(w fyne.Window, conn *pgx.Conn, existingConsole *Console) *
 	formData.ownedCheck = widget.NewCheck("Possédé", nil)
 	if existingConsole != nil {
 		formData.ownedCheck.Checked = existingConsole.Owned
+		formData.wishlist = newWishlistFields(formData.ownedCheck, existingConsole.TargetPrice, existingConsole.WishPriority, existingConsole.WishEdition)
 	} else {
 		formData.ownedCheck.Checked = true
+		formData.wishlist = newWishlistFields(formData.ownedCheck, nil, nil, nil)
 	}
 
 	// Condition slider
@@ -2084,6 +2072,19 @@ func buildConsoleForm(w fyne.Window, conn *pgx.Conn, existingConsole *Console) *
 		formData.conditionLabel.SetText(fmt.Sprintf("État: %d", int(value)))
 	}
 
+	// Purchase info fields
+	formData.purchaseDateEntry = widget.NewEntry()
+	formData.purchaseDateEntry.SetPlaceHolder("AAAA-MM-JJ")
+	if existingConsole != nil && existingConsole.PurchaseDate != nil {
+		formData.purchaseDateEntry.SetText(existingConsole.PurchaseDate.Format("2006-01-02"))
+	}
+
+	formData.purchasePriceEntry = widget.NewEntry()
+	formData.purchasePriceEntry.SetPlaceHolder("Prix d'achat")
+	if existingConsole != nil && existingConsole.PurchasePrice != nil {
+		formData.purchasePriceEntry.SetText(fmt.Sprintf("%.2f", *existingConsole.PurchasePrice))
+	}
+
 	// Notes field
 	formData.notesEntry = widget.NewMultiLineEntry()
 	formData.notesEntry.SetPlaceHolder("Notes")
@@ -2153,6 +2154,14 @@ func buildConsoleForm(w fyne.Window, conn *pgx.Conn, existingConsole *Console) *
 		formData.ownedCheck,
 		formData.conditionLabel,
 		formData.conditionSlider,
+		formData.wishlist.section,
+
+		widget.NewSeparator(),
+		widget.NewLabel("Informations d'achat"),
+		widget.NewLabel("Date d'achat:"),
+		formData.purchaseDateEntry,
+		widget.NewLabel("Prix d'achat:"),
+		formData.purchasePriceEntry,
 
 		widget.NewSeparator(),
 		widget.NewLabel("Notes"),
@@ -2162,15 +2171,16 @@ func buildConsoleForm(w fyne.Window, conn *pgx.Conn, existingConsole *Console) *
 	return formData
 }
 
-func showAddConsoleDialog(w fyne.Window, conn *pgx.Conn, onSuccess func()) {
-	formData := buildConsoleForm(w, conn, nil)
+func showAddConsoleDialog(w fyne.Window, store CollectionStore, owned bool, onSuccess func()) {
+	formData := buildConsoleForm(w, store, nil)
+	formData.ownedCheck.SetChecked(owned) // Unchecked when adding to the wishlist
 
 	var d dialog.Dialog
 
 	_, _, buttonBar := createDialogButtons(
 		func() { d.Hide() },
 		func() {
-			_, err := saveConsole(conn, formData, 0)
+			_, err := saveConsole(store, formData, 0)
 			if err != nil {
 				dialog.ShowError(err, w)
 				return
@@ -2203,21 +2213,21 @@ func showAddConsoleDialog(w fyne.Window, conn *pgx.Conn, onSuccess func()) {
 	d.Show()
 }
 
-func showEditConsoleDialog(w fyne.Window, conn *pgx.Conn, consoleID int, onSuccess func()) {
-	existingConsole, err := getConsoleByID(conn, consoleID)
+func showEditConsoleDialog(w fyne.Window, store CollectionStore, consoleID int, onSuccess func()) {
+	existingConsole, err := store.GetConsoleByID(consoleID)
 	if err != nil {
 		dialog.ShowError(fmt.Errorf("échec du chargement de la console: %w", err), w)
 		return
 	}
 
-	formData := buildConsoleForm(w, conn, existingConsole)
+	formData := buildConsoleForm(w, store, existingConsole)
 
 	var d dialog.Dialog
 
 	_, _, buttonBar := createDialogButtons(
 		func() { d.Hide() },
 		func() {
-			_, err := saveConsole(conn, formData, consoleID)
+			_, err := saveConsole(store, formData, consoleID)
 			if err != nil {
 				dialog.ShowError(err, w)
 				return
@@ -2250,173 +2260,79 @@ func showEditConsoleDialog(w fyne.Window, conn *pgx.Conn, consoleID int, onSucce
 	d.Show()
 }
 
-// saveConsole inserts or updates a console (similar to saveGame pattern)
-func saveConsole(conn *pgx.Conn, formData *consoleFormData, consoleID int) (int, error) {
-	// Validate required fields
-	if formData.nameEntry.Text == "" {
-		return 0, fmt.Errorf("nom requis")
-	}
-	if formData.typeSelect.Selected == "" {
-		return 0, fmt.Errorf("type requis")
-	}
-	if formData.manufacturerSelect.Selected == "" {
-		return 0, fmt.Errorf("plateforme requise")
+// saveConsole converts the form into a Console and hands it to the store (similar to saveGame pattern)
+func saveConsole(store CollectionStore, formData *consoleFormData, consoleID int) (int, error) {
+	console := &Console{
+		ConsoleID: consoleID,
+		Name:      formData.nameEntry.Text,
+		Owned:     formData.ownedCheck.Checked,
 	}
 
 	// Convert dropdown selections to IDs
-	var typeID *int
 	if formData.typeSelect.Selected != "" {
 		id := formData.typeMap[formData.typeSelect.Selected]
-		typeID = &id
+		console.TypeID = &id
 	}
 
-	var manufacturerID *int
 	if formData.manufacturerSelect.Selected != "" {
 		id := formData.manufacturerMap[formData.manufacturerSelect.Selected]
-		manufacturerID = &id
+		console.ManufacturerID = &id
 	}
 
 	// Parse numeric fields
-	var generation *int
-	if formData.generationEntry.Text != "" {
-		var gen int
-		fmt.Sscanf(formData.generationEntry.Text, "%d", &gen)
-		generation = &gen
-	}
+	console.Generation = parseIntEntry(formData.generationEntry.Text)
 
 	// Parse date fields
-	var jpReleaseDate, usReleaseDate, euReleaseDate, discontinued *string
-	if formData.jpReleaseDateEntry.Text != "" {
-		jpReleaseDate = &formData.jpReleaseDateEntry.Text
+	var err error
+	if console.JPReleaseDate, err = parseDateEntry(formData.jpReleaseDateEntry.Text); err != nil {
+		return 0, err
 	}
-	if formData.usReleaseDateEntry.Text != "" {
-		usReleaseDate = &formData.usReleaseDateEntry.Text
+	if console.USReleaseDate, err = parseDateEntry(formData.usReleaseDateEntry.Text); err != nil {
+		return 0, err
 	}
-	if formData.euReleaseDateEntry.Text != "" {
-		euReleaseDate = &formData.euReleaseDateEntry.Text
+	if console.EUReleaseDate, err = parseDateEntry(formData.euReleaseDateEntry.Text); err != nil {
+		return 0, err
 	}
-	if formData.discontinuedEntry.Text != "" {
-		discontinued = &formData.discontinuedEntry.Text
+	if console.Discontinued, err = parseDateEntry(formData.discontinuedEntry.Text); err != nil {
+		return 0, err
 	}
 
 	// Parse price fields
-	var priceJPY, priceUSD *int
-	if formData.priceJPYEntry.Text != "" {
-		var price int
-		fmt.Sscanf(formData.priceJPYEntry.Text, "%d", &price)
-		priceJPY = &price
-	}
-	if formData.priceUSDEntry.Text != "" {
-		var price int
-		fmt.Sscanf(formData.priceUSDEntry.Text, "%d", &price)
-		priceUSD = &price
-	}
+	console.PriceJPY = parseIntEntry(formData.priceJPYEntry.Text)
+	console.PriceUSD = parseIntEntry(formData.priceUSDEntry.Text)
 
 	// Parse hardware spec fields
-	var controllers *int
-	if formData.controllersEntry.Text != "" {
-		var c int
-		fmt.Sscanf(formData.controllersEntry.Text, "%d", &c)
-		controllers = &c
-	}
-
-	var cpu, gpu, memory, audio *string
-	if formData.cpuEntry.Text != "" {
-		cpu = &formData.cpuEntry.Text
-	}
-	if formData.gpuEntry.Text != "" {
-		gpu = &formData.gpuEntry.Text
-	}
-	if formData.memoryEntry.Text != "" {
-		memory = &formData.memoryEntry.Text
-	}
-	if formData.audioEntry.Text != "" {
-		audio = &formData.audioEntry.Text
-	}
+	console.Controllers = parseIntEntry(formData.controllersEntry.Text)
+	console.CPU = optionalText(formData.cpuEntry.Text)
+	console.GPU = optionalText(formData.gpuEntry.Text)
+	console.Memory = optionalText(formData.memoryEntry.Text)
+	console.Audio = optionalText(formData.audioEntry.Text)
 
 	// Parse sales & history fields
-	var unitsSold *int
-	if formData.unitsSoldEntry.Text != "" {
-		var units int
-		fmt.Sscanf(formData.unitsSoldEntry.Text, "%d", &units)
-		unitsSold = &units
-	}
-
-	var topGame, predecessor, successor *string
-	if formData.topGameEntry.Text != "" {
-		topGame = &formData.topGameEntry.Text
-	}
-	if formData.predecessorEntry.Text != "" {
-		predecessor = &formData.predecessorEntry.Text
-	}
-	if formData.successorEntry.Text != "" {
-		successor = &formData.successorEntry.Text
-	}
+	console.UnitsSold = parseIntEntry(formData.unitsSoldEntry.Text)
+	console.TopGame = optionalText(formData.topGameEntry.Text)
+	console.Predecessor = optionalText(formData.predecessorEntry.Text)
+	console.Successor = optionalText(formData.successorEntry.Text)
 
 	// Parse condition
-	var condition *int
 	if formData.conditionSlider.Value > 0 {
 		c := int(formData.conditionSlider.Value)
-		condition = &c
+		console.Condition = &c
+	}
+
+	// Parse purchase info
+	if console.PurchaseDate, err = parseDateEntry(formData.purchaseDateEntry.Text); err != nil {
+		return 0, err
 	}
+	console.PurchasePrice = parsePriceEntry(formData.purchasePriceEntry.Text)
 
 	// Parse notes
-	var notes *string
-	if formData.notesEntry.Text != "" {
-		notes = &formData.notesEntry.Text
-	}
-
-	// Execute INSERT or UPDATE
-	if consoleID == 0 {
-		// INSERT
-		query := `
-			INSERT INTO consoles (
-				name, type_id, manufacturer_id, generation,
-				jp_release_date, us_release_date, eu_release_date, discontinued,
-				price_jpy, price_usd, controllers, cpu, gpu, memory, audio,
-				units_sold, top_game, predecessor, successor,
-				owned, condition, notes
-			)
-			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22)
-			RETURNING console_id
-		`
-
-		err := conn.QueryRow(context.Background(), query,
-			formData.nameEntry.Text, typeID, manufacturerID, generation,
-			jpReleaseDate, usReleaseDate, euReleaseDate, discontinued,
-			priceJPY, priceUSD, controllers, cpu, gpu, memory, audio,
-			unitsSold, topGame, predecessor, successor,
-			formData.ownedCheck.Checked, condition, notes,
-		).Scan(&consoleID)
-
-		if err != nil {
-			return 0, fmt.Errorf("échec de l'ajout de console: %w", err)
-		}
-		return consoleID, nil
-	} else {
-		// UPDATE
-		query := `
-			UPDATE consoles SET
-				name = $1, type_id = $2, manufacturer_id = $3, generation = $4,
-				jp_release_date = $5, us_release_date = $6, eu_release_date = $7, discontinued = $8,
-				price_jpy = $9, price_usd = $10, controllers = $11, cpu = $12, gpu = $13, memory = $14, audio = $15,
-				units_sold = $16, top_game = $17, predecessor = $18, successor = $19,
-				owned = $20, condition = $21, notes = $22
-			WHERE console_id = $23
-		`
-
-		_, err := conn.Exec(context.Background(), query,
-			formData.nameEntry.Text, typeID, manufacturerID, generation,
-			jpReleaseDate, usReleaseDate, euReleaseDate, discontinued,
-			priceJPY, priceUSD, controllers, cpu, gpu, memory, audio,
-			unitsSold, topGame, predecessor, successor,
-			formData.ownedCheck.Checked, condition, notes,
-			consoleID,
-		)
-
-		if err != nil {
-			return 0, fmt.Errorf("échec de la mise à jour de la console: %w", err)
-		}
-		return consoleID, nil
+	console.Notes = optionalText(formData.notesEntry.Text)
+
+	console.TargetPrice, console.WishPriority, console.WishEdition = formData.wishlist.values()
+
+	if err := validateConsole(console); err != nil {
+		return 0, err
 	}
+	return store.SaveConsole(console)
 }
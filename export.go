@@ -0,0 +1,468 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// ========== COLLECTION EXPORT ==========
+// Games, consoles and accessories are written with their full details to CSV, JSON or XLSX.
+// Columns use the labels of the import fields (see import.go) and values are formatted the
+// way the importer reads them, so an exported file can be imported back as is.
+
+// Export formats
+const (
+	exportCSV  = "CSV"
+	exportJSON = "JSON"
+	exportXLSX = "XLSX"
+)
+
+var exportFormats = []string{exportCSV, exportJSON, exportXLSX}
+
+// exportColumn is one exported field: Key names it in JSON, Label heads CSV/XLSX columns
+// Value returns nil, string, int, float64, bool, time.Time or []string
+type exportColumn[T any] struct {
+	Key   string
+	Label string
+	Value func(item *T) any
+}
+
+// exportTable is the data of one entity type ready to be written
+type exportTable struct {
+	Name   string // JSON key, sheet name and CSV file name ("jeux", "consoles", "accessoires")
+	Keys   []string
+	Labels []string
+	Rows   [][]any
+}
+
+// optional returns nil for a nil pointer so empty fields stay empty
+func optional[V any](value *V) any {
+	if value == nil {
+		return nil
+	}
+	return *value
+}
+
+// optionalString returns nil for an empty name
+func optionalString(value string) any {
+	if value == "" {
+		return nil
+	}
+	return value
+}
+
+var gameExportColumns = []exportColumn[Game]{
+	{"id", "ID", func(g *Game) any { return g.GameID }},
+	{"title", "Titre", func(g *Game) any { return g.Title }},
+	{"console", "Plateforme", func(g *Game) any { return optionalString(g.ConsoleName) }},
+	{"genre", "Genre", func(g *Game) any { return optionalString(g.GenreName) }},
+	{"developers", "Développeur(s)", func(g *Game) any { return g.Developers }},
+	{"publishers", "Distributeur(s)", func(g *Game) any { return g.Publishers }},
+	{"composers", "Compositeur(s)", func(g *Game) any { return g.Composers }},
+	{"producers", "Producteur(s)", func(g *Game) any { return g.Producers }},
+	{"jp_release_date", "Sortie Japon", func(g *Game) any { return optional(g.JPReleaseDate) }},
+	{"us_release_date", "Sortie USA", func(g *Game) any { return optional(g.USReleaseDate) }},
+	{"eu_release_date", "Sortie Europe", func(g *Game) any { return optional(g.EUReleaseDate) }},
+	{"jp_rating", "Classification Japon", func(g *Game) any { return optionalString(g.JPRating) }},
+	{"us_rating", "Classification USA", func(g *Game) any { return optionalString(g.USRating) }},
+	{"eu_rating", "Classification Europe", func(g *Game) any { return optionalString(g.EURating) }},
+	{"units_sold", "Unités vendues", func(g *Game) any { return optional(g.UnitsSold) }},
+	{"owned", "Possédé", func(g *Game) any { return g.Owned }},
+	{"box_owned", "Boîte", func(g *Game) any { return optional(g.BoxOwned) }},
+	{"collector", "Édition collector", func(g *Game) any { return optional(g.Collector) }},
+	{"condition", "État", func(g *Game) any { return optional(g.Condition) }},
+	{"purchase_date", "Date d'achat", func(g *Game) any { return optional(g.PurchaseDate) }},
+	{"purchase_price", "Prix d'achat", func(g *Game) any { return optional(g.PurchasePrice) }},
+	{"copies", "Exemplaires", func(g *Game) any { return copyDescriptions(g.Copies) }},
+	{"completeness", "Complétude", func(g *Game) any { return completenessLabels[g.Completeness] }},
+	{"target_price", "Prix cible", func(g *Game) any { return optional(g.TargetPrice) }},
+	{"wish_priority", "Priorité", func(g *Game) any { return optionalString(wishPriorityText(g.WishPriority)) }},
+	{"wish_edition", "Région / édition", func(g *Game) any { return optional(g.WishEdition) }},
+	{"play_status", "Progression", func(g *Game) any { return optionalString(playStatusText(g.PlayStatus)) }},
+	{"play_started_on", "Commencé le", func(g *Game) any { return optional(g.PlayStartedOn) }},
+	{"play_finished_on", "Terminé le", func(g *Game) any { return optional(g.PlayFinishedOn) }},
+	{"hours_played", "Temps de jeu (heures)", func(g *Game) any { return optional(g.HoursPlayed) }},
+	{"personal_rating", "Note personnelle", func(g *Game) any { return optional(g.PersonalRating) }},
+	{"review", "Avis", func(g *Game) any { return optional(g.Review) }},
+	{"notes", "Notes", func(g *Game) any { return optional(g.Notes) }},
+}
+
+var consoleExportColumns = []exportColumn[Console]{
+	{"id", "ID", func(c *Console) any { return c.ConsoleID }},
+	{"name", "Nom", func(c *Console) any { return c.Name }},
+	{"type", "Type", func(c *Console) any { return optionalString(c.TypeName) }},
+	{"manufacturer", "Fabricant", func(c *Console) any { return optionalString(c.ManufacturerName) }},
+	{"generation", "Génération", func(c *Console) any { return optional(c.Generation) }},
+	{"jp_release_date", "Sortie Japon", func(c *Console) any { return optional(c.JPReleaseDate) }},
+	{"us_release_date", "Sortie USA", func(c *Console) any { return optional(c.USReleaseDate) }},
+	{"eu_release_date", "Sortie Europe", func(c *Console) any { return optional(c.EUReleaseDate) }},
+	{"discontinued", "Fin de production", func(c *Console) any { return optional(c.Discontinued) }},
+	{"price_jpy", "Prix de lancement (JPY)", func(c *Console) any { return optional(c.PriceJPY) }},
+	{"price_usd", "Prix de lancement (USD)", func(c *Console) any { return optional(c.PriceUSD) }},
+	{"controllers", "Ports contrôleurs", func(c *Console) any { return optional(c.Controllers) }},
+	{"cpu", "CPU", func(c *Console) any { return optional(c.CPU) }},
+	{"gpu", "GPU", func(c *Console) any { return optional(c.GPU) }},
+	{"memory", "Mémoire", func(c *Console) any { return optional(c.Memory) }},
+	{"audio", "Audio", func(c *Console) any { return optional(c.Audio) }},
+	{"units_sold", "Unités vendues", func(c *Console) any { return optional(c.UnitsSold) }},
+	{"top_game", "Top ventes", func(c *Console) any { return optional(c.TopGame) }},
+	{"predecessor", "Prédécesseur", func(c *Console) any { return optional(c.Predecessor) }},
+	{"successor", "Successeur", func(c *Console) any { return optional(c.Successor) }},
+	{"owned", "Possédé", func(c *Console) any { return c.Owned }},
+	{"condition", "État", func(c *Console) any { return optional(c.Condition) }},
+	{"purchase_date", "Date d'achat", func(c *Console) any { return optional(c.PurchaseDate) }},
+	{"purchase_price", "Prix d'achat", func(c *Console) any { return optional(c.PurchasePrice) }},
+	{"target_price", "Prix cible", func(c *Console) any { return optional(c.TargetPrice) }},
+	{"wish_priority", "Priorité", func(c *Console) any { return optionalString(wishPriorityText(c.WishPriority)) }},
+	{"wish_edition", "Région / édition", func(c *Console) any { return optional(c.WishEdition) }},
+	{"notes", "Notes", func(c *Console) any { return optional(c.Notes) }},
+}
+
+var accessoryExportColumns = []exportColumn[Accessory]{
+	{"id", "ID", func(a *Accessory) any { return a.AccessoryID }},
+	{"name", "Nom", func(a *Accessory) any { return a.Name }},
+	{"type", "Type", func(a *Accessory) any { return optionalString(a.TypeName) }},
+	{"manufacturer", "Fabricant", func(a *Accessory) any { return optionalString(a.ManufacturerName) }},
+	{"consoles", "Plateformes compatibles", func(a *Accessory) any { return a.Consoles }},
+	{"color", "Couleur", func(a *Accessory) any { return optional(a.Color) }},
+	{"condition", "État", func(a *Accessory) any { return optional(a.Condition) }},
+	{"owned", "Possédé", func(a *Accessory) any { return a.Owned }},
+	{"purchase_date", "Date d'achat", func(a *Accessory) any { return optional(a.PurchaseDate) }},
+	{"purchase_price", "Prix d'achat", func(a *Accessory) any { return optional(a.PurchasePrice) }},
+	{"quantity", "Quantité", func(a *Accessory) any { return a.Quantity }},
+	{"target_price", "Prix cible", func(a *Accessory) any { return optional(a.TargetPrice) }},
+	{"wish_priority", "Priorité", func(a *Accessory) any { return optionalString(wishPriorityText(a.WishPriority)) }},
+	{"wish_edition", "Région / édition", func(a *Accessory) any { return optional(a.WishEdition) }},
+	{"notes", "Notes", func(a *Accessory) any { return optional(a.Notes) }},
+}
+
+// newExportTable builds a table from items and their column definitions
+func newExportTable[T any](name string, columns []exportColumn[T], items []*T) *exportTable {
+	table := &exportTable{Name: name}
+	for _, col := range columns {
+		table.Keys = append(table.Keys, col.Key)
+		table.Labels = append(table.Labels, col.Label)
+	}
+	for _, item := range items {
+		row := make([]any, len(columns))
+		for i, col := range columns {
+			row[i] = col.Value(item)
+		}
+		table.Rows = append(table.Rows, row)
+	}
+	return table
+}
+
+// ========== LOADING ==========
+
+// loadExportTable fetches the full details of the given rows of an entity type
+// ids == nil exports every row; entityType is "game", "console" or "accessory".
+func loadExportTable(store CollectionStore, entityType string, ids []int) (*exportTable, error) {
+	switch entityType {
+	case "game":
+		return loadGamesExport(store, ids)
+	case "console":
+		return loadConsolesExport(store, ids)
+	default:
+		return loadAccessoriesExport(store, ids)
+	}
+}
+
+// loadCollectionExport fetches every game, console and accessory
+func loadCollectionExport(store CollectionStore) ([]*exportTable, error) {
+	var tables []*exportTable
+	for _, entityType := range []string{"game", "console", "accessory"} {
+		table, err := loadExportTable(store, entityType, nil)
+		if err != nil {
+			return nil, err
+		}
+		tables = append(tables, table)
+	}
+	return tables, nil
+}
+
+func loadGamesExport(store CollectionStore, ids []int) (*exportTable, error) {
+	if ids == nil {
+		games, err := store.GetGames()
+		if err != nil {
+			return nil, fmt.Errorf("échec de chargement des jeux: %w", err)
+		}
+		for _, g := range games {
+			ids = append(ids, g.GameID)
+		}
+	}
+
+	// The detail query returns rating IDs only
+	ratings, err := store.GetRatingSystems()
+	if err != nil {
+		return nil, fmt.Errorf("échec de chargement des classifications: %w", err)
+	}
+	ratingCodes := make(map[int]string)
+	for _, r := range ratings {
+		ratingCodes[r.RatingID] = r.Code
+	}
+	ratingCode := func(id *int) string {
+		if id == nil {
+			return ""
+		}
+		return ratingCodes[*id]
+	}
+
+	var games []*Game
+	for _, id := range ids {
+		game, err := store.GetGameByID(id)
+		if err != nil {
+			return nil, fmt.Errorf("échec de chargement du jeu %d: %w", id, err)
+		}
+		game.JPRating = ratingCode(game.JPRatingID)
+		game.USRating = ratingCode(game.USRatingID)
+		game.EURating = ratingCode(game.EURatingID)
+		games = append(games, game)
+	}
+	return newExportTable("jeux", gameExportColumns, games), nil
+}
+
+func loadConsolesExport(store CollectionStore, ids []int) (*exportTable, error) {
+	if ids == nil {
+		consoles, err := store.GetConsoles()
+		if err != nil {
+			return nil, fmt.Errorf("échec de chargement des consoles: %w", err)
+		}
+		for _, c := range consoles {
+			ids = append(ids, c.ConsoleID)
+		}
+	}
+
+	var consoles []*Console
+	for _, id := range ids {
+		console, err := store.GetConsoleByID(id)
+		if err != nil {
+			return nil, fmt.Errorf("échec de chargement de la console %d: %w", id, err)
+		}
+		consoles = append(consoles, console)
+	}
+	return newExportTable("consoles", consoleExportColumns, consoles), nil
+}
+
+func loadAccessoriesExport(store CollectionStore, ids []int) (*exportTable, error) {
+	if ids == nil {
+		accessories, err := store.GetAccessories()
+		if err != nil {
+			return nil, fmt.Errorf("échec de chargement des accessoires: %w", err)
+		}
+		for _, a := range accessories {
+			ids = append(ids, a.AccessoryID)
+		}
+	}
+
+	var accessories []*Accessory
+	for _, id := range ids {
+		accessory, err := store.GetAccessoryByID(id)
+		if err != nil {
+			return nil, fmt.Errorf("échec de chargement de l'accessoire %d: %w", id, err)
+		}
+		accessories = append(accessories, accessory)
+	}
+	return newExportTable("accessoires", accessoryExportColumns, accessories), nil
+}
+
+// ========== WRITERS ==========
+
+// formatExportValue renders a value as text for CSV, in a form the importer reads back
+func formatExportValue(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case int:
+		return strconv.Itoa(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', 2, 64)
+	case bool:
+		if v {
+			return "oui"
+		}
+		return "non"
+	case time.Time:
+		return v.Format("2006-01-02")
+	case []string:
+		return strings.Join(v, ", ")
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// writeExportCSV writes one table as CSV
+// Excel expects ';' with French regional settings and a byte order mark to read UTF-8
+func writeExportCSV(w io.Writer, table *exportTable) error {
+	if _, err := io.WriteString(w, "\ufeff"); err != nil {
+		return err
+	}
+
+	writer := csv.NewWriter(w)
+	writer.Comma = ';'
+	writer.UseCRLF = true
+
+	if err := writer.Write(table.Labels); err != nil {
+		return err
+	}
+	record := make([]string, len(table.Labels))
+	for _, row := range table.Rows {
+		for i, value := range row {
+			record[i] = formatExportValue(value)
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// writeExportJSON writes the tables as one object: {"jeux": [...], "consoles": [...]}
+// Fields keep the column order, dates are AAAA-MM-JJ strings and empty fields are null.
+func writeExportJSON(w io.Writer, tables []*exportTable) error {
+	var buf bytes.Buffer
+	buf.WriteString("{\n")
+
+	for t, table := range tables {
+		name, _ := json.Marshal(table.Name)
+		fmt.Fprintf(&buf, "  %s: [", name)
+
+		for r, row := range table.Rows {
+			if r > 0 {
+				buf.WriteString(",")
+			}
+			buf.WriteString("\n    {")
+			for i, value := range row {
+				if i > 0 {
+					buf.WriteString(", ")
+				}
+				if date, ok := value.(time.Time); ok {
+					value = date.Format("2006-01-02")
+				}
+				if list, ok := value.([]string); ok && list == nil {
+					value = []string{}
+				}
+
+				key, _ := json.Marshal(table.Keys[i])
+				encoded, err := json.Marshal(value)
+				if err != nil {
+					return fmt.Errorf("%s ligne %d: %w", table.Name, r+1, err)
+				}
+				fmt.Fprintf(&buf, "%s: %s", key, encoded)
+			}
+			buf.WriteString("}")
+		}
+
+		if len(table.Rows) > 0 {
+			buf.WriteString("\n  ")
+		}
+		buf.WriteString("]")
+		if t < len(tables)-1 {
+			buf.WriteString(",")
+		}
+		buf.WriteString("\n")
+	}
+
+	buf.WriteString("}\n")
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// writeExportXLSX writes each table on its own sheet
+// Dates are real date cells and numbers stay numbers so the sheet can be sorted and summed.
+func writeExportXLSX(w io.Writer, tables []*exportTable) error {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	headerStyle, err := f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}})
+	if err != nil {
+		return err
+	}
+	dateFormat := "yyyy-mm-dd"
+	dateStyle, err := f.NewStyle(&excelize.Style{CustomNumFmt: &dateFormat})
+	if err != nil {
+		return err
+	}
+	priceFormat := "0.00"
+	priceStyle, err := f.NewStyle(&excelize.Style{CustomNumFmt: &priceFormat})
+	if err != nil {
+		return err
+	}
+
+	defaultSheet := f.GetSheetName(0)
+	for t, table := range tables {
+		sheet := table.Name
+		if t == 0 {
+			if err := f.SetSheetName(defaultSheet, sheet); err != nil {
+				return err
+			}
+		} else if _, err := f.NewSheet(sheet); err != nil {
+			return err
+		}
+
+		header := make([]any, len(table.Labels))
+		for i, label := range table.Labels {
+			header[i] = label
+		}
+		if err := f.SetSheetRow(sheet, "A1", &header); err != nil {
+			return err
+		}
+		lastCol, _ := excelize.ColumnNumberToName(len(table.Labels))
+		if err := f.SetCellStyle(sheet, "A1", lastCol+"1", headerStyle); err != nil {
+			return err
+		}
+
+		for r, row := range table.Rows {
+			cells := make([]any, len(row))
+			for i, value := range row {
+				switch v := value.(type) {
+				case bool, []string:
+					cells[i] = formatExportValue(v)
+				default:
+					cells[i] = v
+				}
+			}
+
+			first, _ := excelize.CoordinatesToCellName(1, r+2)
+			if err := f.SetSheetRow(sheet, first, &cells); err != nil {
+				return err
+			}
+
+			for i, value := range row {
+				style := 0
+				switch value.(type) {
+				case time.Time:
+					style = dateStyle
+				case float64:
+					style = priceStyle
+				}
+				if style != 0 {
+					cell, _ := excelize.CoordinatesToCellName(i+1, r+2)
+					if err := f.SetCellStyle(sheet, cell, cell, style); err != nil {
+						return err
+					}
+				}
+			}
+		}
+
+		// Keep the header visible while scrolling
+		err := f.SetPanes(sheet, &excelize.Panes{Freeze: true, YSplit: 1, TopLeftCell: "A2", ActivePane: "bottomLeft"})
+		if err != nil {
+			return err
+		}
+	}
+
+	return f.Write(w)
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/widget"
+)
+
+// ========== EXPORT DIALOG ==========
+
+// exportFileNames gives the base file name of each entity type ("" = whole collection)
+var exportFileNames = map[string]string{
+	"":          "collection",
+	"game":      "jeux",
+	"console":   "consoles",
+	"accessory": "accessoires",
+}
+
+// showExportDialog asks for a format then a destination and writes the export
+// entityType is "game", "console" or "accessory" for a tab, or "" for the whole collection.
+// visibleIDs are the rows currently shown by the tab; when fewer than totalCount (a search
+// is active) the user can limit the export to them.
+func showExportDialog(w fyne.Window, store CollectionStore, entityType string, visibleIDs []int, totalCount int) {
+	formatRadio := widget.NewRadioGroup(exportFormats, nil)
+	formatRadio.Horizontal = true
+	formatRadio.SetSelected(exportCSV)
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Format", formatRadio),
+	}
+
+	filteredCheck := widget.NewCheck(fmt.Sprintf("Uniquement les %d ligne(s) affichée(s) par la recherche", len(visibleIDs)), nil)
+	if entityType != "" && len(visibleIDs) < totalCount {
+		filteredCheck.SetChecked(true)
+		items = append(items, widget.NewFormItem("", filteredCheck))
+	}
+
+	if entityType == "" {
+		hint := widget.NewLabel("En CSV, un fichier par type est créé dans le dossier choisi.")
+		hint.Wrapping = fyne.TextWrapWord
+		items = append(items, widget.NewFormItem("", hint))
+	}
+
+	title := "Exporter la collection"
+	if entityType != "" {
+		title = "Exporter les " + exportFileNames[entityType]
+	}
+
+	d := dialog.NewForm(title, "Exporter", "Annuler", items, func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+
+		var ids []int // nil = everything
+		if filteredCheck.Checked {
+			ids = visibleIDs
+			if ids == nil {
+				ids = []int{}
+			}
+		}
+
+		format := formatRadio.Selected
+		if entityType == "" && format == exportCSV {
+			chooseExportFolder(w, store, format)
+		} else {
+			chooseExportFile(w, store, entityType, ids, format)
+		}
+	}, w)
+	d.Resize(fyne.NewSize(500, 0))
+	d.Show()
+}
+
+// exportFileName builds a dated file name such as "jeux-2024-05-01.csv"
+func exportFileName(entityType, format string) string {
+	return fmt.Sprintf("%s-%s.%s", exportFileNames[entityType], time.Now().Format("2006-01-02"), strings.ToLower(format))
+}
+
+// chooseExportFile asks where to save a single export file then writes it
+func chooseExportFile(w fyne.Window, store CollectionStore, entityType string, ids []int, format string) {
+	// Load and encode first so nothing is created on failure
+	var tables []*exportTable
+	var err error
+	if entityType == "" {
+		tables, err = loadCollectionExport(store)
+	} else {
+		var table *exportTable
+		table, err = loadExportTable(store, entityType, ids)
+		tables = []*exportTable{table}
+	}
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("échec de l'export: %w", err), w)
+		return
+	}
+
+	var buf bytes.Buffer
+	switch format {
+	case exportJSON:
+		err = writeExportJSON(&buf, tables)
+	case exportXLSX:
+		err = writeExportXLSX(&buf, tables)
+	default:
+		err = writeExportCSV(&buf, tables[0])
+	}
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("échec de l'export: %w", err), w)
+		return
+	}
+
+	saveDialog := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		if writer == nil {
+			return // Cancelled
+		}
+		defer writer.Close()
+
+		if _, err := writer.Write(buf.Bytes()); err != nil {
+			dialog.ShowError(fmt.Errorf("échec d'écriture du fichier: %w", err), w)
+			return
+		}
+		dialog.ShowInformation("Export terminé", fmt.Sprintf("%s exporté(s) dans %s", exportRowCount(tables), writer.URI().Name()), w)
+	}, w)
+
+	saveDialog.SetFileName(exportFileName(entityType, format))
+	saveDialog.SetFilter(storage.NewExtensionFileFilter([]string{"." + strings.ToLower(format)}))
+	saveDialog.Resize(fyne.NewSize(800, 600))
+	saveDialog.Show()
+}
+
+// chooseExportFolder asks for a folder and writes one CSV file per entity type in it
+func chooseExportFolder(w fyne.Window, store CollectionStore, format string) {
+	tables, err := loadCollectionExport(store)
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("échec de l'export: %w", err), w)
+		return
+	}
+
+	folderDialog := dialog.NewFolderOpen(func(dir fyne.ListableURI, err error) {
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		if dir == nil {
+			return // Cancelled
+		}
+
+		for i, entityType := range []string{"game", "console", "accessory"} {
+			if err := writeExportFile(dir, exportFileName(entityType, format), tables[i]); err != nil {
+				dialog.ShowError(fmt.Errorf("échec de l'export: %w", err), w)
+				return
+			}
+		}
+		dialog.ShowInformation("Export terminé", fmt.Sprintf("%s exporté(s) dans %s", exportRowCount(tables), dir.Name()), w)
+	}, w)
+
+	folderDialog.Resize(fyne.NewSize(800, 600))
+	folderDialog.Show()
+}
+
+// writeExportFile writes one table as CSV into a folder
+func writeExportFile(dir fyne.URI, name string, table *exportTable) error {
+	uri, err := storage.Child(dir, name)
+	if err != nil {
+		return err
+	}
+	writer, err := storage.Writer(uri)
+	if err != nil {
+		return err
+	}
+	defer writer.Close()
+	return writeExportCSV(writer, table)
+}
+
+// exportRowCount describes the number of exported rows, e.g. "12 jeux, 3 consoles"
+func exportRowCount(tables []*exportTable) string {
+	var parts []string
+	for _, table := range tables {
+		parts = append(parts, fmt.Sprintf("%d %s", len(table.Rows), table.Name))
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,597 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// ========== FACET FILTERS ==========
+// Collapsible panel on the right of each tab narrowing down the rows found by the search
+// bar: checkboxes for the values of the lookup tables, minimum/maximum sliders for the
+// numbers and Tous/Oui/Non switches for the yes/no fields. The count next to each choice
+// is the number of rows it would give, taking the other facets into account.
+
+// facetPanelWidth is the width of the filter panel
+const facetPanelWidth = 270
+
+// prefFacetPanel is the preference key remembering whether the filter panels are shown
+const prefFacetPanel = "facetPanelVisible"
+
+// missingFacetValue stands for the rows without a value in a checkbox facet
+const missingFacetValue = "(non renseigné)"
+
+// Choices of the yes/no facets
+const (
+	facetAll = "Tous"
+	facetYes = "Oui"
+	facetNo  = "Non"
+)
+
+// facetKind tells which widgets a facet uses
+type facetKind int
+
+const (
+	facetValues facetKind = iota // One checkbox per value
+	facetRange                   // Minimum and maximum sliders
+	facetToggle                  // Tous / Oui / Non
+)
+
+// facetDef describes a facet of a tab
+type facetDef[T any] struct {
+	Label string
+	Kind  facetKind
+
+	// facetValues: values of an item ("" when not set) and the choices from the lookup tables
+	Values func(item *T) []string
+	Lookup func(store CollectionStore) ([]string, error)
+
+	// facetRange: value of an item (false when not set); bounds are taken from the data unless Max is set
+	Number   func(item *T) (float64, bool)
+	Min, Max float64
+	Format   func(value float64) string
+
+	// facetToggle
+	Bool func(item *T) bool
+}
+
+// facet is a facet with its current choices and widgets
+type facet[T any] struct {
+	def facetDef[T]
+
+	// facetValues
+	values   []string
+	selected map[string]bool
+	checks   map[string]*widget.Check
+
+	// facetRange
+	min, max, low, high   float64
+	lowSlider, highSlider *widget.Slider
+	rangeLabel            *widget.Label
+
+	// facetToggle
+	toggle      string
+	radio       *widget.RadioGroup
+	toggleLabel *widget.Label
+}
+
+// facetPanel holds the facets of a tab
+type facetPanel[T any] struct {
+	facets   []*facet[T]
+	onChange func()
+	content  fyne.CanvasObject
+	button   *widget.Button // Shows or hides the panel, with the number of active facets
+	updating bool           // Set while widgets are reset, so they don't report changes
+}
+
+// newFacetPanel creates the facets of a tab; items give the bounds of the sliders
+// onChange is called whenever a choice changes.
+func newFacetPanel[T any](store CollectionStore, defs []facetDef[T], items []T, onChange func()) *facetPanel[T] {
+	p := &facetPanel[T]{onChange: onChange, updating: true}
+	defer func() { p.updating = false }()
+
+	var sections []*widget.AccordionItem
+	for _, def := range defs {
+		f := &facet[T]{def: def}
+		var content fyne.CanvasObject
+		switch def.Kind {
+		case facetValues:
+			content = p.createValuesFacet(store, f, items)
+		case facetRange:
+			content = p.createRangeFacet(f, items)
+		case facetToggle:
+			content = p.createToggleFacet(f)
+		}
+		p.facets = append(p.facets, f)
+		sections = append(sections, widget.NewAccordionItem(def.Label, content))
+	}
+
+	accordion := widget.NewAccordion(sections...)
+	accordion.MultiOpen = true
+	for i, f := range p.facets {
+		// Long checkbox lists start closed
+		if f.def.Kind != facetValues || len(f.values) <= 8 {
+			accordion.Open(i)
+		}
+	}
+
+	resetBtn := widget.NewButton("Réinitialiser", p.Reset)
+	header := container.NewBorder(nil, nil, widget.NewLabelWithStyle("Filtres", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}), resetBtn)
+
+	// The rectangle gives the panel its width
+	width := canvas.NewRectangle(color.Transparent)
+	width.SetMinSize(fyne.NewSize(facetPanelWidth, 0))
+	p.content = container.NewStack(width, container.NewBorder(header, nil, nil, nil, container.NewVScroll(accordion)))
+
+	p.button = widget.NewButtonWithIcon("Filtres", theme.SearchIcon(), func() {
+		visible := !p.content.Visible()
+		p.setVisible(visible)
+		fyne.CurrentApp().Preferences().SetBool(prefFacetPanel, visible)
+	})
+	p.setVisible(fyne.CurrentApp().Preferences().Bool(prefFacetPanel))
+	return p
+}
+
+func (p *facetPanel[T]) setVisible(visible bool) {
+	if visible {
+		p.content.Show()
+		p.button.Importance = widget.HighImportance
+	} else {
+		p.content.Hide()
+		p.button.Importance = widget.MediumImportance
+	}
+	p.button.Refresh()
+}
+
+func (p *facetPanel[T]) changed() {
+	if !p.updating && p.onChange != nil {
+		p.onChange()
+	}
+}
+
+func (p *facetPanel[T]) createValuesFacet(store CollectionStore, f *facet[T], items []T) fyne.CanvasObject {
+	f.selected = make(map[string]bool)
+	f.checks = make(map[string]*widget.Check)
+
+	names, err := f.def.Lookup(store)
+	if err != nil {
+		return widget.NewLabel(fmt.Sprintf("Échec de chargement: %v", err))
+	}
+
+	// Lookup values, then values only found in the rows and the rows without value
+	seen := make(map[string]bool)
+	missing := false
+	for _, name := range names {
+		if !seen[strings.ToLower(name)] {
+			seen[strings.ToLower(name)] = true
+			f.values = append(f.values, name)
+		}
+	}
+	for i := range items {
+		values := f.def.Values(&items[i])
+		if len(values) == 0 {
+			missing = true
+		}
+		for _, value := range values {
+			if value == "" {
+				missing = true
+			} else if !seen[strings.ToLower(value)] {
+				seen[strings.ToLower(value)] = true
+				f.values = append(f.values, value)
+			}
+		}
+	}
+	if missing {
+		f.values = append(f.values, missingFacetValue)
+	}
+
+	box := container.NewVBox()
+	for _, value := range f.values {
+		value := value
+		check := widget.NewCheck(value, func(checked bool) {
+			f.selected[value] = checked
+			p.changed()
+		})
+		f.checks[value] = check
+		box.Add(check)
+	}
+	return box
+}
+
+func (p *facetPanel[T]) createRangeFacet(f *facet[T], items []T) fyne.CanvasObject {
+	f.min, f.max = f.def.Min, f.def.Max
+	if f.def.Max == 0 {
+		f.min, f.max = math.Inf(1), math.Inf(-1)
+		for i := range items {
+			if value, ok := f.def.Number(&items[i]); ok {
+				f.min = min(f.min, math.Floor(value))
+				f.max = max(f.max, math.Ceil(value))
+			}
+		}
+		if f.min > f.max {
+			return widget.NewLabelWithStyle("Aucune valeur", fyne.TextAlignLeading, fyne.TextStyle{Italic: true})
+		}
+	}
+	f.low, f.high = f.min, f.max
+
+	f.rangeLabel = widget.NewLabel("")
+	f.lowSlider = widget.NewSlider(f.min, max(f.max, f.min+1))
+	f.highSlider = widget.NewSlider(f.min, max(f.max, f.min+1))
+	f.lowSlider.SetValue(f.low)
+	f.highSlider.SetValue(f.high)
+
+	// Labels follow the sliders while dragging, the rows are filtered on release
+	f.lowSlider.OnChanged = func(value float64) {
+		f.low = value
+		if f.low > f.high {
+			f.highSlider.SetValue(f.low)
+		}
+		f.updateRangeLabel("")
+	}
+	f.highSlider.OnChanged = func(value float64) {
+		f.high = value
+		if f.high < f.low {
+			f.lowSlider.SetValue(f.high)
+		}
+		f.updateRangeLabel("")
+	}
+	f.lowSlider.OnChangeEnded = func(float64) { p.changed() }
+	f.highSlider.OnChangeEnded = func(float64) { p.changed() }
+
+	f.updateRangeLabel("")
+	return container.NewVBox(f.rangeLabel,
+		container.NewBorder(nil, nil, widget.NewLabel("Min"), nil, f.lowSlider),
+		container.NewBorder(nil, nil, widget.NewLabel("Max"), nil, f.highSlider),
+	)
+}
+
+func (p *facetPanel[T]) createToggleFacet(f *facet[T]) fyne.CanvasObject {
+	f.toggle = facetAll
+	f.toggleLabel = widget.NewLabel("")
+	f.radio = widget.NewRadioGroup([]string{facetAll, facetYes, facetNo}, func(choice string) {
+		f.toggle = choice
+		p.changed()
+	})
+	f.radio.Horizontal = true
+	f.radio.Required = true
+	f.radio.SetSelected(facetAll)
+	return container.NewVBox(f.radio, f.toggleLabel)
+}
+
+// Content returns the panel widgets
+func (p *facetPanel[T]) Content() fyne.CanvasObject {
+	return p.content
+}
+
+// Button returns the toolbar button showing or hiding the panel
+func (p *facetPanel[T]) Button() *widget.Button {
+	return p.button
+}
+
+// ActiveCount returns the number of facets narrowing down the rows
+func (p *facetPanel[T]) ActiveCount() int {
+	count := 0
+	for _, f := range p.facets {
+		if f.active() {
+			count++
+		}
+	}
+	return count
+}
+
+// Reset clears every facet
+func (p *facetPanel[T]) Reset() {
+	p.updating = true
+	for _, f := range p.facets {
+		for _, check := range f.checks {
+			check.SetChecked(false)
+		}
+		if f.lowSlider != nil {
+			f.lowSlider.SetValue(f.min)
+			f.highSlider.SetValue(f.max)
+			f.low, f.high = f.min, f.max
+		}
+		if f.radio != nil {
+			f.radio.SetSelected(facetAll)
+		}
+	}
+	p.updating = false
+	p.changed()
+}
+
+// Apply returns the items matching every facet, in the same order
+func (p *facetPanel[T]) Apply(items []T) []T {
+	if p.ActiveCount() == 0 {
+		return items
+	}
+	var matching []T
+	for i := range items {
+		if p.match(&items[i], nil) {
+			matching = append(matching, items[i])
+		}
+	}
+	return matching
+}
+
+// match checks an item against every facet but skip
+func (p *facetPanel[T]) match(item *T, skip *facet[T]) bool {
+	for _, f := range p.facets {
+		if f != skip && !f.match(item) {
+			return false
+		}
+	}
+	return true
+}
+
+// UpdateCounts shows next to each choice the number of items it would give
+// items are the rows found by the search bar; each facet counts the rows matching the others.
+func (p *facetPanel[T]) UpdateCounts(items []T) {
+	if active := p.ActiveCount(); active > 0 {
+		p.button.SetText(fmt.Sprintf("Filtres (%d)", active))
+	} else {
+		p.button.SetText("Filtres")
+	}
+
+	for _, f := range p.facets {
+		var base []*T
+		for i := range items {
+			if p.match(&items[i], f) {
+				base = append(base, &items[i])
+			}
+		}
+		f.updateCounts(base)
+	}
+}
+
+func (f *facet[T]) active() bool {
+	switch f.def.Kind {
+	case facetValues:
+		for _, checked := range f.selected {
+			if checked {
+				return true
+			}
+		}
+	case facetRange:
+		return f.lowSlider != nil && (f.low > f.min || f.high < f.max)
+	case facetToggle:
+		return f.toggle != facetAll
+	}
+	return false
+}
+
+func (f *facet[T]) match(item *T) bool {
+	if !f.active() {
+		return true
+	}
+	switch f.def.Kind {
+	case facetValues:
+		for _, value := range f.itemValues(item) {
+			if f.selected[value] {
+				return true
+			}
+		}
+		return false
+	case facetRange:
+		value, ok := f.def.Number(item)
+		return ok && value >= f.low && value <= f.high
+	case facetToggle:
+		return f.def.Bool(item) == (f.toggle == facetYes)
+	}
+	return true
+}
+
+// itemValues returns the values of an item as listed in the facet (matching the lookup case)
+func (f *facet[T]) itemValues(item *T) []string {
+	var values []string
+	for _, value := range f.def.Values(item) {
+		if value == "" {
+			continue
+		}
+		if i := slices.IndexFunc(f.values, func(v string) bool { return strings.EqualFold(v, value) }); i >= 0 {
+			value = f.values[i]
+		}
+		values = append(values, value)
+	}
+	if len(values) == 0 {
+		values = []string{missingFacetValue}
+	}
+	return values
+}
+
+func (f *facet[T]) updateCounts(base []*T) {
+	switch f.def.Kind {
+	case facetValues:
+		counts := make(map[string]int)
+		for _, item := range base {
+			for _, value := range f.itemValues(item) {
+				counts[value]++
+			}
+		}
+		for value, check := range f.checks {
+			check.Text = fmt.Sprintf("%s (%d)", value, counts[value])
+			check.Refresh()
+		}
+
+	case facetRange:
+		if f.lowSlider == nil {
+			return
+		}
+		count := 0
+		for _, item := range base {
+			if value, ok := f.def.Number(item); ok && value >= f.low && value <= f.high {
+				count++
+			}
+		}
+		f.updateRangeLabel(fmt.Sprintf(" (%d)", count))
+
+	case facetToggle:
+		yes := 0
+		for _, item := range base {
+			if f.def.Bool(item) {
+				yes++
+			}
+		}
+		f.toggleLabel.SetText(fmt.Sprintf("Oui: %d, Non: %d", yes, len(base)-yes))
+	}
+}
+
+// updateRangeLabel shows the bounds of a range facet, followed by the count when known
+func (f *facet[T]) updateRangeLabel(count string) {
+	format := f.def.Format
+	if format == nil {
+		format = func(value float64) string { return fmt.Sprintf("%.0f", value) }
+	}
+	f.rangeLabel.SetText(fmt.Sprintf("%s – %s%s", format(f.low), format(f.high), count))
+}
+
+// ========== Facets of each tab ==========
+
+// earliestYear returns the year of the first release among the regions
+func earliestYear(dates ...*time.Time) (float64, bool) {
+	years := yearValues(dates...)
+	if len(years) == 0 {
+		return 0, false
+	}
+	return slices.Min(years), true
+}
+
+func lookupNames[L any](items []L, err error, name func(L) string) ([]string, error) {
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, len(items))
+	for i, item := range items {
+		names[i] = name(item)
+	}
+	return names, nil
+}
+
+func conditionFacet[T any](condition func(item *T) *int) facetDef[T] {
+	return facetDef[T]{Label: "État", Kind: facetRange, Min: 1, Max: 5,
+		Number: func(item *T) (float64, bool) { return sortNumber(condition(item)) },
+		Format: func(value float64) string { return conditionToStars(intPtr(int(value))) }}
+}
+
+func priceFacet[T any](price func(item *T) *float64) facetDef[T] {
+	return facetDef[T]{Label: "Prix d'achat", Kind: facetRange,
+		Number: func(item *T) (float64, bool) {
+			if p := price(item); p != nil {
+				return *p, true
+			}
+			return 0, false
+		},
+		Format: func(value float64) string { return fmt.Sprintf("%.0f €", value) }}
+}
+
+func intPtr(value int) *int {
+	return &value
+}
+
+var gameFacets = []facetDef[Game]{
+	{Label: "Plateforme", Kind: facetValues,
+		Values: func(g *Game) []string { return []string{g.ConsoleName} },
+		Lookup: func(store CollectionStore) ([]string, error) {
+			consoles, err := store.GetConsoles()
+			return lookupNames(consoles, err, func(c Console) string { return c.Name })
+		}},
+	{Label: "Genre", Kind: facetValues,
+		Values: func(g *Game) []string { return []string{g.GenreName} },
+		Lookup: func(store CollectionStore) ([]string, error) {
+			genres, err := store.GetGenres()
+			return lookupNames(genres, err, func(g Genre) string { return g.Name })
+		}},
+	{Label: "Classification", Kind: facetValues,
+		Values: func(g *Game) []string { return []string{g.JPRating, g.USRating, g.EURating} },
+		Lookup: func(store CollectionStore) ([]string, error) {
+			ratings, err := store.GetRatingSystems()
+			return lookupNames(ratings, err, func(r RatingSystem) string { return r.Code })
+		}},
+	conditionFacet(func(g *Game) *int { return g.Condition }),
+	priceFacet(func(g *Game) *float64 { return g.PurchasePrice }),
+	{Label: "Année de sortie", Kind: facetRange,
+		Number: func(g *Game) (float64, bool) { return earliestYear(g.JPReleaseDate, g.USReleaseDate, g.EUReleaseDate) }},
+	{Label: "Complétude", Kind: facetValues,
+		Values: func(g *Game) []string { return []string{completenessLabels[g.Completeness]} },
+		Lookup: func(store CollectionStore) ([]string, error) {
+			var labels []string
+			for _, status := range completenessStatuses {
+				labels = append(labels, completenessLabels[status])
+			}
+			return labels, nil
+		}},
+	{Label: "Éléments manquants", Kind: facetValues,
+		Values: func(g *Game) []string { return g.MissingComponents },
+		Lookup: func(store CollectionStore) ([]string, error) {
+			components, err := store.GetCompletenessComponents()
+			return lookupNames(components, err, func(c CompletenessComponent) string { return c.Name })
+		}},
+	{Label: "Boîte", Kind: facetToggle, Bool: func(g *Game) bool { return boolValue(g.BoxOwned) }},
+	{Label: "Collector", Kind: facetToggle, Bool: func(g *Game) bool { return boolValue(g.Collector) }},
+	{Label: "Prêté", Kind: facetToggle, Bool: func(g *Game) bool { return g.LentTo != "" }},
+	{Label: "Progression", Kind: facetValues,
+		Values: func(g *Game) []string { return []string{playStatusText(g.PlayStatus)} },
+		Lookup: func(store CollectionStore) ([]string, error) {
+			var labels []string
+			for _, status := range playStatuses {
+				labels = append(labels, playStatusLabels[status])
+			}
+			return labels, nil
+		}},
+	{Label: "Note personnelle", Kind: facetRange, Min: 1, Max: 10,
+		Number: func(g *Game) (float64, bool) { return sortNumber(g.PersonalRating) },
+		Format: func(value float64) string { return ratingText(intPtr(int(value))) }},
+}
+
+var consoleFacets = []facetDef[Console]{
+	{Label: "Fabricant", Kind: facetValues,
+		Values: func(c *Console) []string { return []string{c.ManufacturerName} },
+		Lookup: func(store CollectionStore) ([]string, error) {
+			manufacturers, err := store.GetManufacturers()
+			return lookupNames(manufacturers, err, func(m Manufacturer) string { return m.Name })
+		}},
+	{Label: "Type", Kind: facetValues,
+		Values: func(c *Console) []string { return []string{c.TypeName} },
+		Lookup: func(store CollectionStore) ([]string, error) {
+			types, err := store.GetConsoleTypes()
+			return lookupNames(types, err, func(t ConsoleType) string { return t.Name })
+		}},
+	conditionFacet(func(c *Console) *int { return c.Condition }),
+	{Label: "Année de sortie", Kind: facetRange,
+		Number: func(c *Console) (float64, bool) {
+			return earliestYear(c.JPReleaseDate, c.USReleaseDate, c.EUReleaseDate)
+		}},
+	{Label: "Prêté", Kind: facetToggle, Bool: func(c *Console) bool { return c.LentTo != "" }},
+}
+
+var accessoryFacets = []facetDef[Accessory]{
+	{Label: "Type", Kind: facetValues,
+		Values: func(a *Accessory) []string { return []string{a.TypeName} },
+		Lookup: func(store CollectionStore) ([]string, error) {
+			types, err := store.GetAccessoryTypes()
+			return lookupNames(types, err, func(t AccessoryType) string { return t.Name })
+		}},
+	{Label: "Fabricant", Kind: facetValues,
+		Values: func(a *Accessory) []string { return []string{a.ManufacturerName} },
+		Lookup: func(store CollectionStore) ([]string, error) {
+			manufacturers, err := store.GetManufacturers()
+			return lookupNames(manufacturers, err, func(m Manufacturer) string { return m.Name })
+		}},
+	{Label: "Plateforme", Kind: facetValues,
+		Values: func(a *Accessory) []string { return a.Consoles },
+		Lookup: func(store CollectionStore) ([]string, error) {
+			consoles, err := store.GetConsoles()
+			return lookupNames(consoles, err, func(c Console) string { return c.Name })
+		}},
+	conditionFacet(func(a *Accessory) *int { return a.Condition }),
+	priceFacet(func(a *Accessory) *float64 { return a.PurchasePrice }),
+	{Label: "Prêté", Kind: facetToggle, Bool: func(a *Accessory) bool { return a.LentTo != "" }},
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"slices"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// ========== GALLERY VIEW ==========
+// Alternate view of the games tab showing the covers in a grid instead of the table.
+
+// prefGamesView is the preference key remembering the view of the games tab
+const prefGamesView = "gamesView"
+
+// Values of prefGamesView
+const (
+	gamesViewTable   = "table"
+	gamesViewGallery = "gallery"
+)
+
+// galleryCoverWidth and galleryCoverHeight give the size of a cover in the gallery (box art ratio)
+const (
+	galleryCoverWidth  = 150
+	galleryCoverHeight = 190
+)
+
+// gamesViewPreference returns the view chosen for the games tab, the table by default
+func gamesViewPreference() string {
+	return fyne.CurrentApp().Preferences().StringWithFallback(prefGamesView, gamesViewTable)
+}
+
+// setGamesViewPreference remembers the view of the games tab for the next sessions
+func setGamesViewPreference(view string) {
+	fyne.CurrentApp().Preferences().SetString(prefGamesView, view)
+}
+
+// newGalleryCell creates a gallery item: the cover with the title and platform underneath
+func newGalleryCell() fyne.CanvasObject {
+	cover := canvas.NewImageFromResource(theme.MediaPhotoIcon())
+	cover.FillMode = canvas.ImageFillContain
+	cover.SetMinSize(fyne.NewSize(galleryCoverWidth, galleryCoverHeight))
+
+	title := widget.NewLabelWithStyle("", fyne.TextAlignCenter, fyne.TextStyle{Bold: true})
+	title.Truncation = fyne.TextTruncateEllipsis
+	platform := widget.NewLabelWithStyle("", fyne.TextAlignCenter, fyne.TextStyle{Italic: true})
+	platform.Truncation = fyne.TextTruncateEllipsis
+
+	return container.NewBorder(nil, container.NewVBox(title, platform), nil, nil, cover)
+}
+
+// updateGalleryCell fills a cell made by newGalleryCell with a game
+func updateGalleryCell(obj fyne.CanvasObject, game Game) {
+	cell := obj.(*fyne.Container)
+	cover := cell.Objects[0].(*canvas.Image)
+	captions := cell.Objects[1].(*fyne.Container)
+
+	// Games without images show a placeholder icon
+	if game.CoverThumb != "" {
+		cover.Resource = nil
+		cover.File = game.CoverThumb
+	} else {
+		cover.File = ""
+		cover.Resource = theme.MediaPhotoIcon()
+	}
+	cover.Refresh()
+
+	captions.Objects[0].(*widget.Label).SetText(game.Title)
+	captions.Objects[1].(*widget.Label).SetText(game.ConsoleName)
+}
+
+// buildGamesGalleryWithSelection creates the games gallery and tracks selection like the table
+func buildGamesGalleryWithSelection(games []Game, detailsBtn, editBtn, deleteBtn *widget.Button, selectedGameID *int) *widget.GridWrap {
+	gallery := widget.NewGridWrap(
+		func() int {
+			return len(games)
+		},
+		newGalleryCell,
+		func(id widget.GridWrapItemID, obj fyne.CanvasObject) {
+			updateGalleryCell(obj, games[id])
+		},
+	)
+
+	gallery.OnSelected = func(id widget.GridWrapItemID) {
+		*selectedGameID = games[id].GameID
+		detailsBtn.Enable()
+		editBtn.Enable()
+		deleteBtn.Enable()
+	}
+
+	gallery.OnUnselected = func(id widget.GridWrapItemID) {
+		*selectedGameID = -1
+		detailsBtn.Disable()
+		editBtn.Disable()
+		deleteBtn.Disable()
+	}
+
+	// Keep the selected game selected after a sort or a search when it is still displayed
+	if index := slices.IndexFunc(games, func(g Game) bool { return g.GameID == *selectedGameID }); index >= 0 {
+		gallery.Select(index)
+	} else {
+		*selectedGameID = -1
+		detailsBtn.Disable()
+		editBtn.Disable()
+		deleteBtn.Disable()
+	}
+
+	return gallery
+}
@@ -0,0 +1,269 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// ========== GAME COPIES ==========
+// A game can be owned several times (other region, edition or state). Each copy has its own
+// condition, completeness (see completeness.go) and purchase; the game row keeps a summary used by the tables,
+// search, filters and dashboard (see syncGameCopies).
+
+// copyRegions are the usual regions offered in the copy form (any other text is accepted)
+var copyRegions = []string{"PAL", "PAL FR", "NTSC-U", "NTSC-J"}
+
+// copiesSummary shows the number of copies and their regions in the games table
+func copiesSummary(count int, regions []string) string {
+	if count == 0 {
+		return ""
+	}
+	if len(regions) == 0 {
+		return fmt.Sprintf("%d", count)
+	}
+	return fmt.Sprintf("%d (%s)", count, strings.Join(regions, ", "))
+}
+
+// copyDescription describes a copy on one line: region, edition, state, box and manual
+func copyDescription(c *GameCopy) string {
+	var parts []string
+	if c.Region != nil {
+		parts = append(parts, *c.Region)
+	}
+	if c.Edition != nil {
+		parts = append(parts, *c.Edition)
+	}
+	if c.Condition != nil {
+		parts = append(parts, conditionToStars(c.Condition))
+	}
+	switch {
+	case c.Completeness != "":
+		parts = append(parts, completenessLabels[c.Completeness])
+	case boolValue(c.BoxOwned) && boolValue(c.ManualOwned):
+		parts = append(parts, "boîte et notice")
+	case boolValue(c.BoxOwned):
+		parts = append(parts, "boîte")
+	case boolValue(c.ManualOwned):
+		parts = append(parts, "notice")
+	}
+	if c.PurchasePrice != nil {
+		parts = append(parts, fmt.Sprintf("%.2f", *c.PurchasePrice))
+	}
+	if len(parts) == 0 {
+		return "Exemplaire sans détails"
+	}
+	return strings.Join(parts, " · ")
+}
+
+// copyDescriptions describes every copy of a game (export column)
+func copyDescriptions(copies []GameCopy) []string {
+	var descriptions []string
+	for i := range copies {
+		descriptions = append(descriptions, copyDescription(&copies[i]))
+	}
+	return descriptions
+}
+
+// createGameCopiesSection lists the copies of a game in its detail dialog
+// onChange is called after a copy is added, edited or removed, as the game summary changes.
+func createGameCopiesSection(w fyne.Window, store CollectionStore, gameID int, onChange func()) fyne.CanvasObject {
+	section := container.NewVBox()
+
+	var reload func()
+	reload = func() {
+		changed := func() {
+			reload()
+			if onChange != nil {
+				onChange()
+			}
+		}
+
+		copies, err := store.GetGameCopies(gameID)
+		if err != nil {
+			section.Objects = []fyne.CanvasObject{widget.NewLabel(fmt.Sprintf("Échec de chargement des exemplaires: %v", err))}
+			section.Refresh()
+			return
+		}
+
+		objects := []fyne.CanvasObject{widget.NewLabel(fmt.Sprintf("Exemplaires (%d)", len(copies)))}
+		for _, c := range copies {
+			c := c
+			label := widget.NewLabel(copyDescription(&c))
+			label.Wrapping = fyne.TextWrapWord
+
+			editBtn := widget.NewButton("Éditer", func() {
+				showGameCopyDialog(w, store, c, changed)
+			})
+			deleteBtn := widget.NewButton("Supprimer", func() {
+				dialog.ShowConfirm("Supprimer l'exemplaire", fmt.Sprintf("Retirer « %s » de la collection?", copyDescription(&c)), func(confirmed bool) {
+					if !confirmed {
+						return
+					}
+					if err := store.DeleteGameCopy(c.CopyID); err != nil {
+						dialog.ShowError(err, w)
+						return
+					}
+					changed()
+				}, w)
+			})
+			deleteBtn.Importance = widget.DangerImportance
+
+			row := container.NewBorder(nil, nil, nil, container.NewHBox(editBtn, deleteBtn), label)
+			objects = append(objects, row)
+			if c.Completeness != "" && c.Completeness != GradeNew {
+				objects = append(objects, widget.NewLabel(missingComponentsText(c.Missing)))
+			}
+			if c.Notes != nil {
+				notes := widget.NewLabelWithStyle(*c.Notes, fyne.TextAlignLeading, fyne.TextStyle{Italic: true})
+				notes.Wrapping = fyne.TextWrapWord
+				objects = append(objects, notes)
+			}
+		}
+
+		addBtn := widget.NewButton("Ajouter un exemplaire", func() {
+			showGameCopyDialog(w, store, GameCopy{GameID: gameID}, changed)
+		})
+		objects = append(objects, container.NewHBox(addBtn))
+
+		section.Objects = objects
+		section.Refresh()
+	}
+
+	reload()
+	return section
+}
+
+// showGameCopyDialog adds (CopyID == 0) or edits a copy
+// The completeness checklist lists the components of the platform of the game; without
+// configured components, only the box and manual are asked.
+func showGameCopyDialog(w fyne.Window, store CollectionStore, gameCopy GameCopy, onSaved func()) {
+	components, err := store.GetGameComponents(gameCopy.GameID)
+	if err != nil {
+		dialog.ShowError(err, w)
+		return
+	}
+
+	regionEntry := widget.NewSelectEntry(copyRegions)
+	regionEntry.SetPlaceHolder("Région")
+	if gameCopy.Region != nil {
+		regionEntry.SetText(*gameCopy.Region)
+	}
+
+	editionEntry := widget.NewEntry()
+	editionEntry.SetPlaceHolder("Standard, Platinum, Player's Choice...")
+	if gameCopy.Edition != nil {
+		editionEntry.SetText(*gameCopy.Edition)
+	}
+
+	conditionLabel := widget.NewLabel("État: -")
+	conditionSlider := widget.NewSlider(1, 5)
+	conditionSlider.Step = 1
+	if gameCopy.Condition != nil {
+		conditionSlider.Value = float64(*gameCopy.Condition)
+		conditionLabel.SetText(fmt.Sprintf("État: %s", conditionToStars(gameCopy.Condition)))
+	}
+	conditionSet := gameCopy.Condition != nil
+	conditionSlider.OnChanged = func(value float64) {
+		conditionSet = true
+		conditionLabel.SetText(fmt.Sprintf("État: %s", conditionToStars(intPtr(int(value)))))
+	}
+
+	boxCheck := widget.NewCheck("Boîte", nil)
+	boxCheck.Checked = boolValue(gameCopy.BoxOwned)
+	manualCheck := widget.NewCheck("Notice", nil)
+	manualCheck.Checked = boolValue(gameCopy.ManualOwned)
+	var contents fyne.CanvasObject = container.NewHBox(boxCheck, manualCheck)
+
+	componentChecks := make([]*widget.Check, len(components))
+	if len(components) > 0 {
+		checklist := container.NewGridWithColumns(2)
+		for i, c := range components {
+			componentChecks[i] = widget.NewCheck(c.Name, nil)
+			// A new copy has at least its cartridge or disc
+			componentChecks[i].Checked = slices.Contains(gameCopy.Components, c.ComponentID) ||
+				(gameCopy.CopyID == 0 && c.Role == ComponentMedia)
+			checklist.Add(componentChecks[i])
+		}
+		contents = checklist
+	}
+
+	dateEntry := widget.NewEntry()
+	dateEntry.SetPlaceHolder("AAAA-MM-JJ")
+	if gameCopy.PurchaseDate != nil {
+		dateEntry.SetText(gameCopy.PurchaseDate.Format("2006-01-02"))
+	}
+
+	priceEntry := widget.NewEntry()
+	priceEntry.SetPlaceHolder("Prix d'achat")
+	if gameCopy.PurchasePrice != nil {
+		priceEntry.SetText(fmt.Sprintf("%.2f", *gameCopy.PurchasePrice))
+	}
+
+	notesEntry := widget.NewMultiLineEntry()
+	notesEntry.SetPlaceHolder("Notes")
+	if gameCopy.Notes != nil {
+		notesEntry.SetText(*gameCopy.Notes)
+	}
+
+	title := "Modifier l'exemplaire"
+	if gameCopy.CopyID == 0 {
+		title = "Ajouter un exemplaire"
+	}
+
+	dialog.ShowForm(title, "Enregistrer", "Annuler",
+		[]*widget.FormItem{
+			widget.NewFormItem("Région", regionEntry),
+			widget.NewFormItem("Édition", editionEntry),
+			widget.NewFormItem("", conditionLabel),
+			widget.NewFormItem("État", conditionSlider),
+			widget.NewFormItem("Contenu", contents),
+			widget.NewFormItem("Date d'achat", dateEntry),
+			widget.NewFormItem("Prix d'achat", priceEntry),
+			widget.NewFormItem("Notes", notesEntry),
+		},
+		func(confirmed bool) {
+			if !confirmed {
+				return
+			}
+
+			date, err := parseDateEntry(dateEntry.Text)
+			if err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+
+			boxOwned := boxCheck.Checked
+			manualOwned := manualCheck.Checked
+			gameCopy.Region = optionalText(strings.TrimSpace(regionEntry.Text))
+			gameCopy.Edition = optionalText(strings.TrimSpace(editionEntry.Text))
+			gameCopy.BoxOwned = &boxOwned
+			gameCopy.ManualOwned = &manualOwned
+			if len(components) > 0 {
+				gameCopy.Components = []int{}
+				for i, c := range components {
+					if componentChecks[i].Checked {
+						gameCopy.Components = append(gameCopy.Components, c.ComponentID)
+					}
+				}
+			}
+			gameCopy.PurchaseDate = date
+			gameCopy.PurchasePrice = parsePriceEntry(priceEntry.Text)
+			gameCopy.Notes = optionalText(notesEntry.Text)
+			gameCopy.Condition = nil
+			if conditionSet {
+				gameCopy.Condition = intPtr(int(conditionSlider.Value))
+			}
+
+			if _, err := store.SaveGameCopy(&gameCopy); err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			onSaved()
+		}, w)
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/widget"
+)
+
+// ========== IMAGES ==========
+
+// imageKinds lists the image kinds in display order
+var imageKinds = []string{ImageBoxFront, ImageBoxBack, ImageCartridge, ImagePhoto}
+
+// imageKindLabels names the image kinds in the UI
+var imageKindLabels = map[string]string{
+	ImageBoxFront:  "Boîte (avant)",
+	ImageBoxBack:   "Boîte (arrière)",
+	ImageCartridge: "Cartouche / disque",
+	ImagePhoto:     "Photo",
+}
+
+// detailThumbnailSize is the size of the thumbnails shown in the detail dialogs
+const detailThumbnailSize = 110
+
+// imageThumbnail is a clickable thumbnail with a caption
+type imageThumbnail struct {
+	widget.BaseWidget
+	path     string
+	caption  string
+	onTapped func()
+}
+
+func newImageThumbnail(path, caption string, onTapped func()) *imageThumbnail {
+	t := &imageThumbnail{path: path, caption: caption, onTapped: onTapped}
+	t.ExtendBaseWidget(t)
+	return t
+}
+
+func (t *imageThumbnail) CreateRenderer() fyne.WidgetRenderer {
+	img := canvas.NewImageFromFile(t.path)
+	img.FillMode = canvas.ImageFillContain
+	img.SetMinSize(fyne.NewSize(detailThumbnailSize, detailThumbnailSize))
+
+	caption := widget.NewLabelWithStyle(t.caption, fyne.TextAlignCenter, fyne.TextStyle{Italic: true})
+	return widget.NewSimpleRenderer(container.NewBorder(nil, caption, nil, nil, img))
+}
+
+func (t *imageThumbnail) Tapped(*fyne.PointEvent) {
+	if t.onTapped != nil {
+		t.onTapped()
+	}
+}
+
+func (t *imageThumbnail) Cursor() desktop.Cursor {
+	return desktop.PointerCursor
+}
+
+// createImageSection shows the images of an item in a detail dialog, with buttons to add
+// and remove them. onChange is called after each change (e.g. to refresh the table covers).
+func createImageSection(w fyne.Window, store CollectionStore, itemType string, itemID int, onChange func()) fyne.CanvasObject {
+	strip := container.NewHBox()
+	section := container.NewVBox()
+
+	var reload func()
+	reload = func() {
+		changed := func() {
+			reload()
+			if onChange != nil {
+				onChange()
+			}
+		}
+
+		images, err := store.GetImages(itemType, itemID)
+		if err != nil {
+			strip.Objects = []fyne.CanvasObject{widget.NewLabel(fmt.Sprintf("Échec de chargement des images: %v", err))}
+			strip.Refresh()
+			return
+		}
+
+		strip.Objects = nil
+		for _, img := range images {
+			img := img
+			strip.Add(newImageThumbnail(img.ThumbPath, imageKindLabels[img.Kind], func() {
+				showImageViewer(w, store, img, changed)
+			}))
+		}
+		if len(images) == 0 {
+			strip.Add(widget.NewLabelWithStyle("Aucune image", fyne.TextAlignLeading, fyne.TextStyle{Italic: true}))
+		}
+		strip.Refresh()
+
+		// Suggest the box front for the first image, a photo afterwards
+		defaultKind := ImagePhoto
+		if len(images) == 0 {
+			defaultKind = ImageBoxFront
+		}
+		addBtn := widget.NewButton("Ajouter une image", func() {
+			showAddImageDialog(w, store, itemType, itemID, defaultKind, changed)
+		})
+
+		section.Objects = []fyne.CanvasObject{
+			widget.NewLabel("Images"),
+			container.NewHScroll(strip),
+			container.NewHBox(addBtn),
+		}
+		section.Refresh()
+	}
+
+	reload()
+	return section
+}
+
+// showAddImageDialog picks a picture file then its kind, and attaches it to the item
+func showAddImageDialog(w fyne.Window, store CollectionStore, itemType string, itemID int, defaultKind string, onAdded func()) {
+	fileDialog := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		if reader == nil {
+			return // Cancelled
+		}
+		path := reader.URI().Path()
+		reader.Close()
+
+		var kindLabels []string
+		for _, kind := range imageKinds {
+			kindLabels = append(kindLabels, imageKindLabels[kind])
+		}
+		kindSelect := widget.NewSelect(kindLabels, nil)
+		kindSelect.SetSelected(imageKindLabels[defaultKind])
+
+		dialog.ShowForm("Ajouter une image", "Ajouter", "Annuler",
+			[]*widget.FormItem{widget.NewFormItem("Type", kindSelect)},
+			func(confirmed bool) {
+				if !confirmed {
+					return
+				}
+
+				kind := ImagePhoto
+				for k, label := range imageKindLabels {
+					if label == kindSelect.Selected {
+						kind = k
+					}
+				}
+
+				if _, err := store.AddImage(itemType, itemID, kind, path); err != nil {
+					dialog.ShowError(err, w)
+					return
+				}
+				if onAdded != nil {
+					onAdded()
+				}
+			}, w)
+	}, w)
+
+	fileDialog.SetFilter(storage.NewExtensionFileFilter([]string{".jpg", ".jpeg", ".png", ".gif", ".webp", ".JPG", ".JPEG", ".PNG"}))
+	fileDialog.Resize(fyne.NewSize(800, 600))
+	fileDialog.Show()
+}
+
+// showImageViewer displays an image in full size with a button to remove it
+func showImageViewer(w fyne.Window, store CollectionStore, img ItemImage, onDeleted func()) {
+	picture := canvas.NewImageFromFile(img.Path)
+	picture.FillMode = canvas.ImageFillContain
+	picture.SetMinSize(fyne.NewSize(500, 500))
+
+	var d dialog.Dialog
+
+	deleteBtn := widget.NewButton("Supprimer", func() {
+		dialog.ShowConfirm("Supprimer l'image", "Retirer cette image de la fiche?", func(confirmed bool) {
+			if !confirmed {
+				return
+			}
+			if err := store.DeleteImage(img.ImageID); err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			d.Hide()
+			if onDeleted != nil {
+				onDeleted()
+			}
+		}, w)
+	})
+	deleteBtn.Importance = widget.DangerImportance
+
+	closeBtn := widget.NewButton("Fermer", func() { d.Hide() })
+	buttonBar := container.NewCenter(container.NewHBox(deleteBtn, closeBtn))
+
+	d = dialog.NewCustomWithoutButtons(imageKindLabels[img.Kind], container.NewBorder(nil, buttonBar, nil, nil, picture), w)
+	d.Resize(fyne.NewSize(700, 700))
+	d.Show()
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	_ "image/gif" // Register decoders for image.Decode
+	_ "image/png"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+// ========== Media Cache ==========
+// Attached pictures are copied into a local, content-addressed cache:
+//   MEDIA_DIR/images/ab/abcdef....jpg  original file, named after its SHA-256
+//   MEDIA_DIR/thumb/ab/abcdef....jpg   thumbnail shown in the dialogs and tables
+// The database only stores the hash, so the same picture attached twice is kept once
+// and the media folder can be copied to another machine along with the database.
+
+// defaultMediaDir is used when MEDIA_DIR is not set
+const defaultMediaDir = "media"
+
+const (
+	thumbnailSize    = 256      // Longest side of generated thumbnails, in pixels
+	thumbnailQuality = 85       // JPEG quality of thumbnails
+	maxImageFileSize = 50 << 20 // Larger files are refused
+)
+
+// mediaCache stores image files on disk
+type mediaCache struct {
+	dir string
+}
+
+// newMediaCache uses the folder given by MEDIA_DIR in .env
+func newMediaCache() *mediaCache {
+	dir := strings.TrimSpace(os.Getenv("MEDIA_DIR"))
+	if dir == "" {
+		dir = defaultMediaDir
+	}
+	return &mediaCache{dir: dir}
+}
+
+// imagePath returns the location of an original image
+func (c *mediaCache) imagePath(hash, ext string) string {
+	return filepath.Join(c.dir, "images", hash[:2], hash+ext)
+}
+
+// thumbPath returns the location of a thumbnail (always JPEG)
+func (c *mediaCache) thumbPath(hash string) string {
+	return filepath.Join(c.dir, "thumb", hash[:2], hash+".jpg")
+}
+
+// add copies a picture into the cache and generates its thumbnail
+// Returns the hash and extension identifying the cached file.
+func (c *mediaCache) add(sourcePath string) (hash, ext string, err error) {
+	info, err := os.Stat(sourcePath)
+	if err != nil {
+		return "", "", fmt.Errorf("image introuvable: %w", err)
+	}
+	if info.Size() > maxImageFileSize {
+		return "", "", fmt.Errorf("image trop volumineuse (%d Mo maximum)", maxImageFileSize>>20)
+	}
+
+	content, err := os.ReadFile(sourcePath)
+	if err != nil {
+		return "", "", fmt.Errorf("lecture de l'image impossible: %w", err)
+	}
+
+	// Decoding validates the file and gives its real format whatever the file name says
+	img, format, err := image.Decode(bytes.NewReader(content))
+	if err != nil {
+		return "", "", fmt.Errorf("format d'image non reconnu (JPEG, PNG, GIF ou WebP attendu)")
+	}
+	ext = "." + format
+	if format == "jpeg" {
+		ext = ".jpg"
+	}
+
+	sum := sha256.Sum256(content)
+	hash = hex.EncodeToString(sum[:])
+
+	if err := writeCacheFile(c.imagePath(hash, ext), content); err != nil {
+		return "", "", fmt.Errorf("copie de l'image impossible: %w", err)
+	}
+	if err := c.writeThumbnail(hash, img); err != nil {
+		return "", "", fmt.Errorf("création de la miniature impossible: %w", err)
+	}
+
+	return hash, ext, nil
+}
+
+// writeThumbnail scales an image down to thumbnailSize and saves it as JPEG
+func (c *mediaCache) writeThumbnail(hash string, img image.Image) error {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width == 0 || height == 0 {
+		return fmt.Errorf("image vide")
+	}
+
+	// Keep the aspect ratio, never upscale
+	scale := min(1, float64(thumbnailSize)/float64(max(width, height)))
+	thumbWidth := max(1, int(float64(width)*scale))
+	thumbHeight := max(1, int(float64(height)*scale))
+
+	// JPEG has no transparency: draw on white
+	thumb := image.NewRGBA(image.Rect(0, 0, thumbWidth, thumbHeight))
+	draw.Draw(thumb, thumb.Bounds(), image.White, image.Point{}, draw.Src)
+	draw.CatmullRom.Scale(thumb, thumb.Bounds(), img, bounds, draw.Over, nil)
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, thumb, &jpeg.Options{Quality: thumbnailQuality}); err != nil {
+		return err
+	}
+	return writeCacheFile(c.thumbPath(hash), buf.Bytes())
+}
+
+// ensureThumbnail regenerates a missing thumbnail from the original
+// (e.g. when only the images folder was copied from another machine)
+func (c *mediaCache) ensureThumbnail(hash, ext string) error {
+	if _, err := os.Stat(c.thumbPath(hash)); err == nil {
+		return nil
+	}
+
+	content, err := os.ReadFile(c.imagePath(hash, ext))
+	if err != nil {
+		return err
+	}
+	img, _, err := image.Decode(bytes.NewReader(content))
+	if err != nil {
+		return err
+	}
+	return c.writeThumbnail(hash, img)
+}
+
+// remove deletes an image and its thumbnail from the cache
+func (c *mediaCache) remove(hash, ext string) {
+	for _, path := range []string{c.imagePath(hash, ext), c.thumbPath(hash)} {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			log.Println("Unable to remove cached image:", err)
+		}
+	}
+}
+
+// writeCacheFile writes a file of the cache unless it already exists (same name, same content)
+// It goes through a temporary file so an interrupted copy never leaves a truncated file
+// under its final name.
+func writeCacheFile(path string, content []byte) error {
+	if _, err := os.Stat(path); err == nil {
+		return nil // Same hash, same content
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,901 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/xuri/excelize/v2"
+	"golang.org/x/text/encoding/charmap"
+)
+
+// ========== SPREADSHEET IMPORT ==========
+// Rows of a CSV or XLSX file are mapped onto Game/Console/Accessory fields, checked against
+// the lookup tables without writing anything (dry run), then handed to the store which
+// inserts them in a single transaction. The wizard itself lives in import_dialog.go.
+
+// importField describes a model field a spreadsheet column can be mapped to
+type importField struct {
+	Key      string
+	Label    string
+	Aliases  []string // Other header names recognised automatically (normalized)
+	Required bool
+}
+
+var gameImportFields = []importField{
+	{Key: "title", Label: "Titre", Aliases: []string{"jeu", "nom", "name"}, Required: true},
+	{Key: "console", Label: "Plateforme", Aliases: []string{"console", "platform", "support"}, Required: true},
+	{Key: "genre", Label: "Genre"},
+	{Key: "developers", Label: "Développeur(s)", Aliases: []string{"developpeur", "developpeurs", "developer", "developers", "studio"}},
+	{Key: "publishers", Label: "Distributeur(s)", Aliases: []string{"distributeur", "distributeurs", "editeur", "editeurs", "publisher", "publishers"}},
+	{Key: "composers", Label: "Compositeur(s)", Aliases: []string{"compositeur", "compositeurs", "composer", "composers", "musique"}},
+	{Key: "producers", Label: "Producteur(s)", Aliases: []string{"producteur", "producteurs", "producer", "producers"}},
+	{Key: "jp_release_date", Label: "Sortie Japon", Aliases: []string{"sortie jp", "date jp", "jp release"}},
+	{Key: "us_release_date", Label: "Sortie USA", Aliases: []string{"sortie us", "date us", "us release"}},
+	{Key: "eu_release_date", Label: "Sortie Europe", Aliases: []string{"sortie eu", "date eu", "eu release"}},
+	{Key: "jp_rating", Label: "Classification Japon", Aliases: []string{"cero", "classification jp"}},
+	{Key: "us_rating", Label: "Classification USA", Aliases: []string{"esrb", "classification us"}},
+	{Key: "eu_rating", Label: "Classification Europe", Aliases: []string{"pegi", "classification eu"}},
+	{Key: "units_sold", Label: "Unités vendues", Aliases: []string{"ventes", "units sold"}},
+	{Key: "owned", Label: "Possédé", Aliases: []string{"possede", "owned"}},
+	{Key: "box_owned", Label: "Boîte", Aliases: []string{"boite possedee", "box", "box owned"}},
+	{Key: "collector", Label: "Édition collector", Aliases: []string{"collector"}},
+	{Key: "condition", Label: "État", Aliases: []string{"condition"}},
+	{Key: "purchase_date", Label: "Date d'achat", Aliases: []string{"date achat", "achete le", "purchase date"}},
+	{Key: "purchase_price", Label: "Prix d'achat", Aliases: []string{"prix", "prix achat", "price", "purchase price"}},
+	{Key: "target_price", Label: "Prix cible", Aliases: []string{"target price", "budget"}},
+	{Key: "wish_priority", Label: "Priorité", Aliases: []string{"priorite", "priority"}},
+	{Key: "wish_edition", Label: "Région / édition", Aliases: []string{"edition", "region", "version"}},
+	{Key: "notes", Label: "Notes", Aliases: []string{"note", "commentaire", "commentaires", "remarques"}},
+}
+
+var consoleImportFields = []importField{
+	{Key: "name", Label: "Nom", Aliases: []string{"console", "name"}, Required: true},
+	{Key: "type", Label: "Type", Required: true},
+	{Key: "manufacturer", Label: "Fabricant", Aliases: []string{"constructeur", "marque", "manufacturer"}, Required: true},
+	{Key: "generation", Label: "Génération", Aliases: []string{"gen", "generation"}},
+	{Key: "jp_release_date", Label: "Sortie Japon", Aliases: []string{"sortie jp", "date jp", "jp release"}},
+	{Key: "us_release_date", Label: "Sortie USA", Aliases: []string{"sortie us", "date us", "us release"}},
+	{Key: "eu_release_date", Label: "Sortie Europe", Aliases: []string{"sortie eu", "date eu", "eu release"}},
+	{Key: "discontinued", Label: "Fin de production", Aliases: []string{"discontinued"}},
+	{Key: "price_jpy", Label: "Prix de lancement (JPY)", Aliases: []string{"prix jpy", "price jpy"}},
+	{Key: "price_usd", Label: "Prix de lancement (USD)", Aliases: []string{"prix usd", "price usd"}},
+	{Key: "controllers", Label: "Ports contrôleurs", Aliases: []string{"manettes", "controllers"}},
+	{Key: "cpu", Label: "CPU", Aliases: []string{"processeur"}},
+	{Key: "gpu", Label: "GPU"},
+	{Key: "memory", Label: "Mémoire", Aliases: []string{"ram", "memory"}},
+	{Key: "audio", Label: "Audio", Aliases: []string{"son"}},
+	{Key: "units_sold", Label: "Unités vendues", Aliases: []string{"ventes", "units sold"}},
+	{Key: "top_game", Label: "Top ventes", Aliases: []string{"top game", "meilleure vente"}},
+	{Key: "predecessor", Label: "Prédécesseur", Aliases: []string{"predecessor"}},
+	{Key: "successor", Label: "Successeur", Aliases: []string{"successor"}},
+	{Key: "owned", Label: "Possédé", Aliases: []string{"possede", "owned"}},
+	{Key: "condition", Label: "État", Aliases: []string{"condition"}},
+	{Key: "purchase_date", Label: "Date d'achat", Aliases: []string{"date achat", "achete le", "purchase date"}},
+	{Key: "purchase_price", Label: "Prix d'achat", Aliases: []string{"prix", "prix achat", "price", "purchase price"}},
+	{Key: "target_price", Label: "Prix cible", Aliases: []string{"target price", "budget"}},
+	{Key: "wish_priority", Label: "Priorité", Aliases: []string{"priorite", "priority"}},
+	{Key: "wish_edition", Label: "Région / édition", Aliases: []string{"edition", "region", "version"}},
+	{Key: "notes", Label: "Notes", Aliases: []string{"note", "commentaire", "commentaires", "remarques"}},
+}
+
+var accessoryImportFields = []importField{
+	{Key: "name", Label: "Nom", Aliases: []string{"accessoire", "name"}, Required: true},
+	{Key: "type", Label: "Type", Required: true},
+	{Key: "manufacturer", Label: "Fabricant", Aliases: []string{"constructeur", "marque", "manufacturer"}},
+	{Key: "consoles", Label: "Plateformes compatibles", Aliases: []string{"plateforme", "plateformes", "console", "consoles", "platforms"}},
+	{Key: "color", Label: "Couleur", Aliases: []string{"color", "colour"}},
+	{Key: "condition", Label: "État", Aliases: []string{"condition"}},
+	{Key: "owned", Label: "Possédé", Aliases: []string{"possede", "owned"}},
+	{Key: "purchase_date", Label: "Date d'achat", Aliases: []string{"date achat", "achete le", "purchase date"}},
+	{Key: "purchase_price", Label: "Prix d'achat", Aliases: []string{"prix", "prix achat", "price", "purchase price"}},
+	{Key: "quantity", Label: "Quantité", Aliases: []string{"qte", "nombre", "quantity"}},
+	{Key: "target_price", Label: "Prix cible", Aliases: []string{"target price", "budget"}},
+	{Key: "wish_priority", Label: "Priorité", Aliases: []string{"priorite", "priority"}},
+	{Key: "wish_edition", Label: "Région / édition", Aliases: []string{"edition", "region", "version"}},
+	{Key: "notes", Label: "Notes", Aliases: []string{"note", "commentaire", "commentaires", "remarques"}},
+}
+
+// importFieldsFor returns the importable fields of an entity type ("game", "console" or "accessory")
+func importFieldsFor(entityType string) []importField {
+	switch entityType {
+	case "game":
+		return gameImportFields
+	case "console":
+		return consoleImportFields
+	default:
+		return accessoryImportFields
+	}
+}
+
+// lookupKindLabels names the lookup tables in messages shown to the user
+var lookupKindLabels = map[LookupKind]string{
+	LookupConsole:       "Plateforme",
+	LookupGenre:         "Genre",
+	LookupDeveloper:     "Développeur",
+	LookupComposer:      "Compositeur",
+	LookupPublisher:     "Distributeur",
+	LookupProducer:      "Producteur",
+	LookupManufacturer:  "Fabricant",
+	LookupConsoleType:   "Type de console",
+	LookupAccessoryType: "Type d'accessoire",
+}
+
+// ========== FILE READING ==========
+
+// importFile is the content of a spreadsheet to import
+type importFile struct {
+	Header []string
+	Rows   [][]string // Padded to the header width
+	Lines  []int      // Line number of each row in the file, for error messages
+}
+
+// readImportFile reads a .csv or .xlsx file (first sheet), dropping blank rows
+func readImportFile(path string) (*importFile, error) {
+	var records [][]string
+	var err error
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv", ".txt":
+		records, err = readCSVFile(path)
+	case ".xlsx", ".xlsm":
+		records, err = readXLSXFile(path)
+	default:
+		return nil, fmt.Errorf("format non pris en charge: %s (CSV ou XLSX attendu)", filepath.Ext(path))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Skip leading blank lines, the first non-blank line is the header
+	headerIndex := 0
+	for headerIndex < len(records) && isBlankRecord(records[headerIndex]) {
+		headerIndex++
+	}
+	if headerIndex == len(records) {
+		return nil, fmt.Errorf("le fichier est vide")
+	}
+
+	file := &importFile{Header: records[headerIndex]}
+	for i := range file.Header {
+		file.Header[i] = strings.TrimSpace(file.Header[i])
+	}
+
+	for i := headerIndex + 1; i < len(records); i++ {
+		if isBlankRecord(records[i]) {
+			continue
+		}
+		row := make([]string, len(file.Header))
+		copy(row, records[i])
+		file.Rows = append(file.Rows, row)
+		file.Lines = append(file.Lines, i+1)
+	}
+	if len(file.Rows) == 0 {
+		return nil, fmt.Errorf("aucune ligne à importer après l'en-tête")
+	}
+
+	return file, nil
+}
+
+// readCSVFile reads a CSV file, guessing the delimiter from the first line
+// Excel uses ';' when saving CSV with French regional settings
+func readCSVFile(path string) ([][]string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("lecture impossible: %w", err)
+	}
+
+	// Drop the byte order mark added by Excel
+	content = []byte(strings.TrimPrefix(string(content), "\ufeff"))
+	if !utf8.Valid(content) {
+		content = []byte(latin1ToUTF8(content))
+	}
+
+	firstLine, _, _ := strings.Cut(string(content), "\n")
+	reader := csv.NewReader(strings.NewReader(string(content)))
+	reader.Comma = detectCSVDelimiter(firstLine)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	var records [][]string
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("CSV invalide: %w", err)
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// detectCSVDelimiter picks the most frequent of ';', ',' and tab in the header line
+func detectCSVDelimiter(line string) rune {
+	best, bestCount := ',', 0
+	for _, candidate := range []rune{';', ',', '\t'} {
+		if n := strings.Count(line, string(candidate)); n > bestCount {
+			best, bestCount = candidate, n
+		}
+	}
+	return best
+}
+
+// latin1ToUTF8 converts Windows-1252/ISO-8859-1 text, the other encoding Excel saves CSV in
+// Windows-1252 is a superset of ISO-8859-1 for printable text, and has "€", "œ" and the curly
+// quotes where ISO-8859-1 has control characters.
+func latin1ToUTF8(content []byte) string {
+	decoded, err := charmap.Windows1252.NewDecoder().Bytes(content)
+	if err != nil {
+		return string(content)
+	}
+	return string(decoded)
+}
+
+// readXLSXFile reads the cells of the first sheet of a workbook as displayed by Excel
+func readXLSXFile(path string) ([][]string, error) {
+	f, err := excelize.OpenFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("lecture impossible: %w", err)
+	}
+	defer f.Close()
+
+	sheets := f.GetSheetList()
+	if len(sheets) == 0 {
+		return nil, fmt.Errorf("le classeur ne contient aucune feuille")
+	}
+
+	rows, err := f.GetRows(sheets[0])
+	if err != nil {
+		return nil, fmt.Errorf("lecture de la feuille '%s' impossible: %w", sheets[0], err)
+	}
+	return rows, nil
+}
+
+// isBlankRecord reports whether every cell of a record is empty
+func isBlankRecord(record []string) bool {
+	for _, cell := range record {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// ========== COLUMN MAPPING ==========
+
+// headerReplacer removes accents and separators so "Date_d'Achat" matches "date d'achat"
+var headerReplacer = strings.NewReplacer(
+	"é", "e", "è", "e", "ê", "e", "ë", "e",
+	"à", "a", "â", "a", "ä", "a",
+	"î", "i", "ï", "i", "ô", "o", "ö", "o",
+	"ù", "u", "û", "u", "ü", "u", "ç", "c",
+	"_", " ", "-", " ", "(", " ", ")", " ", "’", "'",
+)
+
+// normalizeHeader lowercases a header and strips accents and extra spaces
+func normalizeHeader(header string) string {
+	header = headerReplacer.Replace(strings.ToLower(strings.TrimSpace(header)))
+	return strings.Join(strings.Fields(header), " ")
+}
+
+// autoMapColumns guesses the field of each column from its header
+// Returns one field key per column, "" when the column is ignored
+func autoMapColumns(header []string, fields []importField) []string {
+	mapping := make([]string, len(header))
+	used := make(map[string]bool)
+
+	for col, h := range header {
+		name := normalizeHeader(h)
+		if name == "" {
+			continue
+		}
+		for _, field := range fields {
+			if used[field.Key] {
+				continue
+			}
+			if name == normalizeHeader(field.Key) || name == normalizeHeader(field.Label) || slices.Contains(field.Aliases, name) {
+				mapping[col] = field.Key
+				used[field.Key] = true
+				break
+			}
+		}
+	}
+	return mapping
+}
+
+// ========== DRY RUN ==========
+
+// importLookups holds the existing lookup entries, keyed by lowercase name
+type importLookups struct {
+	names   map[LookupKind]map[string]string // lowercase name -> stored name
+	pending map[LookupKind]map[string]string // Missing names met so far, lowercase -> spelling used
+	ratings map[string]map[string]int        // region -> lowercase code -> rating ID
+}
+
+// loadImportLookups reads every lookup table used to resolve names
+func loadImportLookups(store CollectionStore) (*importLookups, error) {
+	lookups := &importLookups{
+		names:   make(map[LookupKind]map[string]string),
+		pending: make(map[LookupKind]map[string]string),
+		ratings: make(map[string]map[string]int),
+	}
+	add := func(kind LookupKind, name string) {
+		if lookups.names[kind] == nil {
+			lookups.names[kind] = make(map[string]string)
+		}
+		lookups.names[kind][strings.ToLower(name)] = name
+	}
+
+	consoles, err := store.GetConsoles()
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range consoles {
+		add(LookupConsole, c.Name)
+	}
+
+	genres, err := store.GetGenres()
+	if err != nil {
+		return nil, err
+	}
+	for _, g := range genres {
+		add(LookupGenre, g.Name)
+	}
+
+	developers, err := store.GetDevelopers()
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range developers {
+		add(LookupDeveloper, d.Name)
+	}
+
+	composers, err := store.GetComposers()
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range composers {
+		add(LookupComposer, c.Name)
+	}
+
+	publishers, err := store.GetPublishers()
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range publishers {
+		add(LookupPublisher, p.Name)
+	}
+
+	producers, err := store.GetProducers()
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range producers {
+		add(LookupProducer, p.Name)
+	}
+
+	manufacturers, err := store.GetManufacturers()
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range manufacturers {
+		add(LookupManufacturer, m.Name)
+	}
+
+	consoleTypes, err := store.GetConsoleTypes()
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range consoleTypes {
+		add(LookupConsoleType, t.Name)
+	}
+
+	accessoryTypes, err := store.GetAccessoryTypes()
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range accessoryTypes {
+		add(LookupAccessoryType, t.Name)
+	}
+
+	ratings, err := store.GetRatingSystems()
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range ratings {
+		if lookups.ratings[r.Region] == nil {
+			lookups.ratings[r.Region] = make(map[string]int)
+		}
+		lookups.ratings[r.Region][strings.ToLower(r.Code)] = r.RatingID
+	}
+
+	return lookups, nil
+}
+
+// importRow is one data row of the file after validation
+type importRow struct {
+	Line    int           // Line number in the file
+	Label   string        // Title or name shown in the preview
+	Errors  []string      // Problems that prevent importing the row
+	Missing []LookupEntry // Referenced names absent from the lookup tables
+
+	game      *Game
+	console   *Console
+	accessory *Accessory
+}
+
+// valid reports whether the row can be imported
+// Rows referencing missing names are only valid if those names will be created
+func (r *importRow) valid(createMissing bool) bool {
+	return len(r.Errors) == 0 && (createMissing || len(r.Missing) == 0)
+}
+
+// importPreview is the result of the dry run
+type importPreview struct {
+	EntityType string
+	Rows       []importRow
+	Missing    []LookupEntry // Every missing name, once
+}
+
+// validCount returns the number of rows that would be imported
+func (p *importPreview) validCount(createMissing bool) int {
+	count := 0
+	for i := range p.Rows {
+		if p.Rows[i].valid(createMissing) {
+			count++
+		}
+	}
+	return count
+}
+
+// batch builds the rows to hand to the store, leaving invalid rows out
+func (p *importPreview) batch(createMissing bool) *ImportBatch {
+	batch := &ImportBatch{}
+	needed := make(map[LookupEntry]bool)
+
+	for i := range p.Rows {
+		row := &p.Rows[i]
+		if !row.valid(createMissing) {
+			continue
+		}
+		for _, m := range row.Missing {
+			needed[m] = true
+		}
+		switch {
+		case row.game != nil:
+			batch.Games = append(batch.Games, *row.game)
+		case row.console != nil:
+			batch.Consoles = append(batch.Consoles, *row.console)
+		case row.accessory != nil:
+			batch.Accessories = append(batch.Accessories, *row.accessory)
+		}
+	}
+
+	// Only create the names used by the rows actually imported
+	for _, m := range p.Missing {
+		if needed[m] {
+			batch.NewEntries = append(batch.NewEntries, m)
+		}
+	}
+	return batch
+}
+
+// prepareImport converts and validates every row without writing anything
+// mapping gives the field key of each column ("" = ignored).
+func prepareImport(entityType string, file *importFile, mapping []string, lookups *importLookups) *importPreview {
+	preview := &importPreview{EntityType: entityType}
+	fields := importFieldsFor(entityType)
+	lookups.pending = make(map[LookupKind]map[string]string)
+	missingSeen := make(map[LookupEntry]bool)
+	namesSeen := make(map[string]int) // Console names already in the file -> line
+
+	for i, record := range file.Rows {
+		p := &importRowParser{
+			values:  make(map[string]string),
+			fields:  fields,
+			lookups: lookups,
+			row:     &importRow{Line: file.Lines[i]},
+		}
+		for col, key := range mapping {
+			if key != "" && col < len(record) {
+				p.values[key] = strings.TrimSpace(record[col])
+			}
+		}
+
+		// Required fields
+		for _, field := range fields {
+			if field.Required && p.values[field.Key] == "" {
+				p.errorf("%s requis", field.Label)
+			}
+		}
+
+		switch entityType {
+		case "game":
+			p.row.game = p.parseGame()
+			p.row.Label = p.row.game.Title
+		case "console":
+			p.row.console = p.parseConsole()
+			p.row.Label = p.row.console.Name
+
+			// Console names are unique
+			name := strings.ToLower(p.row.console.Name)
+			if _, exists := lookups.names[LookupConsole][name]; exists {
+				p.errorf("console déjà présente dans la collection")
+			} else if line, dup := namesSeen[name]; dup && name != "" {
+				p.errorf("console en double (ligne %d)", line)
+			}
+			namesSeen[name] = p.row.Line
+		default:
+			p.row.accessory = p.parseAccessory()
+			p.row.Label = p.row.accessory.Name
+		}
+
+		for _, m := range p.row.Missing {
+			if !missingSeen[m] {
+				missingSeen[m] = true
+				preview.Missing = append(preview.Missing, m)
+			}
+		}
+		preview.Rows = append(preview.Rows, *p.row)
+	}
+
+	return preview
+}
+
+// importRowParser reads the mapped cells of one row and records the problems found
+type importRowParser struct {
+	values  map[string]string // Field key -> cell text
+	fields  []importField
+	lookups *importLookups
+	row     *importRow
+}
+
+func (p *importRowParser) errorf(format string, args ...any) {
+	p.row.Errors = append(p.row.Errors, fmt.Sprintf(format, args...))
+}
+
+// label returns the display name of a field for error messages
+func (p *importRowParser) label(key string) string {
+	for _, field := range p.fields {
+		if field.Key == key {
+			return field.Label
+		}
+	}
+	return key
+}
+
+func (p *importRowParser) parseGame() *Game {
+	game := &Game{
+		Title:         p.values["title"],
+		ConsoleName:   p.lookup("console", LookupConsole),
+		GenreName:     p.lookup("genre", LookupGenre),
+		Developers:    p.lookupList("developers", LookupDeveloper),
+		Publishers:    p.lookupList("publishers", LookupPublisher),
+		Composers:     p.lookupList("composers", LookupComposer),
+		Producers:     p.lookupList("producers", LookupProducer),
+		JPReleaseDate: p.date("jp_release_date"),
+		USReleaseDate: p.date("us_release_date"),
+		EUReleaseDate: p.date("eu_release_date"),
+		JPRatingID:    p.rating("jp_rating", "JP", "cero"),
+		USRatingID:    p.rating("us_rating", "US", "esrb"),
+		EURatingID:    p.rating("eu_rating", "EU", "pegi"),
+		UnitsSold:     p.integer("units_sold"),
+		Owned:         p.owned(),
+		BoxOwned:      p.boolean("box_owned"),
+		Collector:     p.boolean("collector"),
+		Condition:     p.condition(),
+		PurchaseDate:  p.date("purchase_date"),
+		PurchasePrice: p.price("purchase_price"),
+		Notes:         optionalText(p.values["notes"]),
+		TargetPrice:   p.price("target_price"),
+		WishPriority:  p.wishPriority(),
+		WishEdition:   optionalText(p.values["wish_edition"]),
+	}
+	return game
+}
+
+func (p *importRowParser) parseConsole() *Console {
+	console := &Console{
+		Name:             p.values["name"],
+		TypeName:         p.lookup("type", LookupConsoleType),
+		ManufacturerName: p.lookup("manufacturer", LookupManufacturer),
+		Generation:       p.integer("generation"),
+		JPReleaseDate:    p.date("jp_release_date"),
+		USReleaseDate:    p.date("us_release_date"),
+		EUReleaseDate:    p.date("eu_release_date"),
+		Discontinued:     p.date("discontinued"),
+		PriceJPY:         p.integer("price_jpy"),
+		PriceUSD:         p.integer("price_usd"),
+		Controllers:      p.integer("controllers"),
+		CPU:              optionalText(p.values["cpu"]),
+		GPU:              optionalText(p.values["gpu"]),
+		Memory:           optionalText(p.values["memory"]),
+		Audio:            optionalText(p.values["audio"]),
+		UnitsSold:        p.integer("units_sold"),
+		TopGame:          optionalText(p.values["top_game"]),
+		Predecessor:      optionalText(p.values["predecessor"]),
+		Successor:        optionalText(p.values["successor"]),
+		Owned:            p.owned(),
+		Condition:        p.condition(),
+		PurchaseDate:     p.date("purchase_date"),
+		PurchasePrice:    p.price("purchase_price"),
+		Notes:            optionalText(p.values["notes"]),
+		TargetPrice:      p.price("target_price"),
+		WishPriority:     p.wishPriority(),
+		WishEdition:      optionalText(p.values["wish_edition"]),
+	}
+	return console
+}
+
+func (p *importRowParser) parseAccessory() *Accessory {
+	accessory := &Accessory{
+		Name:             p.values["name"],
+		TypeName:         p.lookup("type", LookupAccessoryType),
+		ManufacturerName: p.lookup("manufacturer", LookupManufacturer),
+		Consoles:         p.lookupList("consoles", LookupConsole),
+		Color:            optionalText(p.values["color"]),
+		Condition:        p.condition(),
+		Owned:            p.owned(),
+		PurchaseDate:     p.date("purchase_date"),
+		PurchasePrice:    p.price("purchase_price"),
+		Quantity:         1,
+		Notes:            optionalText(p.values["notes"]),
+		TargetPrice:      p.price("target_price"),
+		WishPriority:     p.wishPriority(),
+		WishEdition:      optionalText(p.values["wish_edition"]),
+	}
+	if quantity := p.integer("quantity"); quantity != nil {
+		if *quantity < 1 {
+			p.errorf("%s doit être au moins 1", p.label("quantity"))
+		} else {
+			accessory.Quantity = *quantity
+		}
+	}
+	return accessory
+}
+
+// lookup returns the stored spelling of a referenced name, recording it as missing if unknown
+func (p *importRowParser) lookup(key string, kind LookupKind) string {
+	name := p.values[key]
+	if name == "" {
+		return ""
+	}
+	if stored, ok := p.lookups.names[kind][strings.ToLower(name)]; ok {
+		return stored
+	}
+
+	// A missing name keeps the first spelling met in the file
+	if p.lookups.pending[kind] == nil {
+		p.lookups.pending[kind] = make(map[string]string)
+	}
+	if pending, ok := p.lookups.pending[kind][strings.ToLower(name)]; ok {
+		name = pending
+	} else {
+		p.lookups.pending[kind][strings.ToLower(name)] = name
+	}
+
+	entry := LookupEntry{Kind: kind, Name: name}
+	if !slices.Contains(p.row.Missing, entry) {
+		p.row.Missing = append(p.row.Missing, entry)
+	}
+	return name
+}
+
+// lookupList resolves a cell holding several names separated by ',', ';' or '|'
+func (p *importRowParser) lookupList(key string, kind LookupKind) []string {
+	cell := p.values[key]
+	if cell == "" {
+		return nil
+	}
+
+	var names []string
+	for _, part := range strings.FieldsFunc(cell, func(r rune) bool { return r == ',' || r == ';' || r == '|' }) {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		p.values[key] = part
+		names = append(names, p.lookup(key, kind))
+	}
+	p.values[key] = cell
+	return names
+}
+
+// rating resolves a rating code such as "PEGI 12", or just "12" with the system prefix
+func (p *importRowParser) rating(key, region, prefix string) *int {
+	code := strings.ToLower(p.values[key])
+	if code == "" {
+		return nil
+	}
+	for _, candidate := range []string{code, prefix + " " + code} {
+		if id, ok := p.lookups.ratings[region][candidate]; ok {
+			return &id
+		}
+	}
+	p.errorf("%s inconnue: '%s'", p.label(key), p.values[key])
+	return nil
+}
+
+// importDateLayouts are the date formats accepted in files
+// 01-02-06 is the mm-dd-yy format used by default for XLSX date cells
+var importDateLayouts = []string{
+	"2006-01-02",
+	"02/01/2006",
+	"2/1/2006",
+	"02/01/06",
+	"02.01.2006",
+	"2006/01/02",
+	"01-02-06",
+}
+
+func (p *importRowParser) date(key string) *time.Time {
+	text := p.values[key]
+	if text == "" {
+		return nil
+	}
+
+	// Drop a time part ("1995-03-11 00:00:00")
+	if datePart, _, found := strings.Cut(text, " "); found {
+		text = datePart
+	}
+	for _, layout := range importDateLayouts {
+		if date, err := time.Parse(layout, text); err == nil {
+			return &date
+		}
+	}
+
+	// Date cells without a display format come out as an Excel serial number
+	if serial, err := strconv.ParseFloat(text, 64); err == nil && serial > 1 && serial < 100000 {
+		if date, err := excelize.ExcelDateToTime(serial, false); err == nil {
+			date = time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+			return &date
+		}
+	}
+
+	p.errorf("%s: date invalide '%s' (AAAA-MM-JJ ou JJ/MM/AAAA attendu)", p.label(key), p.values[key])
+	return nil
+}
+
+// thousandsPattern matches integers written with '.' or ',' thousands separators
+var thousandsPattern = regexp.MustCompile(`^\d{1,3}([.,]\d{3})+$`)
+
+// numberReplacer removes the spaces used as thousands separators
+var numberReplacer = strings.NewReplacer(" ", "", "\u00a0", "", "\u202f", "", "'", "")
+
+func (p *importRowParser) integer(key string) *int {
+	text := numberReplacer.Replace(p.values[key])
+	if text == "" {
+		return nil
+	}
+	if thousandsPattern.MatchString(text) {
+		text = strings.NewReplacer(".", "", ",", "").Replace(text)
+	}
+	if value, err := strconv.Atoi(text); err == nil {
+		return &value
+	}
+	// "12.0" from a numeric spreadsheet cell
+	if f, err := strconv.ParseFloat(strings.Replace(text, ",", ".", 1), 64); err == nil && f == float64(int(f)) {
+		value := int(f)
+		return &value
+	}
+
+	p.errorf("%s: nombre entier invalide '%s'", p.label(key), p.values[key])
+	return nil
+}
+
+// priceReplacer removes currency symbols and spaces from a price
+var priceReplacer = strings.NewReplacer("€", "", "$", "", "¥", "", "£", "", "EUR", "", "eur", "", " ", "", "\u00a0", "", "\u202f", "")
+
+func (p *importRowParser) price(key string) *float64 {
+	text := priceReplacer.Replace(p.values[key])
+	if text == "" {
+		return nil
+	}
+
+	// With both separators the first one groups thousands ("1.234,56" or "1,234.56")
+	comma, dot := strings.LastIndex(text, ","), strings.LastIndex(text, ".")
+	switch {
+	case comma >= 0 && dot >= 0 && comma > dot:
+		text = strings.ReplaceAll(text, ".", "")
+		text = strings.Replace(text, ",", ".", 1)
+	case comma >= 0 && dot >= 0:
+		text = strings.ReplaceAll(text, ",", "")
+	default:
+		text = strings.Replace(text, ",", ".", 1)
+	}
+
+	value, err := strconv.ParseFloat(text, 64)
+	if err != nil || value < 0 {
+		p.errorf("%s: prix invalide '%s'", p.label(key), p.values[key])
+		return nil
+	}
+	return &value
+}
+
+// parseImportBool reads the usual spellings of yes/no, ok is false for anything else
+func parseImportBool(text string) (value bool, ok bool) {
+	switch strings.ToLower(text) {
+	case "oui", "o", "yes", "y", "true", "vrai", "1", "x", "✓", "✔":
+		return true, true
+	case "non", "n", "no", "false", "faux", "0", "-":
+		return false, true
+	}
+	return false, false
+}
+
+func (p *importRowParser) boolean(key string) *bool {
+	text := p.values[key]
+	if text == "" {
+		return nil
+	}
+	value, ok := parseImportBool(text)
+	if !ok {
+		p.errorf("%s: valeur oui/non invalide '%s'", p.label(key), text)
+		return nil
+	}
+	return &value
+}
+
+// owned reads the "owned" column; without that column, or when its cell is empty, the item
+// is owned. Only an explicit no ("non", "0"...) sends it to the wishlist.
+func (p *importRowParser) owned() bool {
+	value := p.boolean("owned")
+	return value == nil || *value
+}
+
+// condition reads a 1-5 grade, given as a number ("4" or "4/5") or as stars ("★★★★☆")
+func (p *importRowParser) condition() *int {
+	text := p.values["condition"]
+	if text == "" {
+		return nil
+	}
+
+	var value int
+	if stars := strings.Count(text, "★"); stars > 0 {
+		value = stars
+	} else {
+		number, _, _ := strings.Cut(text, "/")
+		parsed, err := strconv.Atoi(strings.TrimSpace(number))
+		if err != nil {
+			p.errorf("%s invalide '%s' (1 à 5 attendu)", p.label("condition"), text)
+			return nil
+		}
+		value = parsed
+	}
+
+	if value < 1 || value > 5 {
+		p.errorf("%s invalide '%s' (1 à 5 attendu)", p.label("condition"), text)
+		return nil
+	}
+	return &value
+}
+
+// wishPriority reads a wishlist priority given as 1 to 3 or as its label (Haute, Moyenne, Basse)
+func (p *importRowParser) wishPriority() *int {
+	text := p.values["wish_priority"]
+	if text == "" {
+		return nil
+	}
+
+	for i, label := range wishPriorityLabels {
+		if normalizeHeader(text) == normalizeHeader(label) {
+			return intPtr(WishPriorityHigh + i)
+		}
+	}
+	value, err := strconv.Atoi(strings.TrimSpace(text))
+	if err != nil || value < WishPriorityHigh || value > WishPriorityLow {
+		p.errorf("%s invalide '%s' (Haute, Moyenne, Basse ou 1 à 3 attendu)", p.label("wish_priority"), text)
+		return nil
+	}
+	return &value
+}
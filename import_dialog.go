@@ -0,0 +1,337 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/widget"
+)
+
+// ========== IMPORT WIZARD ==========
+// File selection -> column mapping -> dry-run preview -> transactional insert
+
+// importIgnoreOption is the mapping choice for columns that are not imported
+const importIgnoreOption = "— Ignorer —"
+
+// showImportWizard asks for a CSV/XLSX file then walks through mapping and preview
+// entityType is "game", "console" or "accessory" as in createActionButtons
+func showImportWizard(w fyne.Window, store CollectionStore, entityType string, onSuccess func()) {
+	fileDialog := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		if reader == nil {
+			return // Cancelled
+		}
+		path := reader.URI().Path()
+		reader.Close()
+
+		file, err := readImportFile(path)
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("import impossible: %w", err), w)
+			return
+		}
+
+		lookups, err := loadImportLookups(store)
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("échec de chargement des listes de référence: %w", err), w)
+			return
+		}
+
+		showImportDialog(w, store, entityType, file, lookups, onSuccess)
+	}, w)
+
+	fileDialog.SetFilter(storage.NewExtensionFileFilter([]string{".csv", ".xlsx", ".CSV", ".XLSX"}))
+	fileDialog.Resize(fyne.NewSize(800, 600))
+	fileDialog.Show()
+}
+
+// showImportDialog shows the mapping and preview steps in a single dialog
+func showImportDialog(w fyne.Window, store CollectionStore, entityType string, file *importFile, lookups *importLookups, onSuccess func()) {
+	var d dialog.Dialog
+	content := container.NewStack()
+
+	fields := importFieldsFor(entityType)
+	mapping := autoMapColumns(file.Header, fields)
+
+	var showMappingStep func()
+	var showPreviewStep func()
+
+	showMappingStep = func() {
+		step := buildImportMappingStep(file, fields, mapping,
+			func() { d.Hide() },
+			func() {
+				// Every required field needs a column
+				var missing []string
+				for _, field := range fields {
+					if field.Required && !slices.Contains(mapping, field.Key) {
+						missing = append(missing, field.Label)
+					}
+				}
+				if len(missing) > 0 {
+					dialog.ShowError(fmt.Errorf("associez une colonne aux champs requis: %s", strings.Join(missing, ", ")), w)
+					return
+				}
+				showPreviewStep()
+			},
+		)
+		content.Objects = []fyne.CanvasObject{step}
+		content.Refresh()
+	}
+
+	showPreviewStep = func() {
+		preview := prepareImport(entityType, file, mapping, lookups)
+		step := buildImportPreviewStep(w, preview,
+			showMappingStep,
+			func() { d.Hide() },
+			func(createMissing bool) {
+				batch := preview.batch(createMissing)
+				if err := store.ImportCollection(batch); err != nil {
+					dialog.ShowError(fmt.Errorf("import annulé, aucune ligne enregistrée: %w", err), w)
+					return
+				}
+
+				count := len(batch.Games) + len(batch.Consoles) + len(batch.Accessories)
+				dialog.ShowInformation("Import terminé", fmt.Sprintf("%d ligne(s) importée(s).", count), w)
+				if onSuccess != nil {
+					onSuccess()
+				}
+				d.Hide()
+			},
+		)
+		content.Objects = []fyne.CanvasObject{step}
+		content.Refresh()
+	}
+
+	showMappingStep()
+
+	d = dialog.NewCustomWithoutButtons("Importer depuis un tableur", content, w)
+	d.Resize(fyne.NewSize(900, 700))
+	d.Show()
+}
+
+// buildImportMappingStep lets the user pick the field of each column
+// mapping is updated in place as selections change
+func buildImportMappingStep(file *importFile, fields []importField, mapping []string, onCancel, onNext func()) fyne.CanvasObject {
+	options := []string{importIgnoreOption}
+	keyByLabel := make(map[string]string)
+	labelByKey := make(map[string]string)
+	var required []string
+	for _, field := range fields {
+		label := field.Label
+		if field.Required {
+			label += " *"
+			required = append(required, field.Label)
+		}
+		options = append(options, label)
+		keyByLabel[label] = field.Key
+		labelByKey[field.Key] = label
+	}
+
+	intro := widget.NewLabel(fmt.Sprintf(
+		"%d ligne(s) trouvée(s). Associez chaque colonne du fichier à un champ. Champs requis: %s.",
+		len(file.Rows), strings.Join(required, ", ")))
+	intro.Wrapping = fyne.TextWrapWord
+
+	selects := make([]*widget.Select, len(file.Header))
+	grid := container.NewGridWithColumns(3,
+		widget.NewLabelWithStyle("Colonne du fichier", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		widget.NewLabelWithStyle("Champ", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		widget.NewLabelWithStyle("Exemple", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+	)
+
+	for col, header := range file.Header {
+		if header == "" {
+			header = fmt.Sprintf("(colonne %d)", col+1)
+		}
+
+		sel := widget.NewSelect(options, nil)
+		if mapping[col] != "" {
+			sel.SetSelected(labelByKey[mapping[col]])
+		} else {
+			sel.SetSelected(importIgnoreOption)
+		}
+
+		// A field can only be fed by one column: choosing it elsewhere releases it here
+		sel.OnChanged = func(selected string) {
+			key := keyByLabel[selected]
+			mapping[col] = key
+			if key == "" {
+				return
+			}
+			for other := range mapping {
+				if other != col && mapping[other] == key {
+					mapping[other] = ""
+					selects[other].SetSelected(importIgnoreOption)
+				}
+			}
+		}
+		selects[col] = sel
+
+		sample := widget.NewLabel(importSample(file.Rows, col))
+		sample.Truncation = fyne.TextTruncateEllipsis
+
+		headerLabel := widget.NewLabel(header)
+		headerLabel.Truncation = fyne.TextTruncateEllipsis
+
+		grid.Add(headerLabel)
+		grid.Add(sel)
+		grid.Add(sample)
+	}
+
+	cancelBtn := widget.NewButton("Annuler", onCancel)
+	nextBtn := widget.NewButton("Aperçu", onNext)
+	nextBtn.Importance = widget.HighImportance
+	buttonBar := container.NewCenter(container.NewHBox(cancelBtn, nextBtn))
+
+	return container.NewBorder(intro, buttonBar, nil, nil, container.NewVScroll(grid))
+}
+
+// importSample returns the first non-empty value of a column
+func importSample(rows [][]string, col int) string {
+	for _, row := range rows {
+		if col < len(row) && strings.TrimSpace(row[col]) != "" {
+			return strings.TrimSpace(row[col])
+		}
+	}
+	return ""
+}
+
+// buildImportPreviewStep lists every row with its validation result before importing
+func buildImportPreviewStep(w fyne.Window, preview *importPreview, onBack, onCancel func(), onImport func(createMissing bool)) fyne.CanvasObject {
+	createMissing := len(preview.Missing) > 0
+	summary := widget.NewLabel("")
+
+	importBtn := widget.NewButton("", nil)
+	importBtn.Importance = widget.HighImportance
+
+	table := widget.NewTableWithHeaders(
+		func() (int, int) {
+			return len(preview.Rows), 3
+		},
+		func() fyne.CanvasObject {
+			label := widget.NewLabel("")
+			label.Truncation = fyne.TextTruncateEllipsis
+			return label
+		},
+		func(id widget.TableCellID, obj fyne.CanvasObject) {
+			label := obj.(*widget.Label)
+			row := &preview.Rows[id.Row]
+
+			switch id.Col {
+			case 0:
+				label.SetText(fmt.Sprintf("%d", row.Line))
+			case 1:
+				label.SetText(row.Label)
+			case 2:
+				label.SetText(importRowStatus(row, createMissing))
+			}
+		},
+	)
+	table.UpdateHeader = func(id widget.TableCellID, obj fyne.CanvasObject) {
+		label := obj.(*widget.Label)
+		headers := []string{"Ligne", "Nom", "Statut"}
+		label.SetText(headers[id.Col])
+	}
+	table.ShowHeaderColumn = false
+	table.SetColumnWidth(0, 60)
+	table.SetColumnWidth(1, 300)
+	table.SetColumnWidth(2, 480)
+
+	// Full messages of a row on click
+	table.OnSelected = func(id widget.TableCellID) {
+		row := &preview.Rows[id.Row]
+		dialog.ShowInformation(fmt.Sprintf("Ligne %d", row.Line), importRowDetails(row, createMissing), w)
+		table.UnselectAll()
+	}
+
+	refresh := func() {
+		valid := preview.validCount(createMissing)
+		summary.SetText(fmt.Sprintf("%d ligne(s) prête(s) à importer, %d ignorée(s).", valid, len(preview.Rows)-valid))
+		importBtn.SetText(fmt.Sprintf("Importer %d ligne(s)", valid))
+		if valid == 0 {
+			importBtn.Disable()
+		} else {
+			importBtn.Enable()
+		}
+		table.Refresh()
+	}
+
+	importBtn.OnTapped = func() {
+		onImport(createMissing)
+	}
+
+	top := container.NewVBox(summary)
+
+	// Names absent from the lookup tables
+	if len(preview.Missing) > 0 {
+		missing := make([]string, 0, len(preview.Missing))
+		for _, m := range preview.Missing {
+			missing = append(missing, fmt.Sprintf("%s: %s", lookupKindLabels[m.Kind], m.Name))
+		}
+		sort.Strings(missing)
+
+		missingLabel := widget.NewLabel(strings.Join(missing, "\n"))
+		missingScroll := container.NewVScroll(missingLabel)
+		missingScroll.SetMinSize(fyne.NewSize(0, 100))
+
+		createCheck := widget.NewCheck(fmt.Sprintf("Créer les %d entrée(s) manquante(s) des listes de référence", len(preview.Missing)), func(checked bool) {
+			createMissing = checked
+			refresh()
+		})
+		createCheck.SetChecked(createMissing)
+
+		top.Add(widget.NewSeparator())
+		top.Add(createCheck)
+		top.Add(missingScroll)
+		top.Add(widget.NewLabelWithStyle("Sans création, les lignes concernées sont ignorées.", fyne.TextAlignLeading, fyne.TextStyle{Italic: true}))
+	}
+	top.Add(widget.NewSeparator())
+
+	backBtn := widget.NewButton("Retour", onBack)
+	cancelBtn := widget.NewButton("Annuler", onCancel)
+	buttonBar := container.NewCenter(container.NewHBox(backBtn, cancelBtn, importBtn))
+
+	refresh()
+	return container.NewBorder(top, buttonBar, nil, nil, table)
+}
+
+// importRowStatus summarizes the validation result of a row on one line
+func importRowStatus(row *importRow, createMissing bool) string {
+	switch {
+	case len(row.Errors) > 0:
+		return "Erreur: " + strings.Join(row.Errors, "; ")
+	case len(row.Missing) > 0 && !createMissing:
+		return "Ignorée: référence(s) inconnue(s)"
+	case len(row.Missing) > 0:
+		return "OK (crée des entrées de référence)"
+	default:
+		return "OK"
+	}
+}
+
+// importRowDetails lists every message of a row
+func importRowDetails(row *importRow, createMissing bool) string {
+	var lines []string
+	for _, e := range row.Errors {
+		lines = append(lines, "• "+e)
+	}
+	for _, m := range row.Missing {
+		action := "inconnu, ligne ignorée"
+		if createMissing {
+			action = "sera créé"
+		}
+		lines = append(lines, fmt.Sprintf("• %s '%s': %s", lookupKindLabels[m.Kind], m.Name, action))
+	}
+	if len(lines) == 0 {
+		return "Aucun problème, la ligne sera importée."
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestReadCSVFile(t *testing.T) {
+	want := [][]string{{"Titre", "Prix", "Possédé"}, {"Œuvre d'été", "12 €", "non"}}
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"UTF-8", "Titre;Prix;Possédé\nŒuvre d'été;12 €;non\n"},
+		{"UTF-8 with BOM", "\ufeffTitre;Prix;Possédé\r\nŒuvre d'été;12 €;non\r\n"},
+		// "é" is 0xE9, "€" 0x80 and "Œ" 0x8C in Windows-1252
+		{"Windows-1252", "Titre;Prix;Poss\xe9d\xe9\r\n\x8cuvre d'\xe9t\xe9;12 \x80;non\r\n"},
+	}
+
+	for _, tt := range tests {
+		path := filepath.Join(t.TempDir(), "import.csv")
+		if err := os.WriteFile(path, []byte(tt.content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		got, err := readCSVFile(path)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: read %q, want %q", tt.name, got, want)
+		}
+	}
+}
+
+func TestReadCSVFileWindows1252(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "import.csv")
+	if err := os.WriteFile(path, []byte("Titre\nC\x9cur \x93brav\xe9\x94\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	got, err := readCSVFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "Cœur “bravé”"; len(got) != 2 || got[1][0] != want {
+		t.Errorf("read %q, want %q on the second line", got, want)
+	}
+}
+
+func TestDetectCSVDelimiter(t *testing.T) {
+	tests := []struct {
+		line string
+		want rune
+	}{
+		{"Titre;Plateforme;Prix", ';'},
+		{"Titre,Plateforme,Prix", ','},
+		{"Titre\tPlateforme\tPrix", '\t'},
+		{"Titre;Prix (1,5 €)", ';'},
+		{`"Titre, sous-titre";Plateforme;Prix`, ';'},
+		{"Titre", ','},
+		{"", ','},
+	}
+
+	for _, tt := range tests {
+		if got := detectCSVDelimiter(tt.line); got != tt.want {
+			t.Errorf("detectCSVDelimiter(%q) = %q, want %q", tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestImportOwned(t *testing.T) {
+	tests := []struct {
+		name   string
+		values map[string]string
+		want   bool
+		errors int
+	}{
+		{"missing column", map[string]string{"title": "Zelda"}, true, 0},
+		{"empty cell", map[string]string{"owned": ""}, true, 0},
+		{"oui", map[string]string{"owned": "oui"}, true, 0},
+		{"Oui", map[string]string{"owned": "Oui"}, true, 0},
+		{"1", map[string]string{"owned": "1"}, true, 0},
+		{"non", map[string]string{"owned": "non"}, false, 0},
+		{"NON", map[string]string{"owned": "NON"}, false, 0},
+		{"0", map[string]string{"owned": "0"}, false, 0},
+		{"invalid", map[string]string{"owned": "peut-être"}, true, 1},
+	}
+
+	for _, tt := range tests {
+		p := &importRowParser{values: tt.values, fields: importFieldsFor("game"), row: &importRow{}}
+		if got := p.owned(); got != tt.want {
+			t.Errorf("%s: owned() = %t, want %t", tt.name, got, tt.want)
+		}
+		if len(p.row.Errors) != tt.errors {
+			t.Errorf("%s: errors %q, want %d", tt.name, p.row.Errors, tt.errors)
+		}
+	}
+}
@@ -0,0 +1,348 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// ========== LOANS ==========
+// Owned games, consoles and accessories can be lent: the detail dialogs record who has
+// them, since when and until when, and keep the past loans. Lent items are marked in the
+// tables and the dashboard lists the loans past their expected return.
+
+// lentBadge adds the borrower to the name of a lent item in the tables
+func lentBadge(name, lentTo string) string {
+	if lentTo == "" {
+		return name
+	}
+	return fmt.Sprintf("%s  [prêté à %s]", name, lentTo)
+}
+
+// loanDay returns the day of a date, so that loans are compared by day whatever the time zone
+func loanDay(t time.Time) string {
+	return t.Format("2006-01-02")
+}
+
+// isLoanOverdue tells whether a current loan is past its expected return
+func isLoanOverdue(loan *Loan, today time.Time) bool {
+	return loan.ReturnedOn == nil && loan.DueOn != nil && loanDay(*loan.DueOn) < loanDay(today)
+}
+
+// daysLate returns the number of days since the expected return of a loan
+func daysLate(loan *Loan, today time.Time) int {
+	due, _ := time.Parse("2006-01-02", loanDay(*loan.DueOn))
+	day, _ := time.Parse("2006-01-02", loanDay(today))
+	return int(day.Sub(due).Hours() / 24)
+}
+
+// overdueLoans keeps the overdue loans, the most late first
+func overdueLoans(loans []Loan, today time.Time) []Loan {
+	var overdue []Loan
+	for _, l := range loans {
+		if isLoanOverdue(&l, today) {
+			overdue = append(overdue, l)
+		}
+	}
+	sort.SliceStable(overdue, func(i, j int) bool {
+		return overdue[i].DueOn.Before(*overdue[j].DueOn)
+	})
+	return overdue
+}
+
+// loanDescription describes a loan on one line
+func loanDescription(loan *Loan, today time.Time) string {
+	text := fmt.Sprintf("%s, du %s", loan.Borrower, loanDay(loan.LentOn))
+	switch {
+	case loan.ReturnedOn != nil:
+		text += " au " + loanDay(*loan.ReturnedOn)
+	case loan.DueOn != nil && isLoanOverdue(loan, today):
+		text += fmt.Sprintf(", retour prévu le %s (en retard de %d jour(s))", loanDay(*loan.DueOn), daysLate(loan, today))
+	case loan.DueOn != nil:
+		text += ", retour prévu le " + loanDay(*loan.DueOn)
+	}
+	return text
+}
+
+// ========== Loan section ==========
+
+// createLoanSection shows the current loan of an item with buttons to lend it, mark it
+// returned and see the history
+// onChange is called after a loan is recorded, as the tables show the lent items.
+func createLoanSection(w fyne.Window, store CollectionStore, itemType string, itemID int, onChange func()) fyne.CanvasObject {
+	section := container.NewVBox()
+
+	var reload func()
+	reload = func() {
+		changed := func() {
+			reload()
+			if onChange != nil {
+				onChange()
+			}
+		}
+
+		loans, err := store.GetLoans(itemType, itemID)
+		if err != nil {
+			section.Objects = []fyne.CanvasObject{widget.NewLabel(fmt.Sprintf("Échec de chargement des prêts: %v", err))}
+			section.Refresh()
+			return
+		}
+
+		var current *Loan
+		for i := range loans {
+			if loans[i].ReturnedOn == nil {
+				current = &loans[i]
+			}
+		}
+
+		objects := []fyne.CanvasObject{widget.NewLabel("Prêt")}
+		lendBtn := widget.NewButton("Prêter", func() {
+			showLoanDialog(w, store, Loan{ItemType: itemType, ItemID: itemID, LentOn: time.Now()}, changed)
+		})
+		returnBtn := widget.NewButton("Rendu", nil)
+		if current != nil {
+			loan := *current
+			status := widget.NewLabelWithStyle("Prêté à "+loanDescription(&loan, time.Now()), fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+			status.Wrapping = fyne.TextWrapWord
+			objects = append(objects, status)
+			if loan.Notes != nil {
+				objects = append(objects, widget.NewLabelWithStyle(*loan.Notes, fyne.TextAlignLeading, fyne.TextStyle{Italic: true}))
+			}
+			lendBtn.Disable()
+			returnBtn.OnTapped = func() {
+				showLoanReturnDialog(w, store, loan, changed)
+			}
+		} else {
+			objects = append(objects, widget.NewLabelWithStyle("Disponible", fyne.TextAlignLeading, fyne.TextStyle{Italic: true}))
+			returnBtn.Disable()
+		}
+
+		historyBtn := widget.NewButton(fmt.Sprintf("Historique (%d)", len(loans)), func() {
+			showLoanHistory(w, store, loans, changed)
+		})
+		if len(loans) == 0 {
+			historyBtn.Disable()
+		}
+		objects = append(objects, container.NewHBox(lendBtn, returnBtn, historyBtn))
+
+		section.Objects = objects
+		section.Refresh()
+	}
+
+	reload()
+	return section
+}
+
+// showLoanDialog records a new loan (LoanID == 0) or edits one from the history
+func showLoanDialog(w fyne.Window, store CollectionStore, loan Loan, onSaved func()) {
+	borrowerEntry := widget.NewEntry()
+	borrowerEntry.SetPlaceHolder("Nom de l'emprunteur")
+	borrowerEntry.SetText(loan.Borrower)
+
+	lentEntry := widget.NewEntry()
+	lentEntry.SetPlaceHolder("AAAA-MM-JJ")
+	lentEntry.SetText(loanDay(loan.LentOn))
+
+	dueEntry := widget.NewEntry()
+	dueEntry.SetPlaceHolder("AAAA-MM-JJ (facultatif)")
+	if loan.DueOn != nil {
+		dueEntry.SetText(loanDay(*loan.DueOn))
+	}
+
+	returnedEntry := widget.NewEntry()
+	returnedEntry.SetPlaceHolder("AAAA-MM-JJ (vide tant qu'il n'est pas rendu)")
+	if loan.ReturnedOn != nil {
+		returnedEntry.SetText(loanDay(*loan.ReturnedOn))
+	}
+
+	notesEntry := widget.NewMultiLineEntry()
+	notesEntry.SetPlaceHolder("Notes")
+	if loan.Notes != nil {
+		notesEntry.SetText(*loan.Notes)
+	}
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Emprunteur", borrowerEntry),
+		widget.NewFormItem("Prêté le", lentEntry),
+		widget.NewFormItem("Retour prévu", dueEntry),
+	}
+	title := "Prêter"
+	if loan.LoanID != 0 {
+		title = "Modifier le prêt"
+		items = append(items, widget.NewFormItem("Rendu le", returnedEntry))
+	}
+	items = append(items, widget.NewFormItem("Notes", notesEntry))
+
+	dialog.ShowForm(title, "Enregistrer", "Annuler", items, func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+
+		loan.Borrower = strings.TrimSpace(borrowerEntry.Text)
+		if loan.Borrower == "" {
+			dialog.ShowError(fmt.Errorf("emprunteur requis"), w)
+			return
+		}
+		lentOn, err := parseDateEntry(lentEntry.Text)
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		if lentOn == nil {
+			dialog.ShowError(fmt.Errorf("date du prêt requise"), w)
+			return
+		}
+		loan.LentOn = *lentOn
+		if loan.DueOn, err = parseDateEntry(dueEntry.Text); err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		if loan.ReturnedOn, err = parseDateEntry(returnedEntry.Text); err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		if err := checkLoanDates(&loan); err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		loan.Notes = optionalText(notesEntry.Text)
+
+		if _, err := store.SaveLoan(&loan); err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		onSaved()
+	}, w)
+}
+
+// checkLoanDates refuses an expected or actual return before the loan
+func checkLoanDates(loan *Loan) error {
+	if loan.DueOn != nil && loanDay(*loan.DueOn) < loanDay(loan.LentOn) {
+		return fmt.Errorf("le retour prévu est avant la date du prêt")
+	}
+	if loan.ReturnedOn != nil && loanDay(*loan.ReturnedOn) < loanDay(loan.LentOn) {
+		return fmt.Errorf("la date de retour est avant la date du prêt")
+	}
+	return nil
+}
+
+// showLoanReturnDialog marks a loan returned, today unless another day is typed in
+func showLoanReturnDialog(w fyne.Window, store CollectionStore, loan Loan, onSaved func()) {
+	dateEntry := widget.NewEntry()
+	dateEntry.SetPlaceHolder("AAAA-MM-JJ")
+	dateEntry.SetText(loanDay(time.Now()))
+
+	dialog.ShowForm("Retour de prêt", "Enregistrer", "Annuler",
+		[]*widget.FormItem{
+			widget.NewFormItem("Prêté à", widget.NewLabel(loan.Borrower)),
+			widget.NewFormItem("Rendu le", dateEntry),
+		},
+		func(confirmed bool) {
+			if !confirmed {
+				return
+			}
+			date, err := parseDateEntry(dateEntry.Text)
+			if err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			if date == nil {
+				today := time.Now()
+				date = &today
+			}
+			loan.ReturnedOn = date
+			if err := checkLoanDates(&loan); err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			if _, err := store.SaveLoan(&loan); err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			onSaved()
+		}, w)
+}
+
+// showLoanHistory lists every loan of an item, newest first, with buttons to edit and remove them
+func showLoanHistory(w fyne.Window, store CollectionStore, loans []Loan, onChanged func()) {
+	var d dialog.Dialog
+
+	list := widget.NewList(
+		func() int { return len(loans) },
+		func() fyne.CanvasObject {
+			label := widget.NewLabel("")
+			label.Truncation = fyne.TextTruncateEllipsis
+			editBtn := widget.NewButton("Éditer", nil)
+			deleteBtn := widget.NewButton("Supprimer", nil)
+			deleteBtn.Importance = widget.DangerImportance
+			return container.NewBorder(nil, nil, nil, container.NewHBox(editBtn, deleteBtn), label)
+		},
+		func(id widget.ListItemID, obj fyne.CanvasObject) {
+			loan := loans[id]
+			row := obj.(*fyne.Container)
+			label := row.Objects[0].(*widget.Label)
+			buttons := row.Objects[1].(*fyne.Container)
+			editBtn := buttons.Objects[0].(*widget.Button)
+			deleteBtn := buttons.Objects[1].(*widget.Button)
+
+			text := loanDescription(&loan, time.Now())
+			if loan.Notes != nil {
+				text += "   " + *loan.Notes
+			}
+			label.SetText(text)
+
+			editBtn.OnTapped = func() {
+				d.Hide()
+				showLoanDialog(w, store, loan, onChanged)
+			}
+			deleteBtn.OnTapped = func() {
+				dialog.ShowConfirm("Supprimer le prêt", "Retirer ce prêt de l'historique?", func(confirmed bool) {
+					if !confirmed {
+						return
+					}
+					if err := store.DeleteLoan(loan.LoanID); err != nil {
+						dialog.ShowError(err, w)
+						return
+					}
+					d.Hide()
+					onChanged()
+				}, w)
+			}
+		},
+	)
+
+	closeBtn := widget.NewButton("Fermer", func() { d.Hide() })
+	d = dialog.NewCustomWithoutButtons("Historique des prêts", container.NewBorder(nil, container.NewCenter(closeBtn), nil, nil, list), w)
+	d.Resize(fyne.NewSize(700, 450))
+	d.Show()
+}
+
+// ========== Dashboard ==========
+
+// createOverdueLoansList lists the loans past their expected return
+func createOverdueLoansList(loans []Loan) fyne.CanvasObject {
+	if len(loans) == 0 {
+		return widget.NewLabelWithStyle("Aucun prêt en retard", fyne.TextAlignLeading, fyne.TextStyle{Italic: true})
+	}
+
+	today := time.Now()
+	list := container.NewVBox()
+	for _, l := range loans {
+		row := container.NewBorder(nil, nil,
+			container.NewHBox(
+				widget.NewLabel(loanDay(*l.DueOn)),
+				widget.NewLabelWithStyle(wishlistItemTypeLabels[l.ItemType], fyne.TextAlignLeading, fyne.TextStyle{Italic: true}),
+			),
+			widget.NewLabelWithStyle(fmt.Sprintf("%d jour(s) de retard", daysLate(&l, today)), fyne.TextAlignTrailing, fyne.TextStyle{Bold: true}),
+			widget.NewLabel(fmt.Sprintf("%s, prêté à %s le %s", l.ItemName, l.Borrower, loanDay(l.LentOn))),
+		)
+		list.Add(row)
+	}
+	return list
+}
@@ -1,80 +1,245 @@
 package main
 
 import (
-	"context"
 	"log"
+	"os"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/app"
 	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/layout"
 	"fyne.io/fyne/v2/widget"
 )
 
 func main() {
-	// Connect to database
-	conn, err := dbconnect()
-	if err != nil {
-		log.Fatal(err)
+	// With arguments, run a command in the terminal without opening a window (see cli.go)
+	if len(os.Args) > 1 {
+		os.Exit(runCLI(os.Args[1:], os.Stdout, os.Stderr))
 	}
-	defer conn.Close(context.Background())
 
-	// Create app
-	a := app.New()
+	// Create app (the ID gives the preferences a storage location)
+	a := app.NewWithID("fr.vgc.collector")
 	a.Settings().SetTheme(&compactTheme{})
 	w := a.NewWindow("VGC - Video Game Collector")
 
+	// Connect to database (the schema is created or upgraded on the way)
+	// If it fails, the window offers to retry instead of exiting
+	var store CollectionStore
+	onConnected := func(s CollectionStore) {
+		store = s
+		buildMainWindow(w, store)
+		startBackupSchedule(store, loadBackupSettings())
+	}
+
+	s, err := openStore()
+	if err != nil {
+		log.Println(err)
+		showConnectionErrorScreen(w, err, onConnected)
+	} else {
+		onConnected(s)
+	}
+
+	// Run app
+	w.Resize(fyne.NewSize(1400, 900))
+	w.ShowAndRun()
+
+	if store != nil {
+		if settings := loadBackupSettings(); settings.OnExit {
+			if path, _, err := createBackup(store, settings); err != nil {
+				log.Println("Backup on exit failed:", err)
+			} else {
+				log.Println("Backup written to", path)
+			}
+		}
+		store.Close()
+	}
+}
+
+// buildMainWindow creates the tabs and status bar once the database is reachable
+func buildMainWindow(w fyne.Window, store CollectionStore) {
+	// Changes made from the window can be undone (see undo.go)
+	undo := newUndoStore(store)
+	store = undo
+
 	// Create sidebar with tabs
 	sidebar := container.NewAppTabs(
-		container.NewTabItem("Accueil", widget.NewLabel("Dashboard - coming soon!")),
+		container.NewTabItem("Accueil", widget.NewLabel("Loading...")),
 		container.NewTabItem("Jeux", widget.NewLabel("Loading...")),
 		container.NewTabItem("Consoles", widget.NewLabel("Loading...")),
 		container.NewTabItem("Accessoires", widget.NewLabel("Loading...")),
+		container.NewTabItem("Liste de souhaits", widget.NewLabel("Loading...")),
 	)
 	sidebar.SetTabLocation(container.TabLocationLeading)
 
+	// The dashboard is rebuilt whenever one of the other tabs reloads its data
+	refreshDashboard := func() {
+		stats, err := store.GetDashboardStats()
+		if err != nil {
+			log.Println("Error fetching dashboard stats:", err)
+			return
+		}
+		sidebar.Items[0].Content = buildDashboardTab(stats)
+		sidebar.Refresh()
+	}
+
+	// The lists last fetched, shared by their tab and the wishlist
+	var games []Game
+	var consoles []Console
+	var accessories []Accessory
+
 	// Declare refresh functions as variables first
 	var refreshGamesTab func()
 	var refreshConsolesTab func()
 	var refreshAccessoriesTab func()
+	var refreshItemTab func(itemType string)
+
+	// The show functions rebuild one tab from the lists above, without fetching anything
+	showGamesTab := func() {
+		owned := ownedOnly(games, func(g *Game) bool { return g.Owned })
+		sidebar.Items[1].Content = buildJeuxTab(w, store, owned, refreshGamesTab)
+	}
+	showConsolesTab := func() {
+		owned := ownedOnly(consoles, func(c *Console) bool { return c.Owned })
+		sidebar.Items[2].Content = buildConsolesTab(w, store, owned, refreshConsolesTab)
+	}
+	showAccessoriesTab := func() {
+		owned := ownedOnly(accessories, func(a *Accessory) bool { return a.Owned })
+		sidebar.Items[3].Content = buildAccessoiresTab(w, store, owned, refreshAccessoriesTab)
+	}
+	// The wishlist lists the items of the three tables that are not owned yet; buying or
+	// editing a wanted item changes the tab of its type too
+	showWishlistTab := func() {
+		sidebar.Items[4].Content = buildWishlistTab(w, store, games, consoles, accessories, refreshItemTab)
+	}
 
-	// Now define them
+	// A change to one table refetches that table only, then rebuilds its tab, the wishlist
+	// and the dashboard once
 	refreshGamesTab = func() {
-		games, err := getGames(conn)
+		list, err := store.GetGames()
 		if err != nil {
 			log.Println("Error fetching games:", err)
 			return
 		}
-		sidebar.Items[1].Content = buildJeuxTab(w, conn, games, refreshGamesTab)
+		games = list
+		showGamesTab()
+		showWishlistTab()
 		sidebar.Refresh()
+		refreshDashboard()
 	}
 
 	refreshConsolesTab = func() {
-		consoles, err := getConsoles(conn)
+		list, err := store.GetConsoles()
 		if err != nil {
 			log.Println("Error fetching consoles:", err)
 			return
 		}
-		sidebar.Items[2].Content = buildConsolesTab(w, conn, consoles, refreshConsolesTab)
+		consoles = list
+		showConsolesTab()
+		showWishlistTab()
 		sidebar.Refresh()
+		refreshDashboard()
 	}
 
 	refreshAccessoriesTab = func() {
-		accessories, err := getAccessories(conn)
+		list, err := store.GetAccessories()
 		if err != nil {
 			log.Println("Error fetching accessories:", err)
 			return
 		}
-		sidebar.Items[3].Content = buildAccessoiresTab(w, conn, accessories, refreshAccessoriesTab)
+		accessories = list
+		showAccessoriesTab()
+		showWishlistTab()
 		sidebar.Refresh()
+		refreshDashboard()
+	}
+
+	// refreshAllTabs fetches the three tables once and rebuilds every tab
+	refreshAllTabs := func() {
+		var err error
+		if games, err = store.GetGames(); err != nil {
+			log.Println("Error fetching games:", err)
+			return
+		}
+		if consoles, err = store.GetConsoles(); err != nil {
+			log.Println("Error fetching consoles:", err)
+			return
+		}
+		if accessories, err = store.GetAccessories(); err != nil {
+			log.Println("Error fetching accessories:", err)
+			return
+		}
+		showGamesTab()
+		showConsolesTab()
+		showAccessoriesTab()
+		showWishlistTab()
+		sidebar.Refresh()
+		refreshDashboard()
+	}
+
+	// Reloads the tab of an item type, e.g. after an undo or redo
+	refreshItemTab = func(itemType string) {
+		switch itemType {
+		case "game":
+			refreshGamesTab()
+		case "console":
+			refreshConsolesTab()
+		case "accessory":
+			refreshAccessoriesTab()
+		default:
+			refreshAllTabs()
+		}
 	}
 
 	// Initial load of data
-	refreshGamesTab()
-	refreshConsolesTab()
-	refreshAccessoriesTab()
+	refreshAllTabs()
 
-	// Run app
-	w.SetContent(sidebar)
-	w.Resize(fyne.NewSize(1400, 900))
-	w.ShowAndRun()
+	// Status bar: reload everything when the connection comes back after an outage
+	connectionIndicator := createConnectionIndicator(store, refreshAllTabs)
+	statusBar := container.NewHBox(layout.NewSpacer(), connectionIndicator)
+
+	// Main menu (Fyne adds "Quitter" to the first menu)
+	fileMenu := fyne.NewMenu("Fichier",
+		fyne.NewMenuItem("Exporter la collection...", func() {
+			showExportDialog(w, store, "", nil, 0)
+		}),
+		fyne.NewMenuItem("Importer un guide de prix...", func() {
+			showPriceGuideImport(w, store, refreshDashboard)
+		}),
+		fyne.NewMenuItemSeparator(),
+		fyne.NewMenuItem("Sauvegarder maintenant", func() {
+			showBackupNow(w, store)
+		}),
+		fyne.NewMenuItem("Restaurer une sauvegarde...", func() {
+			showRestoreDialog(w, store, func() {
+				undo.clear()
+				refreshAllTabs()
+			})
+		}),
+		fyne.NewMenuItemSeparator(),
+		fyne.NewMenuItem("Éléments de complétude...", func() {
+			showCompletenessComponentsDialog(w, store, refreshGamesTab)
+		}),
+	)
+
+	thumbnailsItem := fyne.NewMenuItem("Miniatures dans les tableaux", nil)
+	thumbnailsItem.Checked = showTableThumbnails()
+	viewMenu := fyne.NewMenu("Affichage", thumbnailsItem)
+
+	var mainMenu *fyne.MainMenu
+	editMenu := newEditMenu(w, undo, refreshItemTab, func() {
+		if mainMenu != nil {
+			mainMenu.Refresh()
+		}
+	})
+
+	mainMenu = fyne.NewMainMenu(fileMenu, editMenu, viewMenu)
+	thumbnailsItem.Action = func() {
+		thumbnailsItem.Checked = !thumbnailsItem.Checked
+		fyne.CurrentApp().Preferences().SetBool(prefTableThumbnails, thumbnailsItem.Checked)
+		mainMenu.Refresh()
+		refreshAllTabs()
+	}
+	w.SetMainMenu(mainMenu)
+
+	w.SetContent(container.NewBorder(nil, statusBar, nil, nil, sidebar))
 }
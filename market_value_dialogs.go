@@ -0,0 +1,328 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/widget"
+)
+
+// ========== MARKET VALUE SECTION ==========
+// Shown in the detail dialogs: latest value of each grade, current value and gain
+
+// createMarketValueSection builds the market value part of a detail dialog
+// grades holds the grade of each unit valued (see valuationGrade): one per copy of a game,
+// one per accessory of the quantity; the purchase price covers all of them.
+func createMarketValueSection(w fyne.Window, store CollectionStore, itemType string, itemID int, grades []string, purchasePrice *float64) fyne.CanvasObject {
+	section := container.NewVBox()
+
+	var reload func()
+	reload = func() {
+		values, err := store.GetMarketValues(itemType, itemID)
+		if err != nil {
+			section.Objects = []fyne.CanvasObject{widget.NewLabel(fmt.Sprintf("Échec de chargement des cotes: %v", err))}
+			section.Refresh()
+			return
+		}
+
+		objects := []fyne.CanvasObject{widget.NewLabel("Cote")}
+		latest := latestMarketValues(values)
+		for _, g := range marketGrades {
+			if v, found := latest[g]; found {
+				objects = append(objects, widget.NewLabel(fmt.Sprintf("%s: %.2f (%s)", marketGradeLabels[g], v.Value, v.RecordedOn.Format("2006-01-02"))))
+			}
+		}
+
+		value, valued := 0.0, false
+		for _, grade := range grades {
+			if current, found := currentMarketValue(latest, grade); found {
+				value += current.Value
+				valued = true
+			}
+		}
+		if valued {
+			objects = append(objects, widget.NewLabelWithStyle(fmt.Sprintf("Valeur actuelle: %.2f", value), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}))
+			if purchasePrice != nil {
+				objects = append(objects, widget.NewLabel("Plus-value: "+formatGain(value, *purchasePrice)))
+			}
+		} else {
+			objects = append(objects, widget.NewLabelWithStyle("Aucune cote enregistrée", fyne.TextAlignLeading, fyne.TextStyle{Italic: true}))
+		}
+
+		addBtn := widget.NewButton("Ajouter une cote", func() {
+			showAddMarketValueDialog(w, store, itemType, itemID, grades[0], reload)
+		})
+		historyBtn := widget.NewButton("Historique", func() {
+			showMarketValueHistory(w, store, values, reload)
+		})
+		if len(values) == 0 {
+			historyBtn.Disable()
+		}
+		objects = append(objects, container.NewHBox(addBtn, historyBtn))
+
+		section.Objects = objects
+		section.Refresh()
+	}
+
+	reload()
+	return section
+}
+
+// showAddMarketValueDialog records a value typed in by hand
+func showAddMarketValueDialog(w fyne.Window, store CollectionStore, itemType string, itemID int, defaultGrade string, onAdded func()) {
+	var gradeOptions []string
+	for _, g := range marketGrades {
+		gradeOptions = append(gradeOptions, marketGradeLabels[g])
+	}
+	gradeSelect := widget.NewSelect(gradeOptions, nil)
+	gradeSelect.SetSelected(marketGradeLabels[defaultGrade])
+
+	valueEntry := widget.NewEntry()
+	valueEntry.SetPlaceHolder("Valeur")
+
+	dateEntry := widget.NewEntry()
+	dateEntry.SetPlaceHolder("AAAA-MM-JJ")
+	dateEntry.SetText(time.Now().Format("2006-01-02"))
+
+	sourceEntry := widget.NewEntry()
+	sourceEntry.SetPlaceHolder("Site, magazine, vente...")
+
+	dialog.ShowForm("Ajouter une cote", "Ajouter", "Annuler",
+		[]*widget.FormItem{
+			widget.NewFormItem("État", gradeSelect),
+			widget.NewFormItem("Valeur", valueEntry),
+			widget.NewFormItem("Relevée le", dateEntry),
+			widget.NewFormItem("Source", sourceEntry),
+		},
+		func(confirmed bool) {
+			if !confirmed {
+				return
+			}
+
+			value := parsePriceEntry(valueEntry.Text)
+			if value == nil || *value < 0 {
+				dialog.ShowError(fmt.Errorf("valeur requise"), w)
+				return
+			}
+			date, err := parseDateEntry(dateEntry.Text)
+			if err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			if date == nil {
+				today := time.Now()
+				date = &today
+			}
+
+			grade := defaultGrade
+			for g, label := range marketGradeLabels {
+				if label == gradeSelect.Selected {
+					grade = g
+				}
+			}
+
+			mv := MarketValue{
+				ItemType:   itemType,
+				ItemID:     itemID,
+				Grade:      grade,
+				Value:      *value,
+				RecordedOn: *date,
+				Source:     optionalText(sourceEntry.Text),
+			}
+			if err := store.AddMarketValues([]MarketValue{mv}); err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			onAdded()
+		}, w)
+}
+
+// showMarketValueHistory lists every recorded value of an item, newest first, with a delete button
+func showMarketValueHistory(w fyne.Window, store CollectionStore, values []MarketValue, onChanged func()) {
+	var d dialog.Dialog
+
+	list := widget.NewList(
+		func() int { return len(values) },
+		func() fyne.CanvasObject {
+			label := widget.NewLabel("")
+			label.Truncation = fyne.TextTruncateEllipsis
+			deleteBtn := widget.NewButton("Supprimer", nil)
+			deleteBtn.Importance = widget.DangerImportance
+			return container.NewBorder(nil, nil, nil, deleteBtn, label)
+		},
+		func(id widget.ListItemID, obj fyne.CanvasObject) {
+			v := values[id]
+			row := obj.(*fyne.Container)
+			label := row.Objects[0].(*widget.Label)
+			deleteBtn := row.Objects[1].(*widget.Button)
+
+			text := fmt.Sprintf("%s   %s   %.2f", v.RecordedOn.Format("2006-01-02"), marketGradeLabels[v.Grade], v.Value)
+			if v.Source != nil {
+				text += "   " + *v.Source
+			}
+			label.SetText(text)
+
+			deleteBtn.OnTapped = func() {
+				dialog.ShowConfirm("Supprimer la cote", "Retirer cette cote de l'historique?", func(confirmed bool) {
+					if !confirmed {
+						return
+					}
+					if err := store.DeleteMarketValue(v.ValueID); err != nil {
+						dialog.ShowError(err, w)
+						return
+					}
+					d.Hide()
+					onChanged()
+				}, w)
+			}
+		},
+	)
+
+	closeBtn := widget.NewButton("Fermer", func() { d.Hide() })
+	d = dialog.NewCustomWithoutButtons("Historique des cotes", container.NewBorder(nil, container.NewCenter(closeBtn), nil, nil, list), w)
+	d.Resize(fyne.NewSize(600, 450))
+	d.Show()
+}
+
+// ========== PRICE GUIDE IMPORT ==========
+// File selection -> matching preview -> transactional insert of the values
+
+// showPriceGuideImport asks for a price guide file and shows how its lines match the collection
+func showPriceGuideImport(w fyne.Window, store CollectionStore, onSuccess func()) {
+	fileDialog := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		if reader == nil {
+			return // Cancelled
+		}
+		path := reader.URI().Path()
+		reader.Close()
+
+		file, err := readImportFile(path)
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("import impossible: %w", err), w)
+			return
+		}
+
+		items, err := loadPriceGuideItems(store)
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("échec de chargement de la collection: %w", err), w)
+			return
+		}
+
+		preview, err := preparePriceGuide(file, items, time.Now())
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("import impossible: %w", err), w)
+			return
+		}
+		showPriceGuidePreview(w, store, preview, onSuccess)
+	}, w)
+
+	fileDialog.SetFilter(storage.NewExtensionFileFilter([]string{".csv", ".xlsx", ".CSV", ".XLSX"}))
+	fileDialog.Resize(fyne.NewSize(800, 600))
+	fileDialog.Show()
+}
+
+// loadPriceGuideItems loads every game, console and accessory, wanted ones included
+func loadPriceGuideItems(store CollectionStore) ([]priceGuideItem, error) {
+	games, err := store.GetGames()
+	if err != nil {
+		return nil, err
+	}
+	consoles, err := store.GetConsoles()
+	if err != nil {
+		return nil, err
+	}
+	accessories, err := store.GetAccessories()
+	if err != nil {
+		return nil, err
+	}
+	return priceGuideItems(games, consoles, accessories), nil
+}
+
+// showPriceGuidePreview lists every line with the item it matches before recording the values
+func showPriceGuidePreview(w fyne.Window, store CollectionStore, preview *priceGuidePreview, onSuccess func()) {
+	var d dialog.Dialog
+
+	matched := preview.matchedCount()
+	summary := widget.NewLabel(fmt.Sprintf("%d ligne(s) associée(s) à la collection, %d ignorée(s).", matched, len(preview.Rows)-matched))
+
+	table := widget.NewTableWithHeaders(
+		func() (int, int) {
+			return len(preview.Rows), 3
+		},
+		func() fyne.CanvasObject {
+			label := widget.NewLabel("")
+			label.Truncation = fyne.TextTruncateEllipsis
+			return label
+		},
+		func(id widget.TableCellID, obj fyne.CanvasObject) {
+			label := obj.(*widget.Label)
+			row := &preview.Rows[id.Row]
+
+			switch id.Col {
+			case 0:
+				label.SetText(fmt.Sprintf("%d", row.Line))
+			case 1:
+				label.SetText(row.Label)
+			case 2:
+				label.SetText(priceGuideRowStatus(row))
+			}
+		},
+	)
+	table.UpdateHeader = func(id widget.TableCellID, obj fyne.CanvasObject) {
+		label := obj.(*widget.Label)
+		headers := []string{"Ligne", "Nom", "Statut"}
+		label.SetText(headers[id.Col])
+	}
+	table.ShowHeaderColumn = false
+	table.SetColumnWidth(0, 60)
+	table.SetColumnWidth(1, 300)
+	table.SetColumnWidth(2, 480)
+
+	importBtn := widget.NewButton(fmt.Sprintf("Importer %d ligne(s)", matched), func() {
+		if err := store.AddMarketValues(preview.values()); err != nil {
+			dialog.ShowError(fmt.Errorf("import annulé, aucune cote enregistrée: %w", err), w)
+			return
+		}
+		dialog.ShowInformation("Import terminé", fmt.Sprintf("Cotes de %d élément(s) enregistrées.", matched), w)
+		if onSuccess != nil {
+			onSuccess()
+		}
+		d.Hide()
+	})
+	importBtn.Importance = widget.HighImportance
+	if matched == 0 {
+		importBtn.Disable()
+	}
+
+	cancelBtn := widget.NewButton("Annuler", func() { d.Hide() })
+	buttonBar := container.NewCenter(container.NewHBox(cancelBtn, importBtn))
+
+	d = dialog.NewCustomWithoutButtons("Importer un guide de prix", container.NewBorder(summary, buttonBar, nil, nil, table), w)
+	d.Resize(fyne.NewSize(900, 700))
+	d.Show()
+}
+
+// priceGuideRowStatus summarizes the matching result of a line
+func priceGuideRowStatus(row *priceGuideRow) string {
+	switch {
+	case len(row.Errors) > 0:
+		return "Erreur: " + strings.Join(row.Errors, "; ")
+	case row.Item == nil:
+		return "Ignorée: absent de la collection"
+	default:
+		var grades []string
+		for _, v := range row.Values {
+			grades = append(grades, fmt.Sprintf("%s %.2f", marketGradeLabels[v.Grade], v.Value))
+		}
+		return fmt.Sprintf("OK (%s): %s", wishlistItemTypeLabels[row.Item.ItemType], strings.Join(grades, ", "))
+	}
+}
@@ -0,0 +1,259 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ========== MARKET VALUES ==========
+// Each item can have dated market values for three grades (loose, complete in box, new).
+// The latest value of the grade matching the item is its current value, compared with
+// the purchase price in the detail dialogs and summed up on the dashboard.
+
+// marketGrades lists the grades in display order
+var marketGrades = []string{GradeLoose, GradeCIB, GradeNew}
+
+// marketGradeLabels names the grades in the UI
+var marketGradeLabels = map[string]string{
+	GradeLoose: "Loose",
+	GradeCIB:   "Complet en boîte",
+	GradeNew:   "Neuf",
+}
+
+// valuationGrade returns the grade used to value an item
+// Games kept with their box are complete; everything else is valued loose.
+func valuationGrade(itemType string, boxOwned *bool) string {
+	if itemType == "game" && boxOwned != nil && *boxOwned {
+		return GradeCIB
+	}
+	return GradeLoose
+}
+
+// gameValuationGrades returns the grade of each copy of a game, its completeness
+// A game without copies (wanted) is valued as if it were owned as described.
+func gameValuationGrades(game *Game) []string {
+	if len(game.Copies) == 0 {
+		return []string{valuationGrade("game", game.BoxOwned)}
+	}
+	grades := make([]string, len(game.Copies))
+	for i, c := range game.Copies {
+		grades[i] = c.Completeness
+		if grades[i] == "" {
+			grades[i] = valuationGrade("game", c.BoxOwned)
+		}
+	}
+	return grades
+}
+
+// latestMarketValues returns the latest value of each grade
+// values must be sorted newest first, as returned by GetMarketValues.
+func latestMarketValues(values []MarketValue) map[string]MarketValue {
+	latest := make(map[string]MarketValue)
+	for _, v := range values {
+		if _, found := latest[v.Grade]; !found {
+			latest[v.Grade] = v
+		}
+	}
+	return latest
+}
+
+// currentMarketValue picks the value of an item from the latest value of each grade
+// A boxed game without a complete-in-box value falls back to its loose value.
+func currentMarketValue(latest map[string]MarketValue, grade string) (MarketValue, bool) {
+	if v, found := latest[grade]; found {
+		return v, true
+	}
+	v, found := latest[GradeLoose]
+	return v, found
+}
+
+// formatGain shows a gain or loss with its sign and percentage of the price paid
+func formatGain(value, purchasePrice float64) string {
+	gain := value - purchasePrice
+	if purchasePrice == 0 {
+		return fmt.Sprintf("%+.2f", gain)
+	}
+	return fmt.Sprintf("%+.2f (%+.0f %%)", gain, gain*100/purchasePrice)
+}
+
+// ========== Price guide import ==========
+// A price guide is a CSV/XLSX file with one line per item and one column per grade,
+// such as the exports of price tracking sites. Lines are matched to the collection by
+// name, narrowed by the type and platform columns when the file has them.
+
+var priceGuideFields = []importField{
+	{Key: "name", Label: "Nom", Aliases: []string{"titre", "title", "jeu", "produit", "product", "product name", "product-name"}, Required: true},
+	{Key: "type", Label: "Type", Aliases: []string{"categorie", "category"}},
+	{Key: "console", Label: "Plateforme", Aliases: []string{"console", "platform", "console name", "console-name"}},
+	{Key: "loose", Label: "Loose", Aliases: []string{"loose price", "loose-price", "prix loose", "seul"}},
+	{Key: "cib", Label: "Complet en boîte", Aliases: []string{"cib", "cib price", "cib-price", "complet", "complete", "complete price"}},
+	{Key: "new", Label: "Neuf", Aliases: []string{"new", "new price", "new-price", "sealed", "scelle"}},
+	{Key: "date", Label: "Date", Aliases: []string{"releve le", "recorded on"}},
+	{Key: "source", Label: "Source", Aliases: []string{"site", "guide"}},
+}
+
+// priceGuideTypes maps the spellings of the type column to item types
+var priceGuideTypes = map[string]string{
+	"jeu": "game", "jeux": "game", "game": "game", "games": "game", "video games": "game",
+	"console": "console", "consoles": "console", "systems": "console",
+	"accessoire": "accessory", "accessoires": "accessory", "accessory": "accessory", "accessories": "accessory",
+}
+
+// priceGuideItem is an item of the collection that price guide lines can match
+type priceGuideItem struct {
+	ItemType string
+	ItemID   int
+	Name     string
+	Platform string // Console of a game, "" otherwise
+}
+
+// priceGuideRow is a line of the price guide after matching
+type priceGuideRow struct {
+	Line   int
+	Label  string
+	Item   *priceGuideItem // nil when no item matches
+	Values []MarketValue
+	Errors []string
+}
+
+// priceGuidePreview is the result of matching a price guide with the collection
+type priceGuidePreview struct {
+	Rows []priceGuideRow
+}
+
+// values returns the market values of the matched rows without errors
+func (p *priceGuidePreview) values() []MarketValue {
+	var values []MarketValue
+	for _, row := range p.Rows {
+		if row.Item != nil && len(row.Errors) == 0 {
+			values = append(values, row.Values...)
+		}
+	}
+	return values
+}
+
+// matchedCount returns the number of rows that will be imported
+func (p *priceGuidePreview) matchedCount() int {
+	count := 0
+	for _, row := range p.Rows {
+		if row.Item != nil && len(row.Errors) == 0 {
+			count++
+		}
+	}
+	return count
+}
+
+// priceGuideItems lists the games, consoles and accessories price guide lines can match
+func priceGuideItems(games []Game, consoles []Console, accessories []Accessory) []priceGuideItem {
+	var items []priceGuideItem
+	for _, g := range games {
+		items = append(items, priceGuideItem{ItemType: "game", ItemID: g.GameID, Name: g.Title, Platform: g.ConsoleName})
+	}
+	for _, c := range consoles {
+		items = append(items, priceGuideItem{ItemType: "console", ItemID: c.ConsoleID, Name: c.Name})
+	}
+	for _, a := range accessories {
+		items = append(items, priceGuideItem{ItemType: "accessory", ItemID: a.AccessoryID, Name: a.Name})
+	}
+	return items
+}
+
+// preparePriceGuide matches each line of a price guide with the collection and reads its values
+// Lines without a date are recorded on today.
+func preparePriceGuide(file *importFile, items []priceGuideItem, today time.Time) (*priceGuidePreview, error) {
+	mapping := autoMapColumns(file.Header, priceGuideFields)
+	if !slices.Contains(mapping, "name") {
+		return nil, fmt.Errorf("colonne du nom introuvable (en-têtes reconnus: Nom, Titre, product-name...)")
+	}
+	if !slices.Contains(mapping, GradeLoose) && !slices.Contains(mapping, GradeCIB) && !slices.Contains(mapping, GradeNew) {
+		return nil, fmt.Errorf("aucune colonne de prix (Loose, Complet en boîte ou Neuf)")
+	}
+
+	// Items by normalized name
+	byName := make(map[string][]priceGuideItem)
+	for _, item := range items {
+		key := normalizeHeader(item.Name)
+		byName[key] = append(byName[key], item)
+	}
+
+	preview := &priceGuidePreview{}
+	for i, record := range file.Rows {
+		p := &importRowParser{
+			values: make(map[string]string),
+			fields: priceGuideFields,
+			row:    &importRow{Line: file.Lines[i]},
+		}
+		for col, key := range mapping {
+			if key != "" && col < len(record) {
+				p.values[key] = strings.TrimSpace(record[col])
+			}
+		}
+
+		row := priceGuideRow{Line: p.row.Line, Label: p.values["name"]}
+		row.Item = matchPriceGuideItem(byName[normalizeHeader(p.values["name"])], p.values["type"], p.values["console"], p)
+
+		date := &today
+		if p.values["date"] != "" {
+			date = p.date("date")
+		}
+		source := optionalText(p.values["source"])
+		for _, grade := range marketGrades {
+			if value := p.price(grade); value != nil && date != nil {
+				row.Values = append(row.Values, MarketValue{Grade: grade, Value: *value, RecordedOn: *date, Source: source})
+			}
+		}
+		if len(row.Values) == 0 && len(p.row.Errors) == 0 {
+			p.errorf("aucun prix")
+		}
+
+		if row.Item != nil {
+			for j := range row.Values {
+				row.Values[j].ItemType = row.Item.ItemType
+				row.Values[j].ItemID = row.Item.ItemID
+			}
+		}
+		row.Errors = p.row.Errors
+		preview.Rows = append(preview.Rows, row)
+	}
+	return preview, nil
+}
+
+// matchPriceGuideItem picks the item a line refers to among the items of the same name
+// The type and platform columns break ties; an ambiguous line is reported as an error.
+func matchPriceGuideItem(candidates []priceGuideItem, itemType, platform string, p *importRowParser) *priceGuideItem {
+	if itemType != "" {
+		wanted, known := priceGuideTypes[normalizeHeader(itemType)]
+		if !known {
+			p.errorf("type inconnu '%s' (jeu, console ou accessoire attendu)", itemType)
+			return nil
+		}
+		candidates = filterPriceGuideItems(candidates, func(item priceGuideItem) bool { return item.ItemType == wanted })
+	}
+	if platform != "" && len(candidates) > 1 {
+		candidates = filterPriceGuideItems(candidates, func(item priceGuideItem) bool {
+			return item.ItemType != "game" || normalizeHeader(item.Platform) == normalizeHeader(platform)
+		})
+	}
+
+	switch len(candidates) {
+	case 0:
+		return nil
+	case 1:
+		return &candidates[0]
+	default:
+		p.errorf("%d éléments portent ce nom, précisez le type ou la plateforme", len(candidates))
+		return nil
+	}
+}
+
+func filterPriceGuideItems(items []priceGuideItem, keep func(item priceGuideItem) bool) []priceGuideItem {
+	var result []priceGuideItem
+	for _, item := range items {
+		if keep(item) {
+			result = append(result, item)
+		}
+	}
+	return result
+}
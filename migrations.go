@@ -0,0 +1,169 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ========== Schema Migrations ==========
+// Migrations are plain SQL files embedded in the binary, named NNNN_description.sql.
+// The highest applied version is recorded in schema_migrations; on startup every
+// file with a greater version is applied in order, each one in its own transaction.
+// Each backend has its own directory; version numbers are kept in lockstep.
+
+// dialect describes the SQL flavour of a backend
+type dialect struct {
+	name            string // "postgres" or "sqlite"
+	migrations      fs.FS  // embedded migration files
+	dir             string // directory of the migration files inside migrations
+	migrationsTable string // DDL of the schema_migrations table
+	yearOf          string // Expression giving the year of a DATE column (%s)
+	lower           string // Expression lowercasing a text (%s) like strings.ToLower does
+
+	// Used by backups (see backup.go)
+	tableColumns  string // Query listing the name and declared type of the columns of table $1, in order
+	dropTable     string // Statement dropping table %s
+	snapshot      string // First statement of a transaction that must read a consistent snapshot ("" if implicit)
+	resetSequence string // Statement moving the ID sequence of table %[1]s past its column %[2]s ("" if automatic)
+}
+
+// migration is one versioned SQL script
+type migration struct {
+	Version int
+	Name    string
+	SQL     string
+}
+
+// loadMigrations reads and sorts every migration file found in dir
+func loadMigrations(fsys fs.FS, dir string) ([]migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read migrations: %w", err)
+	}
+
+	var migrations []migration
+	seen := make(map[int]string)
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".sql") {
+			continue
+		}
+
+		// File names look like 0001_initial_schema.sql
+		base := strings.TrimSuffix(entry.Name(), ".sql")
+		prefix, name, found := strings.Cut(base, "_")
+		if !found {
+			return nil, fmt.Errorf("invalid migration file name %q", entry.Name())
+		}
+		version, err := strconv.Atoi(prefix)
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("invalid migration version in %q", entry.Name())
+		}
+		if other, exists := seen[version]; exists {
+			return nil, fmt.Errorf("duplicate migration version %d (%s and %s)", version, other, entry.Name())
+		}
+		seen[version] = entry.Name()
+
+		content, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("unable to read migration %s: %w", entry.Name(), err)
+		}
+
+		migrations = append(migrations, migration{
+			Version: version,
+			Name:    name,
+			SQL:     string(content),
+		})
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+// migrate creates or upgrades the database schema to the latest embedded version
+func migrate(db database, d dialect) error {
+	ctx := context.Background()
+
+	migrations, err := loadMigrations(d.migrations, d.dir)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec(ctx, d.migrationsTable)
+	if err != nil {
+		return fmt.Errorf("unable to create schema_migrations table: %w", err)
+	}
+
+	current, err := schemaVersion(db)
+	if err != nil {
+		return err
+	}
+
+	// Refuse to run against a schema written by a newer build
+	latest := 0
+	if len(migrations) > 0 {
+		latest = migrations[len(migrations)-1].Version
+	}
+	if current > latest {
+		return fmt.Errorf("database schema version %d is newer than the latest known version %d", current, latest)
+	}
+
+	for _, m := range migrations {
+		if m.Version <= current {
+			continue
+		}
+		if err := applyMigration(ctx, db, m); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// schemaVersion returns the highest applied migration version (0 for an empty database)
+func schemaVersion(db querier) (int, error) {
+	var version int
+	err := db.QueryRow(context.Background(),
+		"SELECT COALESCE(MAX(version), 0) FROM schema_migrations").Scan(&version)
+	if err != nil {
+		return 0, fmt.Errorf("unable to read schema version: %w", err)
+	}
+	return version, nil
+}
+
+// applyMigration runs a single migration and records it, all in one transaction
+func applyMigration(ctx context.Context, db database, m migration) error {
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to start migration %04d: %w", m.Version, err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := runMigration(ctx, tx, m); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// runMigration runs a migration and records it in the caller's transaction
+func runMigration(ctx context.Context, tx querier, m migration) error {
+	// Migration files hold several statements: without arguments pgx uses the
+	// simple protocol and SQLite runs every statement of the string
+	if _, err := tx.Exec(ctx, m.SQL); err != nil {
+		return fmt.Errorf("migration %04d_%s failed: %w", m.Version, m.Name, err)
+	}
+
+	_, err := tx.Exec(ctx,
+		"INSERT INTO schema_migrations (version, name) VALUES ($1, $2)",
+		m.Version, m.Name)
+	if err != nil {
+		return fmt.Errorf("unable to record migration %04d: %w", m.Version, err)
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+-- Reference data needed before the first console or game can be added
+-- (console type and manufacturer are required fields). Each table is only
+-- seeded while it is still empty so existing collections are left alone.
+
+INSERT INTO console_types (name)
+SELECT v.name FROM (VALUES
+	('Salon'),
+	('Portable'),
+	('Hybride')
+) AS v(name)
+WHERE NOT EXISTS (SELECT 1 FROM console_types);
+
+INSERT INTO accessory_types (name)
+SELECT v.name FROM (VALUES
+	('Manette'),
+	('Carte mémoire'),
+	('Câble'),
+	('Adaptateur'),
+	('Pistolet'),
+	('Autre')
+) AS v(name)
+WHERE NOT EXISTS (SELECT 1 FROM accessory_types);
+
+INSERT INTO manufacturers (name)
+SELECT v.name FROM (VALUES
+	('Nintendo'),
+	('Sega'),
+	('Sony'),
+	('Microsoft'),
+	('NEC'),
+	('SNK'),
+	('Atari')
+) AS v(name)
+WHERE NOT EXISTS (SELECT 1 FROM manufacturers);
+
+INSERT INTO rating_systems (region, code, description)
+SELECT v.region, v.code, v.description FROM (VALUES
+	('EU', 'PEGI 3', 'Tous publics'),
+	('EU', 'PEGI 7', 'Déconseillé aux moins de 7 ans'),
+	('EU', 'PEGI 12', 'Déconseillé aux moins de 12 ans'),
+	('EU', 'PEGI 16', 'Déconseillé aux moins de 16 ans'),
+	('EU', 'PEGI 18', 'Réservé aux adultes'),
+	('US', 'EC', 'Early Childhood'),
+	('US', 'E', 'Everyone'),
+	('US', 'E10+', 'Everyone 10+'),
+	('US', 'T', 'Teen'),
+	('US', 'M', 'Mature 17+'),
+	('US', 'AO', 'Adults Only 18+'),
+	('JP', 'CERO A', 'All ages'),
+	('JP', 'CERO B', 'Ages 12 and up'),
+	('JP', 'CERO C', 'Ages 15 and up'),
+	('JP', 'CERO D', 'Ages 17 and up'),
+	('JP', 'CERO Z', 'Ages 18 and up only')
+) AS v(region, code, description)
+WHERE NOT EXISTS (SELECT 1 FROM rating_systems);
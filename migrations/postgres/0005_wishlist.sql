@@ -0,0 +1,18 @@
+-- Wishlist: games, consoles and accessories with owned = FALSE are wanted rather than collected.
+-- The wish_* columns describe what to look for; wish_priority goes from 1 (high) to 3 (low).
+-- Consoles get the purchase columns that games and accessories already have, filled when
+-- a wanted console is bought.
+
+ALTER TABLE games ADD COLUMN target_price NUMERIC(10, 2);
+ALTER TABLE games ADD COLUMN wish_priority INTEGER CHECK (wish_priority BETWEEN 1 AND 3);
+ALTER TABLE games ADD COLUMN wish_edition TEXT;
+
+ALTER TABLE consoles ADD COLUMN target_price NUMERIC(10, 2);
+ALTER TABLE consoles ADD COLUMN wish_priority INTEGER CHECK (wish_priority BETWEEN 1 AND 3);
+ALTER TABLE consoles ADD COLUMN wish_edition TEXT;
+ALTER TABLE consoles ADD COLUMN purchase_date DATE;
+ALTER TABLE consoles ADD COLUMN purchase_price NUMERIC(10, 2);
+
+ALTER TABLE accessories ADD COLUMN target_price NUMERIC(10, 2);
+ALTER TABLE accessories ADD COLUMN wish_priority INTEGER CHECK (wish_priority BETWEEN 1 AND 3);
+ALTER TABLE accessories ADD COLUMN wish_edition TEXT;
@@ -0,0 +1,16 @@
+-- Dated market values of games, consoles and accessories, typed in or imported from a price guide.
+-- grade is how the item is sold: loose (alone), cib (complete in box) or new (sealed).
+-- The latest value of each grade is the current one; older rows keep the price history.
+
+CREATE TABLE IF NOT EXISTS market_values (
+	value_id    SERIAL PRIMARY KEY,
+	item_type   TEXT NOT NULL CHECK (item_type IN ('game', 'console', 'accessory')),
+	item_id     INTEGER NOT NULL,
+	grade       TEXT NOT NULL CHECK (grade IN ('loose', 'cib', 'new')),
+	value       NUMERIC(10, 2) NOT NULL CHECK (value >= 0),
+	recorded_on DATE NOT NULL,
+	source      TEXT,
+	created_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_market_values_item ON market_values (item_type, item_id, recorded_on);
@@ -0,0 +1,25 @@
+-- Physical copies of a game: region, edition, state and purchase of each cartridge or disc owned.
+-- The condition, box_owned, purchase_date and purchase_price columns of games become a summary
+-- of the copies (best condition, any box, latest purchase, total price) kept up to date by the store.
+
+CREATE TABLE IF NOT EXISTS game_copies (
+	copy_id        SERIAL PRIMARY KEY,
+	game_id        INTEGER NOT NULL REFERENCES games (game_id) ON DELETE CASCADE,
+	region         TEXT,
+	edition        TEXT,
+	condition      INTEGER CHECK (condition BETWEEN 1 AND 5),
+	box_owned      BOOLEAN,
+	manual_owned   BOOLEAN,
+	purchase_date  DATE,
+	purchase_price NUMERIC(10, 2),
+	notes          TEXT,
+	created_at     TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_game_copies_game ON game_copies (game_id);
+
+-- Every owned game starts with the copy described by its own columns
+INSERT INTO game_copies (game_id, condition, box_owned, purchase_date, purchase_price)
+SELECT game_id, condition, box_owned, purchase_date, purchase_price
+FROM games
+WHERE owned;
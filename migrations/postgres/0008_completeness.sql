@@ -0,0 +1,40 @@
+-- Completeness of game copies: which parts of the original release each copy still has.
+-- Components with a console_type_id only apply to the games of that type of console, the
+-- others to every game. role ties a component to the box_owned/manual_owned columns of the
+-- copies and to the status: a copy is "new" when a 'seal' component is present, "cib" when
+-- every required_for_cib component is, "loose" otherwise.
+
+CREATE TABLE IF NOT EXISTS completeness_components (
+	component_id     SERIAL PRIMARY KEY,
+	console_type_id  INTEGER REFERENCES console_types (type_id) ON DELETE CASCADE,
+	name             TEXT NOT NULL,
+	role             TEXT NOT NULL DEFAULT 'other' CHECK (role IN ('media', 'box', 'manual', 'seal', 'other')),
+	required_for_cib BOOLEAN NOT NULL DEFAULT FALSE,
+	position         INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE TABLE IF NOT EXISTS copy_components (
+	copy_id      INTEGER NOT NULL REFERENCES game_copies (copy_id) ON DELETE CASCADE,
+	component_id INTEGER NOT NULL REFERENCES completeness_components (component_id) ON DELETE CASCADE,
+	PRIMARY KEY (copy_id, component_id)
+);
+
+INSERT INTO completeness_components (name, role, required_for_cib, position)
+SELECT v.name, v.role, v.required_for_cib, v.position FROM (VALUES
+	('Cartouche / disque', 'media', TRUE, 1),
+	('Boîte', 'box', TRUE, 2),
+	('Notice', 'manual', TRUE, 3),
+	('Carte / poster', 'other', FALSE, 4),
+	('Carte d''enregistrement', 'other', FALSE, 5),
+	('Cales / inserts', 'other', FALSE, 6),
+	('Blister scellé', 'seal', FALSE, 7)
+) AS v(name, role, required_for_cib, position)
+WHERE NOT EXISTS (SELECT 1 FROM completeness_components);
+
+-- Existing copies have their cartridge or disc, plus the box and manual they were marked with
+INSERT INTO copy_components (copy_id, component_id)
+SELECT gc.copy_id, cc.component_id
+FROM game_copies gc
+JOIN completeness_components cc ON cc.role = 'media'
+	OR (cc.role = 'box' AND gc.box_owned)
+	OR (cc.role = 'manual' AND gc.manual_owned);
@@ -0,0 +1,17 @@
+-- Games, consoles and accessories lent to friends.
+-- A loan without returned_on is current; an item has at most one current loan and
+-- returned loans keep its history.
+
+CREATE TABLE IF NOT EXISTS loans (
+	loan_id     SERIAL PRIMARY KEY,
+	item_type   TEXT NOT NULL CHECK (item_type IN ('game', 'console', 'accessory')),
+	item_id     INTEGER NOT NULL,
+	borrower    TEXT NOT NULL CHECK (borrower <> ''),
+	lent_on     DATE NOT NULL,
+	due_on      DATE,
+	returned_on DATE,
+	notes       TEXT,
+	created_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_loans_item ON loans (item_type, item_id, lent_on);
@@ -0,0 +1,10 @@
+-- Play tracking: where each game stands (backlog, playing, completed, 100% or abandoned),
+-- when it was started and finished, the time spent on it, a personal rating out of 10 and
+-- a short review. A game without play_status has not been sorted yet.
+
+ALTER TABLE games ADD COLUMN play_status TEXT CHECK (play_status IN ('backlog', 'playing', 'completed', 'full', 'abandoned'));
+ALTER TABLE games ADD COLUMN play_started_on DATE;
+ALTER TABLE games ADD COLUMN play_finished_on DATE;
+ALTER TABLE games ADD COLUMN hours_played NUMERIC(7, 1) CHECK (hours_played >= 0);
+ALTER TABLE games ADD COLUMN personal_rating INTEGER CHECK (personal_rating BETWEEN 1 AND 10);
+ALTER TABLE games ADD COLUMN review TEXT;
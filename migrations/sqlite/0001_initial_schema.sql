@@ -0,0 +1,167 @@
+-- Initial VGC schema for SQLite, mirroring migrations/postgres/0001.
+-- INTEGER PRIMARY KEY columns are rowid aliases and get their values
+-- automatically, like SERIAL in PostgreSQL. Dates are stored as text.
+
+-- ========== Lookup Tables ==========
+
+CREATE TABLE IF NOT EXISTS genres (
+	genre_id INTEGER PRIMARY KEY,
+	name     TEXT NOT NULL UNIQUE
+);
+
+CREATE TABLE IF NOT EXISTS developers (
+	developer_id INTEGER PRIMARY KEY,
+	name         TEXT NOT NULL UNIQUE
+);
+
+CREATE TABLE IF NOT EXISTS composers (
+	composer_id INTEGER PRIMARY KEY,
+	name        TEXT NOT NULL UNIQUE
+);
+
+CREATE TABLE IF NOT EXISTS publishers (
+	publisher_id INTEGER PRIMARY KEY,
+	name         TEXT NOT NULL UNIQUE
+);
+
+CREATE TABLE IF NOT EXISTS producers (
+	producer_id INTEGER PRIMARY KEY,
+	name        TEXT NOT NULL UNIQUE
+);
+
+CREATE TABLE IF NOT EXISTS manufacturers (
+	manufacturer_id INTEGER PRIMARY KEY,
+	name            TEXT NOT NULL UNIQUE
+);
+
+CREATE TABLE IF NOT EXISTS console_types (
+	type_id INTEGER PRIMARY KEY,
+	name    TEXT NOT NULL UNIQUE
+);
+
+CREATE TABLE IF NOT EXISTS accessory_types (
+	type_id INTEGER PRIMARY KEY,
+	name    TEXT NOT NULL UNIQUE
+);
+
+CREATE TABLE IF NOT EXISTS rating_systems (
+	rating_id   INTEGER PRIMARY KEY,
+	region      TEXT NOT NULL CHECK (region IN ('JP', 'US', 'EU')),
+	code        TEXT NOT NULL,
+	description TEXT,
+	UNIQUE (region, code)
+);
+
+-- ========== Main Entities ==========
+
+CREATE TABLE IF NOT EXISTS consoles (
+	console_id      INTEGER PRIMARY KEY,
+	name            TEXT NOT NULL UNIQUE,
+	generation      INTEGER,
+	type_id         INTEGER REFERENCES console_types (type_id),
+	manufacturer_id INTEGER REFERENCES manufacturers (manufacturer_id),
+	jp_release_date DATE,
+	us_release_date DATE,
+	eu_release_date DATE,
+	discontinued    DATE,
+	price_jpy       INTEGER,
+	price_usd       INTEGER,
+	controllers     INTEGER,
+	cpu             TEXT,
+	gpu             TEXT,
+	memory          TEXT,
+	audio           TEXT,
+	units_sold      INTEGER,
+	top_game        TEXT,
+	predecessor     TEXT,
+	successor       TEXT,
+	owned           BOOLEAN NOT NULL DEFAULT FALSE,
+	condition       INTEGER CHECK (condition BETWEEN 1 AND 5),
+	notes           TEXT
+);
+
+CREATE TABLE IF NOT EXISTS games (
+	game_id         INTEGER PRIMARY KEY,
+	title           TEXT NOT NULL,
+	console_id      INTEGER REFERENCES consoles (console_id),
+	genre_id        INTEGER REFERENCES genres (genre_id),
+	jp_release_date DATE,
+	us_release_date DATE,
+	eu_release_date DATE,
+	jp_rating_id    INTEGER REFERENCES rating_systems (rating_id),
+	us_rating_id    INTEGER REFERENCES rating_systems (rating_id),
+	eu_rating_id    INTEGER REFERENCES rating_systems (rating_id),
+	units_sold      INTEGER,
+	owned           BOOLEAN NOT NULL DEFAULT FALSE,
+	box_owned       BOOLEAN,
+	collector       BOOLEAN,
+	condition       INTEGER CHECK (condition BETWEEN 1 AND 5),
+	purchase_date   DATE,
+	purchase_price  NUMERIC(10, 2),
+	notes           TEXT
+);
+
+CREATE TABLE IF NOT EXISTS accessories (
+	accessory_id    INTEGER PRIMARY KEY,
+	name            TEXT NOT NULL,
+	color           TEXT,
+	type_id         INTEGER REFERENCES accessory_types (type_id),
+	manufacturer_id INTEGER REFERENCES manufacturers (manufacturer_id),
+	condition       INTEGER CHECK (condition BETWEEN 1 AND 5),
+	owned           BOOLEAN NOT NULL DEFAULT FALSE,
+	purchase_date   DATE,
+	purchase_price  NUMERIC(10, 2),
+	quantity        INTEGER NOT NULL DEFAULT 1,
+	notes           TEXT
+);
+
+-- ========== Junction Tables ==========
+-- Credits and compatible consoles are removed along with their parent row
+
+CREATE TABLE IF NOT EXISTS game_developers (
+	game_id      INTEGER NOT NULL REFERENCES games (game_id) ON DELETE CASCADE,
+	developer_id INTEGER NOT NULL REFERENCES developers (developer_id),
+	PRIMARY KEY (game_id, developer_id)
+);
+
+CREATE TABLE IF NOT EXISTS game_composers (
+	game_id     INTEGER NOT NULL REFERENCES games (game_id) ON DELETE CASCADE,
+	composer_id INTEGER NOT NULL REFERENCES composers (composer_id),
+	PRIMARY KEY (game_id, composer_id)
+);
+
+CREATE TABLE IF NOT EXISTS game_publishers (
+	game_id      INTEGER NOT NULL REFERENCES games (game_id) ON DELETE CASCADE,
+	publisher_id INTEGER NOT NULL REFERENCES publishers (publisher_id),
+	PRIMARY KEY (game_id, publisher_id)
+);
+
+CREATE TABLE IF NOT EXISTS game_producers (
+	game_id     INTEGER NOT NULL REFERENCES games (game_id) ON DELETE CASCADE,
+	producer_id INTEGER NOT NULL REFERENCES producers (producer_id),
+	PRIMARY KEY (game_id, producer_id)
+);
+
+CREATE TABLE IF NOT EXISTS accessory_consoles (
+	accessory_id INTEGER NOT NULL REFERENCES accessories (accessory_id) ON DELETE CASCADE,
+	console_id   INTEGER NOT NULL REFERENCES consoles (console_id),
+	PRIMARY KEY (accessory_id, console_id)
+);
+
+-- ========== Indexes ==========
+-- Foreign keys used by the JOINs in the list/detail queries and the
+-- reverse side of each junction table
+
+CREATE INDEX IF NOT EXISTS idx_games_title ON games (title);
+CREATE INDEX IF NOT EXISTS idx_games_console_id ON games (console_id);
+CREATE INDEX IF NOT EXISTS idx_games_genre_id ON games (genre_id);
+CREATE INDEX IF NOT EXISTS idx_consoles_type_id ON consoles (type_id);
+CREATE INDEX IF NOT EXISTS idx_consoles_manufacturer_id ON consoles (manufacturer_id);
+CREATE INDEX IF NOT EXISTS idx_accessories_name ON accessories (name);
+CREATE INDEX IF NOT EXISTS idx_accessories_type_id ON accessories (type_id);
+CREATE INDEX IF NOT EXISTS idx_accessories_manufacturer_id ON accessories (manufacturer_id);
+CREATE INDEX IF NOT EXISTS idx_game_developers_developer_id ON game_developers (developer_id);
+CREATE INDEX IF NOT EXISTS idx_game_composers_composer_id ON game_composers (composer_id);
+CREATE INDEX IF NOT EXISTS idx_game_publishers_publisher_id ON game_publishers (publisher_id);
+CREATE INDEX IF NOT EXISTS idx_game_producers_producer_id ON game_producers (producer_id);
+CREATE INDEX IF NOT EXISTS idx_accessory_consoles_console_id ON accessory_consoles (console_id);
@@ -0,0 +1,17 @@
+-- Images attached to games, consoles and accessories (box art, cartridge, photos...).
+-- item_type/item_id point to the owning row; the store deletes the images along with it.
+-- The files live in the local media cache, named after the SHA-256 of their content,
+-- so the same picture attached twice is stored once.
+
+CREATE TABLE IF NOT EXISTS images (
+	image_id  INTEGER PRIMARY KEY,
+	item_type TEXT NOT NULL CHECK (item_type IN ('game', 'console', 'accessory')),
+	item_id   INTEGER NOT NULL,
+	kind      TEXT NOT NULL CHECK (kind IN ('box_front', 'box_back', 'cartridge', 'photo')),
+	hash      TEXT NOT NULL,
+	extension TEXT NOT NULL,
+	added_at  TEXT NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_images_item ON images (item_type, item_id);
+CREATE INDEX IF NOT EXISTS idx_images_hash ON images (hash);
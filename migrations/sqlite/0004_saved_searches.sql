@@ -0,0 +1,11 @@
+-- Named searches of the Jeux, Consoles and Accessoires tabs ("PS1 sans boîte"...).
+-- Only the query text is stored (see query.go): results are computed on the current data.
+
+CREATE TABLE IF NOT EXISTS saved_searches (
+	search_id   INTEGER PRIMARY KEY,
+	entity_type TEXT NOT NULL CHECK (entity_type IN ('game', 'console', 'accessory')),
+	name        TEXT NOT NULL,
+	query       TEXT NOT NULL,
+	created_at  TEXT NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	UNIQUE (entity_type, name)
+);
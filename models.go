@@ -6,139 +6,414 @@ import "time"
 
 // Game represents a game in the collection
 type Game struct {
-	GameID        int
-	Title         string
-	JPReleaseDate *time.Time
-	USReleaseDate *time.Time
-	EUReleaseDate *time.Time
-	UnitsSold     *int
-	Owned         bool
-	BoxOwned      *bool
-	Collector     *bool
-	Condition     *int
-	PurchaseDate  *time.Time
-	PurchasePrice *float64
-	Notes         *string
+	GameID        int        `json:"game_id"`
+	Title         string     `json:"title"`
+	JPReleaseDate *time.Time `json:"jp_release_date"`
+	USReleaseDate *time.Time `json:"us_release_date"`
+	EUReleaseDate *time.Time `json:"eu_release_date"`
+	UnitsSold     *int       `json:"units_sold"`
+	Owned         bool       `json:"owned"`
+	BoxOwned      *bool      `json:"box_owned"`
+	Collector     *bool      `json:"collector"`
+	Condition     *int       `json:"condition"`
+	PurchaseDate  *time.Time `json:"purchase_date"`
+	PurchasePrice *float64   `json:"purchase_price"`
+	Notes         *string    `json:"notes"`
+
+	// Wishlist (while Owned is false)
+	TargetPrice  *float64 `json:"target_price"`
+	WishPriority *int     `json:"wish_priority"` // 1 = high, 2 = medium, 3 = low
+	WishEdition  *string  `json:"wish_edition"`  // Preferred region or edition
+
+	// Play tracking
+	PlayStatus     *string    `json:"play_status"` // One of the Play* constants, nil when not sorted yet
+	PlayStartedOn  *time.Time `json:"play_started_on"`
+	PlayFinishedOn *time.Time `json:"play_finished_on"`
+	HoursPlayed    *float64   `json:"hours_played"`
+	PersonalRating *int       `json:"personal_rating"` // 1 to 10
+	Review         *string    `json:"review"`
 
 	// Foreign keys
-	ConsoleID  *int
-	GenreID    *int
-	JPRatingID *int
-	USRatingID *int
-	EURatingID *int
+	ConsoleID  *int `json:"console_id"`
+	GenreID    *int `json:"genre_id"`
+	JPRatingID *int `json:"jp_rating_id"`
+	USRatingID *int `json:"us_rating_id"`
+	EURatingID *int `json:"eu_rating_id"`
 
 	// Related data (for display in tables) - populated via JOINs
-	ConsoleName string
-	GenreName   string
-	JPRating    string
-	USRating    string
-	EURating    string
-	Developers  []string
-	Publishers  []string
-	Composers   []string
-	Producers   []string
+	ConsoleName string   `json:"console_name"`
+	GenreName   string   `json:"genre_name"`
+	JPRating    string   `json:"jp_rating"`
+	USRating    string   `json:"us_rating"`
+	EURating    string   `json:"eu_rating"`
+	Developers  []string `json:"developers"`
+	Publishers  []string `json:"publishers"`
+	Composers   []string `json:"composers"`
+	Producers   []string `json:"producers"`
+	CoverThumb  string   `json:"-"`       // Thumbnail of the first image, "" without image (list queries)
+	LentTo      string   `json:"lent_to"` // Borrower of the current loan, "" when not lent (list queries)
+
+	// Owned copies; Condition, BoxOwned, PurchaseDate and PurchasePrice summarize them
+	CopyCount         int        `json:"copy_count"`         // Number of copies (list queries)
+	CopyRegions       []string   `json:"copy_regions"`       // Regions of the copies (list queries)
+	Completeness      string     `json:"completeness"`       // Best status of the copies, a Grade* constant ("" without copies)
+	MissingComponents []string   `json:"missing_components"` // Components missing from at least one copy (list queries)
+	Copies            []GameCopy `json:"copies"`             // Every copy (GetGameByID)
+
+	// Many-to-many IDs matching the name slices above (used when saving)
+	DeveloperIDs []int `json:"developer_ids"`
+	PublisherIDs []int `json:"publisher_ids"`
+	ComposerIDs  []int `json:"composer_ids"`
+	ProducerIDs  []int `json:"producer_ids"`
 }
 
 // Console represents a console in the collection
 type Console struct {
-	ConsoleID     int
-	Name          string
-	Generation    *int
-	JPReleaseDate *time.Time
-	USReleaseDate *time.Time
-	EUReleaseDate *time.Time
-	Discontinued  *time.Time
-	PriceJPY      *int
-	PriceUSD      *int
-	Controllers   *int
-	CPU           *string
-	GPU           *string
-	Memory        *string
-	Audio         *string
-	UnitsSold     *int
-	TopGame       *string
-	Predecessor   *string
-	Successor     *string
-	Owned         bool
-	Condition     *int
-	Notes         *string
+	ConsoleID     int        `json:"console_id"`
+	Name          string     `json:"name"`
+	Generation    *int       `json:"generation"`
+	JPReleaseDate *time.Time `json:"jp_release_date"`
+	USReleaseDate *time.Time `json:"us_release_date"`
+	EUReleaseDate *time.Time `json:"eu_release_date"`
+	Discontinued  *time.Time `json:"discontinued"`
+	PriceJPY      *int       `json:"price_jpy"`
+	PriceUSD      *int       `json:"price_usd"`
+	Controllers   *int       `json:"controllers"`
+	CPU           *string    `json:"cpu"`
+	GPU           *string    `json:"gpu"`
+	Memory        *string    `json:"memory"`
+	Audio         *string    `json:"audio"`
+	UnitsSold     *int       `json:"units_sold"`
+	TopGame       *string    `json:"top_game"`
+	Predecessor   *string    `json:"predecessor"`
+	Successor     *string    `json:"successor"`
+	Owned         bool       `json:"owned"`
+	Condition     *int       `json:"condition"`
+	PurchaseDate  *time.Time `json:"purchase_date"`
+	PurchasePrice *float64   `json:"purchase_price"`
+	Notes         *string    `json:"notes"`
+
+	// Wishlist (while Owned is false)
+	TargetPrice  *float64 `json:"target_price"`
+	WishPriority *int     `json:"wish_priority"` // 1 = high, 2 = medium, 3 = low
+	WishEdition  *string  `json:"wish_edition"`  // Preferred region or edition
 
 	// Foreign keys
-	TypeID         *int
-	ManufacturerID *int
+	TypeID         *int `json:"type_id"`
+	ManufacturerID *int `json:"manufacturer_id"`
 
 	// Related data (for display)
-	TypeName         string
-	ManufacturerName string
+	TypeName         string `json:"type_name"`
+	ManufacturerName string `json:"manufacturer_name"`
+	CoverThumb       string `json:"-"`       // Thumbnail of the first image, "" without image (list queries)
+	LentTo           string `json:"lent_to"` // Borrower of the current loan, "" when not lent (list queries)
 }
 
 // Accessory represents an accessory in the collection
 type Accessory struct {
-	AccessoryID   int
-	Name          string
-	Color         *string
-	Condition     *int
-	Owned         bool
-	PurchaseDate  *time.Time
-	PurchasePrice *float64
-	Quantity      int
-	Notes         *string
+	AccessoryID   int        `json:"accessory_id"`
+	Name          string     `json:"name"`
+	Color         *string    `json:"color"`
+	Condition     *int       `json:"condition"`
+	Owned         bool       `json:"owned"`
+	PurchaseDate  *time.Time `json:"purchase_date"`
+	PurchasePrice *float64   `json:"purchase_price"`
+	Quantity      int        `json:"quantity"`
+	Notes         *string    `json:"notes"`
+
+	// Wishlist (while Owned is false)
+	TargetPrice  *float64 `json:"target_price"`
+	WishPriority *int     `json:"wish_priority"` // 1 = high, 2 = medium, 3 = low
+	WishEdition  *string  `json:"wish_edition"`  // Preferred region or edition
 
 	// Foreign keys
-	TypeID         *int
-	ManufacturerID *int
+	TypeID         *int `json:"type_id"`
+	ManufacturerID *int `json:"manufacturer_id"`
 
 	// Related data (for display)
-	TypeName         string
-	ManufacturerName string
-	Consoles         []string // Multiple consoles via join table
+	TypeName         string   `json:"type_name"`
+	ManufacturerName string   `json:"manufacturer_name"`
+	Consoles         []string `json:"consoles"`    // Multiple consoles via join table
+	ConsoleIDs       []int    `json:"console_ids"` // IDs matching Consoles (used when saving)
+	CoverThumb       string   `json:"-"`           // Thumbnail of the first image, "" without image (list queries)
+	LentTo           string   `json:"lent_to"`     // Borrower of the current loan, "" when not lent (list queries)
 }
 
 // ========== Lookup Table Structs ==========
 
 type Genre struct {
-	GenreID int
-	Name    string
+	GenreID int    `json:"genre_id"`
+	Name    string `json:"name"`
 }
 
 type Developer struct {
-	DeveloperID int
-	Name        string
+	DeveloperID int    `json:"developer_id"`
+	Name        string `json:"name"`
 }
 
 type Composer struct {
-	ComposerID int
-	Name       string
+	ComposerID int    `json:"composer_id"`
+	Name       string `json:"name"`
 }
 
 type Publisher struct {
-	PublisherID int
-	Name        string
+	PublisherID int    `json:"publisher_id"`
+	Name        string `json:"name"`
 }
 
 type Producer struct {
-	ProducerID int
-	Name       string
+	ProducerID int    `json:"producer_id"`
+	Name       string `json:"name"`
 }
 
 type Manufacturer struct {
-	ManufacturerID int
-	Name           string
+	ManufacturerID int    `json:"manufacturer_id"`
+	Name           string `json:"name"`
 }
 
 type ConsoleType struct {
-	TypeID int
-	Name   string
+	TypeID int    `json:"type_id"`
+	Name   string `json:"name"`
 }
 
 type AccessoryType struct {
-	TypeID int
-	Name   string
+	TypeID int    `json:"type_id"`
+	Name   string `json:"name"`
 }
 
 type RatingSystem struct {
-	RatingID    int
-	Region      string
-	Code        string
-	Description *string
+	RatingID    int     `json:"rating_id"`
+	Region      string  `json:"region"`
+	Code        string  `json:"code"`
+	Description *string `json:"description"`
+}
+
+// ========== Dashboard Structs ==========
+
+// DashboardStats holds the collection figures shown on the "Accueil" tab
+// Counts only include owned items
+type DashboardStats struct {
+	GamesOwned       int
+	ConsolesOwned    int
+	AccessoriesOwned int // Sum of quantities
+	TotalSpent       float64
+
+	GamesPerConsole []LabelCount
+	GamesPerGenre   []LabelCount
+	Conditions      []ConditionCount // Games, consoles and accessories together
+
+	GamesWithBox    int
+	GamesCollector  int
+	RecentPurchases []RecentPurchase
+	OverdueLoans    []Loan // Current loans past their expected return, oldest due first
+
+	// Play tracking of the owned games
+	Backlog         int // Games not started yet
+	Playing         int
+	HoursPlayed     float64
+	CompletionRates []CompletionRate // Per platform, by number of games
+
+	// Valuation from the latest market values (see valuationGrade)
+	CollectionValue float64 // Owned items with a market value
+	ValuedItems     int
+	ValueGain       float64 // Value minus purchase price, over the valued items bought at a known price
+}
+
+// CompletionRate is the number of finished games (completed or 100 %) out of the owned games of a platform
+type CompletionRate struct {
+	Label    string // Platform, "" when not set
+	Finished int
+	Total    int
+}
+
+// LabelCount is one bar of a dashboard breakdown (empty label = not set)
+type LabelCount struct {
+	Label string
+	Count int
+}
+
+// ConditionCount is the number of items in a given condition (nil = not rated)
+type ConditionCount struct {
+	Condition *int
+	Count     int
+}
+
+// RecentPurchase is a game, console or accessory with a purchase date
+type RecentPurchase struct {
+	ItemType      string // "game", "console" or "accessory"
+	Name          string
+	Detail        string // Console of a game, manufacturer of a console, type of an accessory
+	PurchaseDate  time.Time
+	PurchasePrice *float64
+}
+
+// ========== Wishlist Structs ==========
+
+// Play statuses, stored in Game.PlayStatus
+const (
+	PlayBacklog   = "backlog"   // Not started yet
+	PlayPlaying   = "playing"   // In progress
+	PlayCompleted = "completed" // Finished
+	PlayFull      = "full"      // Finished at 100 %
+	PlayAbandoned = "abandoned"
+)
+
+// Wishlist priorities, stored in WishPriority
+const (
+	WishPriorityHigh   = 1
+	WishPriorityMedium = 2
+	WishPriorityLow    = 3
+)
+
+// Purchase is what is recorded when a wishlist item is bought
+type Purchase struct {
+	Date      *time.Time
+	Price     *float64
+	Condition *int
+}
+
+// ========== Import Structs ==========
+
+// LookupKind identifies a table that imported rows reference by name
+type LookupKind string
+
+const (
+	LookupConsole       LookupKind = "console"
+	LookupGenre         LookupKind = "genre"
+	LookupDeveloper     LookupKind = "developer"
+	LookupComposer      LookupKind = "composer"
+	LookupPublisher     LookupKind = "publisher"
+	LookupProducer      LookupKind = "producer"
+	LookupManufacturer  LookupKind = "manufacturer"
+	LookupConsoleType   LookupKind = "console_type"
+	LookupAccessoryType LookupKind = "accessory_type"
+)
+
+// LookupEntry is a name to create in a lookup table
+type LookupEntry struct {
+	Kind LookupKind
+	Name string
+}
+
+// ImportBatch holds the validated rows of an import, written in a single transaction
+// Lookup references are given by name (ConsoleName, GenreName, Developers, TypeName,
+// ManufacturerName, Consoles...) and NewEntries lists the names to create first.
+// Ratings are the exception: JPRatingID/USRatingID/EURatingID are set directly.
+type ImportBatch struct {
+	NewEntries  []LookupEntry
+	Games       []Game
+	Consoles    []Console
+	Accessories []Accessory
+}
+
+// ========== Image Structs ==========
+
+// Image kinds, in display order
+const (
+	ImageBoxFront  = "box_front"
+	ImageBoxBack   = "box_back"
+	ImageCartridge = "cartridge"
+	ImagePhoto     = "photo"
+)
+
+// ItemImage is a picture attached to a game, console or accessory
+type ItemImage struct {
+	ImageID   int
+	ItemType  string // "game", "console" or "accessory"
+	ItemID    int
+	Kind      string // One of the Image* constants
+	Hash      string // SHA-256 of the file content, names the cached file
+	Extension string // ".jpg", ".png"...
+
+	// Filled by the store from the media cache
+	Path      string
+	ThumbPath string
+}
+
+// ========== Saved Searches ==========
+
+// SavedSearch is a named search of the Jeux, Consoles or Accessoires tab
+type SavedSearch struct {
+	SearchID   int
+	EntityType string // "game", "console" or "accessory"
+	Name       string
+	Query      string // Search bar text, see query.go
+}
+
+// ========== Market Values ==========
+
+// Market value grades, from the cheapest to the most expensive
+const (
+	GradeLoose = "loose" // The item alone
+	GradeCIB   = "cib"   // Complete in box
+	GradeNew   = "new"   // Sealed
+)
+
+// MarketValue is what an item was worth on a given day
+type MarketValue struct {
+	ValueID    int
+	ItemType   string // "game", "console" or "accessory"
+	ItemID     int
+	Grade      string // One of the Grade* constants
+	Value      float64
+	RecordedOn time.Time
+	Source     *string // Price guide or shop the value comes from
+}
+
+// ========== Game Copies ==========
+
+// GameCopy is one physical copy of a game
+type GameCopy struct {
+	CopyID        int        `json:"copy_id"`
+	GameID        int        `json:"game_id"`
+	Region        *string    `json:"region"`  // PAL, NTSC-U, NTSC-J...
+	Edition       *string    `json:"edition"` // Standard, Platinum, Player's Choice...
+	Condition     *int       `json:"condition"`
+	BoxOwned      *bool      `json:"box_owned"`
+	ManualOwned   *bool      `json:"manual_owned"`
+	PurchaseDate  *time.Time `json:"purchase_date"`
+	PurchasePrice *float64   `json:"purchase_price"`
+	Notes         *string    `json:"notes"`
+
+	// Completeness checklist; BoxOwned and ManualOwned follow the box and manual components
+	Components   []int    `json:"components"`   // IDs of the components present (nil keeps BoxOwned/ManualOwned when saving)
+	Completeness string   `json:"completeness"` // Grade* constant computed from the components
+	Missing      []string `json:"missing"`      // Names of the components absent (none for a sealed copy)
+}
+
+// ========== Completeness ==========
+
+// Roles of the completeness components
+const (
+	ComponentMedia  = "media"  // Cartridge or disc
+	ComponentBox    = "box"    // Sets GameCopy.BoxOwned
+	ComponentManual = "manual" // Sets GameCopy.ManualOwned
+	ComponentSeal   = "seal"   // Makes the copy new (sealed)
+	ComponentOther  = "other"
+)
+
+// CompletenessComponent is a part of a game release that a copy may still have
+type CompletenessComponent struct {
+	ComponentID     int
+	ConsoleTypeID   *int   // nil applies to every game
+	ConsoleTypeName string // Populated via JOIN
+	Name            string
+	Role            string // One of the Component* constants
+	RequiredForCIB  bool
+	Position        int
+}
+
+// ========== Loans ==========
+
+// Loan is a game, console or accessory lent to someone
+type Loan struct {
+	LoanID     int
+	ItemType   string // "game", "console" or "accessory"
+	ItemID     int
+	ItemName   string // Title or name of the item (GetOpenLoans)
+	Borrower   string
+	LentOn     time.Time
+	DueOn      *time.Time // Expected return
+	ReturnedOn *time.Time // nil while the item is lent
+	Notes      *string
 }
@@ -0,0 +1,245 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// ========== OPENAPI DOCUMENT ==========
+// The description of the REST API served at /api/openapi.json. Schemas are generated from the
+// model structs and their json tags, so a field added to a model shows up without editing
+// this file; only the routes are listed here, matching newAPIHandler.
+
+// openAPIEntities are the entities with CRUD endpoints, as registered by newAPIHandler
+var openAPIEntities = []struct {
+	Path   string
+	Schema string
+	Model  any
+	Plural string // For the summaries
+}{
+	{"games", "Game", Game{}, "jeux"},
+	{"consoles", "Console", Console{}, "consoles"},
+	{"accessories", "Accessory", Accessory{}, "accessoires"},
+}
+
+// openAPIDocument builds the OpenAPI 3.0 document of the API
+func openAPIDocument(store CollectionStore) map[string]any {
+	schemas := map[string]any{
+		"Error": map[string]any{
+			"type":       "object",
+			"properties": map[string]any{"error": map[string]any{"type": "string"}},
+		},
+		"LookupEntry": openAPISchema(reflect.TypeOf(apiLookupEntry{})),
+	}
+	paths := map[string]any{}
+
+	for _, e := range openAPIEntities {
+		schemas[e.Schema] = openAPISchema(reflect.TypeOf(e.Model))
+		ref := openAPIRef(e.Schema)
+		idParam := map[string]any{"name": "id", "in": "path", "required": true, "schema": map[string]any{"type": "integer"}}
+
+		paths["/api/"+e.Path] = map[string]any{
+			"get": map[string]any{
+				"summary":     fmt.Sprintf("Liste les %s, filtrés et paginés", e.Plural),
+				"description": "Les éléments listés ont les noms liés mais pas les identifiants (console_id, developer_ids...), que seule la lecture d'un élément renvoie.",
+				"parameters": []any{
+					openAPIQueryParam("q", "string", "Recherche, même syntaxe que la barre de recherche (ex. condition>=4 box:yes)"),
+					openAPIQueryParam("owned", "boolean", "Possédés (true) ou souhaités (false) seulement"),
+					openAPIQueryParam("limit", "integer", fmt.Sprintf("Taille de la page, %d par défaut, %d au plus", apiDefaultLimit, apiMaxLimit)),
+					openAPIQueryParam("offset", "integer", "Nombre d'éléments à sauter"),
+				},
+				"responses": map[string]any{
+					"200": openAPIResponse("Page de résultats", map[string]any{
+						"type": "object",
+						"properties": map[string]any{
+							"items":  map[string]any{"type": "array", "items": ref},
+							"total":  map[string]any{"type": "integer"},
+							"limit":  map[string]any{"type": "integer"},
+							"offset": map[string]any{"type": "integer"},
+						},
+					}),
+					"400": openAPIErrorResponse("Recherche ou pagination invalide"),
+				},
+			},
+			"post": map[string]any{
+				"summary":     "Ajoute un élément (l'identifiant et les noms liés sont ignorés)",
+				"requestBody": openAPIBody(ref),
+				"responses": map[string]any{
+					"201": openAPIResponse("Élément enregistré", ref),
+					"400": openAPIErrorResponse("Élément invalide"),
+				},
+			},
+		}
+
+		paths["/api/"+e.Path+"/{id}"] = map[string]any{
+			"parameters": []any{idParam},
+			"get": map[string]any{
+				"summary": "Lit un élément avec tous ses détails",
+				"responses": map[string]any{
+					"200": openAPIResponse("Élément", ref),
+					"404": openAPIErrorResponse("Élément introuvable"),
+				},
+			},
+			"put": map[string]any{
+				"summary":     "Remplace un élément (les champs absents sont vidés)",
+				"requestBody": openAPIBody(ref),
+				"responses": map[string]any{
+					"200": openAPIResponse("Élément enregistré", ref),
+					"400": openAPIErrorResponse("Élément invalide"),
+					"404": openAPIErrorResponse("Élément introuvable"),
+				},
+			},
+			"delete": map[string]any{
+				"summary": "Supprime un élément",
+				"responses": map[string]any{
+					"204": map[string]any{"description": "Élément supprimé"},
+					"404": openAPIErrorResponse("Élément introuvable"),
+				},
+			},
+		}
+	}
+
+	for _, lookup := range apiLookups(store) {
+		t, body := reflect.TypeOf(lookup.Schema), reflect.TypeOf(lookup.Body)
+		schemas[t.Name()] = openAPISchema(t)
+		bodyRef := openAPIRef("LookupEntry")
+		if body != reflect.TypeOf(apiLookupEntry{}) {
+			bodyRef = openAPIRef(body.Name())
+		}
+		idParam := map[string]any{"name": "id", "in": "path", "required": true, "schema": map[string]any{"type": "integer"}}
+
+		paths["/api/"+lookup.Path] = map[string]any{
+			"get": map[string]any{
+				"summary": "Liste les entrées de la table " + lookup.Path + ", filtrées et paginées",
+				"parameters": []any{
+					openAPIQueryParam("q", "string", "Texte contenu dans l'entrée, sans tenir compte des accents ni de la casse"),
+					openAPIQueryParam("limit", "integer", fmt.Sprintf("Taille de la page, %d par défaut, %d au plus", apiDefaultLimit, apiMaxLimit)),
+					openAPIQueryParam("offset", "integer", "Nombre d'entrées à sauter"),
+				},
+				"responses": map[string]any{
+					"200": openAPIResponse("Page d'entrées", map[string]any{
+						"type": "object",
+						"properties": map[string]any{
+							"items":  map[string]any{"type": "array", "items": openAPIRef(t.Name())},
+							"total":  map[string]any{"type": "integer"},
+							"limit":  map[string]any{"type": "integer"},
+							"offset": map[string]any{"type": "integer"},
+						},
+					}),
+					"400": openAPIErrorResponse("Pagination invalide"),
+				},
+			},
+			"post": map[string]any{
+				"summary":     "Ajoute une entrée à la table " + lookup.Path + " (l'identifiant est ignoré)",
+				"requestBody": openAPIBody(bodyRef),
+				"responses": map[string]any{
+					"201": openAPIResponse("Entrée créée", bodyRef),
+					"400": openAPIErrorResponse("Entrée invalide"),
+					"409": openAPIErrorResponse("L'entrée existe déjà"),
+				},
+			},
+		}
+
+		paths["/api/"+lookup.Path+"/{id}"] = map[string]any{
+			"parameters": []any{idParam},
+			"put": map[string]any{
+				"summary":     "Modifie une entrée (l'identifiant du corps est ignoré)",
+				"requestBody": openAPIBody(bodyRef),
+				"responses": map[string]any{
+					"200": openAPIResponse("Entrée enregistrée", bodyRef),
+					"400": openAPIErrorResponse("Entrée invalide"),
+					"404": openAPIErrorResponse("Entrée introuvable"),
+					"409": openAPIErrorResponse("Une autre entrée porte déjà ce nom"),
+				},
+			},
+			"delete": map[string]any{
+				"summary": "Supprime une entrée qu'aucun élément de la collection n'utilise",
+				"responses": map[string]any{
+					"204": map[string]any{"description": "Entrée supprimée"},
+					"404": openAPIErrorResponse("Entrée introuvable"),
+					"409": openAPIErrorResponse("Entrée utilisée par la collection"),
+				},
+			},
+		}
+	}
+
+	return map[string]any{
+		"openapi": "3.0.3",
+		"info": map[string]any{
+			"title":       "VGC - Video Game Collector",
+			"version":     "1.0",
+			"description": "Collection de jeux, consoles et accessoires. Les dates sont au format RFC 3339; les champs des noms liés (console_name, developers...) sont en lecture seule, seuls les identifiants sont enregistrés.",
+		},
+		"paths": paths,
+		"components": map[string]any{
+			"schemas": schemas,
+			"securitySchemes": map[string]any{
+				"bearer": map[string]any{"type": "http", "scheme": "bearer", "description": "Requis quand le serveur est lancé avec --token"},
+			},
+		},
+		"security": []any{map[string]any{"bearer": []any{}}, map[string]any{}},
+	}
+}
+
+// openAPISchema describes a Go type from its json tags
+func openAPISchema(t reflect.Type) map[string]any {
+	switch t.Kind() {
+	case reflect.Pointer:
+		schema := openAPISchema(t.Elem())
+		schema["nullable"] = true
+		return schema
+	case reflect.Slice:
+		return map[string]any{"type": "array", "items": openAPISchema(t.Elem())}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int:
+		return map[string]any{"type": "integer"}
+	case reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Struct:
+		if t == reflect.TypeOf(time.Time{}) {
+			return map[string]any{"type": "string", "format": "date-time"}
+		}
+		properties := map[string]any{}
+		for i := 0; i < t.NumField(); i++ {
+			name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+			if name == "" || name == "-" {
+				continue
+			}
+			properties[name] = openAPISchema(t.Field(i).Type)
+		}
+		return map[string]any{"type": "object", "properties": properties}
+	default:
+		return map[string]any{}
+	}
+}
+
+func openAPIRef(schema string) map[string]any {
+	return map[string]any{"$ref": "#/components/schemas/" + schema}
+}
+
+func openAPIQueryParam(name, kind, description string) map[string]any {
+	return map[string]any{"name": name, "in": "query", "description": description, "schema": map[string]any{"type": kind}}
+}
+
+func openAPIBody(schema map[string]any) map[string]any {
+	return map[string]any{
+		"required": true,
+		"content":  map[string]any{"application/json": map[string]any{"schema": schema}},
+	}
+}
+
+func openAPIResponse(description string, schema map[string]any) map[string]any {
+	return map[string]any{
+		"description": description,
+		"content":     map[string]any{"application/json": map[string]any{"schema": schema}},
+	}
+}
+
+func openAPIErrorResponse(description string) map[string]any {
+	return openAPIResponse(description, openAPIRef("Error"))
+}
@@ -0,0 +1,251 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/widget"
+)
+
+// ========== PLAY TRACKING ==========
+// Besides owning a game, the collection records where its player stands: in the backlog,
+// being played, completed (or at 100 %) or abandoned, with dates, hours played, a rating
+// out of 10 and a short review. The dashboard sums it up per platform.
+
+// playStatuses lists the statuses in the order of the forms and filters
+var playStatuses = []string{PlayBacklog, PlayPlaying, PlayCompleted, PlayFull, PlayAbandoned}
+
+// playStatusLabels names the statuses in the UI
+var playStatusLabels = map[string]string{
+	PlayBacklog:   "À faire",
+	PlayPlaying:   "En cours",
+	PlayCompleted: "Terminé",
+	PlayFull:      "Terminé à 100 %",
+	PlayAbandoned: "Abandonné",
+}
+
+// playStatusText returns the label of a status, "" when not set
+func playStatusText(status *string) string {
+	if status == nil {
+		return ""
+	}
+	return playStatusLabels[*status]
+}
+
+// isFinished tells whether a status counts as finished for the completion rate
+func isFinished(status *string) bool {
+	return status != nil && (*status == PlayCompleted || *status == PlayFull)
+}
+
+// ratingText shows a personal rating, "" when not set
+func ratingText(rating *int) string {
+	if rating == nil {
+		return ""
+	}
+	return fmt.Sprintf("%d/10", *rating)
+}
+
+// playStatusLabelSQL names the status held by column in SQL, for the play: search field
+func playStatusLabelSQL(column string) string {
+	cases := make([]string, len(playStatuses))
+	for i, status := range playStatuses {
+		cases[i] = fmt.Sprintf("WHEN '%s' THEN '%s'", status, playStatusLabels[status])
+	}
+	return fmt.Sprintf("(CASE %s %s END)", column, strings.Join(cases, " "))
+}
+
+// ========== Form fields ==========
+
+// playFields are the play tracking widgets of the game form
+type playFields struct {
+	statusSelect  *widget.Select
+	startedEntry  *widget.Entry
+	finishedEntry *widget.Entry
+	hoursEntry    *widget.Entry
+	ratingSelect  *widget.Select
+	reviewEntry   *widget.Entry
+	section       *fyne.Container
+}
+
+// notSetLabel is the first choice of the selects, to clear them
+const notSetLabel = "Non renseigné"
+
+// ratingLabels are the ratings offered in the form, 1/10 first
+var ratingLabels = func() []string {
+	labels := make([]string, 10)
+	for i := range labels {
+		labels[i] = ratingText(intPtr(i + 1))
+	}
+	return labels
+}()
+
+// newPlayFields creates the play tracking widgets filled with the values of a game (nil for a new one)
+// Starting or finishing a game fills the matching date with today when no date is set.
+func newPlayFields(game *Game) *playFields {
+	f := &playFields{}
+	if game == nil {
+		game = &Game{}
+	}
+
+	f.startedEntry = widget.NewEntry()
+	f.startedEntry.SetPlaceHolder("AAAA-MM-JJ")
+	if game.PlayStartedOn != nil {
+		f.startedEntry.SetText(game.PlayStartedOn.Format("2006-01-02"))
+	}
+
+	f.finishedEntry = widget.NewEntry()
+	f.finishedEntry.SetPlaceHolder("AAAA-MM-JJ")
+	if game.PlayFinishedOn != nil {
+		f.finishedEntry.SetText(game.PlayFinishedOn.Format("2006-01-02"))
+	}
+
+	options := []string{notSetLabel}
+	for _, status := range playStatuses {
+		options = append(options, playStatusLabels[status])
+	}
+	f.statusSelect = widget.NewSelect(options, nil)
+	f.statusSelect.SetSelected(notSetLabel)
+	if label := playStatusText(game.PlayStatus); label != "" {
+		f.statusSelect.SetSelected(label)
+	}
+	f.statusSelect.OnChanged = func(label string) {
+		today := time.Now().Format("2006-01-02")
+		status := f.status()
+		if status != nil && *status != PlayBacklog && f.startedEntry.Text == "" && f.finishedEntry.Text == "" {
+			f.startedEntry.SetText(today)
+		}
+		if isFinished(status) && f.finishedEntry.Text == "" {
+			f.finishedEntry.SetText(today)
+		}
+	}
+
+	f.hoursEntry = widget.NewEntry()
+	f.hoursEntry.SetPlaceHolder("Heures de jeu")
+	if game.HoursPlayed != nil {
+		f.hoursEntry.SetText(fmt.Sprintf("%.1f", *game.HoursPlayed))
+	}
+
+	f.ratingSelect = widget.NewSelect(append([]string{notSetLabel}, ratingLabels...), nil)
+	f.ratingSelect.SetSelected(notSetLabel)
+	if game.PersonalRating != nil {
+		f.ratingSelect.SetSelected(ratingText(game.PersonalRating))
+	}
+
+	f.reviewEntry = widget.NewMultiLineEntry()
+	f.reviewEntry.SetPlaceHolder("Avis en quelques lignes")
+	f.reviewEntry.Wrapping = fyne.TextWrapWord
+	if game.Review != nil {
+		f.reviewEntry.SetText(*game.Review)
+	}
+
+	f.section = container.NewVBox(
+		widget.NewSeparator(),
+		widget.NewLabel("Suivi de jeu"),
+		widget.NewLabel("Progression:"),
+		f.statusSelect,
+		widget.NewLabel("Commencé le:"),
+		f.startedEntry,
+		widget.NewLabel("Terminé le:"),
+		f.finishedEntry,
+		widget.NewLabel("Temps de jeu (heures):"),
+		f.hoursEntry,
+		widget.NewLabel("Note personnelle:"),
+		f.ratingSelect,
+		widget.NewLabel("Avis:"),
+		f.reviewEntry,
+	)
+	return f
+}
+
+// status returns the selected status, nil when not set
+func (f *playFields) status() *string {
+	for _, status := range playStatuses {
+		if playStatusLabels[status] == f.statusSelect.Selected {
+			return &status
+		}
+	}
+	return nil
+}
+
+// apply copies the play tracking entered in the form into a game (checked by validateGame)
+func (f *playFields) apply(game *Game) error {
+	var err error
+	if game.PlayStartedOn, err = parseDateEntry(f.startedEntry.Text); err != nil {
+		return err
+	}
+	if game.PlayFinishedOn, err = parseDateEntry(f.finishedEntry.Text); err != nil {
+		return err
+	}
+	game.HoursPlayed = nil
+	if text := strings.TrimSpace(f.hoursEntry.Text); text != "" {
+		hours, err := strconv.ParseFloat(strings.Replace(text, ",", ".", 1), 64)
+		if err != nil || math.IsNaN(hours) || math.IsInf(hours, 0) {
+			return fmt.Errorf("temps de jeu invalide '%s' (nombre d'heures attendu, ex. 12,5)", text)
+		}
+		game.HoursPlayed = &hours
+	}
+
+	game.PlayStatus = f.status()
+	game.PersonalRating = nil
+	if index := slices.Index(ratingLabels, f.ratingSelect.Selected); index >= 0 {
+		game.PersonalRating = intPtr(index + 1)
+	}
+	game.Review = optionalText(strings.TrimSpace(f.reviewEntry.Text))
+	return nil
+}
+
+// playDetails shows the play tracking of a game in its detail dialog (nothing when not tracked)
+func playDetails(game *Game) []fyne.CanvasObject {
+	var lines []fyne.CanvasObject
+	if label := playStatusText(game.PlayStatus); label != "" {
+		lines = append(lines, widget.NewLabel(fmt.Sprintf("Progression: %s", label)))
+	}
+	if game.PlayStartedOn != nil {
+		lines = append(lines, widget.NewLabel(fmt.Sprintf("Commencé le: %s", game.PlayStartedOn.Format("2006-01-02"))))
+	}
+	if game.PlayFinishedOn != nil {
+		lines = append(lines, widget.NewLabel(fmt.Sprintf("Terminé le: %s", game.PlayFinishedOn.Format("2006-01-02"))))
+	}
+	if game.HoursPlayed != nil {
+		lines = append(lines, widget.NewLabel(fmt.Sprintf("Temps de jeu: %.1f h", *game.HoursPlayed)))
+	}
+	if game.PersonalRating != nil {
+		lines = append(lines, widget.NewLabel(fmt.Sprintf("Note: %s", ratingText(game.PersonalRating))))
+	}
+	if game.Review != nil {
+		review := widget.NewLabelWithStyle(*game.Review, fyne.TextAlignLeading, fyne.TextStyle{Italic: true})
+		review.Wrapping = fyne.TextWrapWord
+		lines = append(lines, review)
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+	return append([]fyne.CanvasObject{widget.NewSeparator(), widget.NewLabel("Suivi de jeu")}, lines...)
+}
+
+// ========== Dashboard ==========
+
+// createCompletionChart shows the share of finished games of each platform
+func createCompletionChart(rates []CompletionRate) fyne.CanvasObject {
+	if len(rates) == 0 {
+		return widget.NewLabelWithStyle("Aucune donnée", fyne.TextAlignLeading, fyne.TextStyle{Italic: true})
+	}
+
+	chart := container.New(layout.NewFormLayout())
+	for _, r := range rates {
+		label := r.Label
+		if label == "" {
+			label = "Non renseigné"
+		}
+		chart.Add(widget.NewLabel(label))
+		chart.Add(createShareBar(r.Finished, r.Total))
+	}
+	return chart
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ========== PostgreSQL Backend ==========
+
+//go:embed migrations/postgres/*.sql
+var postgresMigrations embed.FS
+
+var postgresDialect = dialect{
+	name:       "postgres",
+	migrations: postgresMigrations,
+	dir:        "migrations/postgres",
+	migrationsTable: `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version    INTEGER PRIMARY KEY,
+			name       TEXT NOT NULL,
+			applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+		)
+	`,
+	yearOf: "EXTRACT(YEAR FROM %s)",
+	lower:  "LOWER(%s)", // Unicode-aware with the UTF-8 locales of a default installation
+
+	tableColumns: `
+		SELECT column_name, data_type FROM information_schema.columns
+		WHERE table_schema = current_schema() AND table_name = $1
+		ORDER BY ordinal_position
+	`,
+	dropTable: "DROP TABLE IF EXISTS %s CASCADE",
+	snapshot:  "SET TRANSACTION ISOLATION LEVEL REPEATABLE READ READ ONLY",
+	// setval does nothing for tables without a SERIAL column (the sequence is NULL)
+	resetSequence: "SELECT setval(pg_get_serial_sequence('%[1]s', '%[2]s'), MAX(%[2]s)) FROM %[1]s",
+}
+
+// Connection pool tuning: the database usually lives on a NAS that can restart at any time
+const (
+	pgMaxConns          = 8
+	pgConnectTimeout    = 5 * time.Second
+	pgHealthCheckPeriod = 30 * time.Second
+)
+
+// newPostgresStore connects to the database configured in .env and applies pending migrations
+func newPostgresStore() (*sqlStore, error) {
+	pool, err := dbconnect()
+	if err != nil {
+		return nil, err
+	}
+	db := &pgDatabase{pool: pool}
+
+	// Create or upgrade the schema before any tab queries it
+	if err := migrate(db, postgresDialect); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &sqlStore{db: db, dialect: postgresDialect, media: newMediaCache()}, nil
+}
+
+// dbconnect opens a connection pool and checks that the server answers
+// The pool replaces broken connections on its own: idle connections are health
+// checked in the background and pinged before reuse, so queries work again as soon
+// as the server is back without restarting the app.
+func dbconnect() (*pgxpool.Pool, error) {
+	// Make connection string from .env with trimming
+	connStr := fmt.Sprintf(
+		"postgresql://%s:%s@%s:%s/%s",
+		strings.TrimSpace(os.Getenv("DB_USER")),
+		strings.TrimSpace(os.Getenv("DB_PASSWORD")),
+		strings.TrimSpace(os.Getenv("DB_HOST")),
+		strings.TrimSpace(os.Getenv("DB_PORT")),
+		strings.TrimSpace(os.Getenv("DB_NAME")),
+	)
+
+	config, err := pgxpool.ParseConfig(connStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid database configuration: %w", err)
+	}
+	config.MaxConns = pgMaxConns
+	config.HealthCheckPeriod = pgHealthCheckPeriod
+	config.ConnConfig.ConnectTimeout = pgConnectTimeout
+
+	// Connect to database (the pool connects lazily, so ping to fail early)
+	pool, err := pgxpool.NewWithConfig(context.Background(), config)
+	if err != nil {
+		return nil, fmt.Errorf("unable to connect to database: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), pgConnectTimeout)
+	defer cancel()
+	if err := pool.Ping(ctx); err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("unable to connect to database: %w", err)
+	}
+
+	return pool, nil
+}
+
+// ========== pgx Adapter ==========
+
+// pgDatabase adapts a pgx connection pool to the database interface
+type pgDatabase struct {
+	pool *pgxpool.Pool
+}
+
+func (d *pgDatabase) Exec(ctx context.Context, query string, args ...any) (int64, error) {
+	tag, err := d.pool.Exec(ctx, query, args...)
+	return tag.RowsAffected(), err
+}
+
+func (d *pgDatabase) Query(ctx context.Context, query string, args ...any) (rows, error) {
+	return d.pool.Query(ctx, query, args...)
+}
+
+func (d *pgDatabase) QueryRow(ctx context.Context, query string, args ...any) row {
+	return pgRow{d.pool.QueryRow(ctx, query, args...)}
+}
+
+func (d *pgDatabase) Begin(ctx context.Context) (transaction, error) {
+	tx, err := d.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &pgTransaction{tx: tx}, nil
+}
+
+func (d *pgDatabase) Ping(ctx context.Context) error {
+	return d.pool.Ping(ctx)
+}
+
+func (d *pgDatabase) Close() {
+	d.pool.Close()
+}
+
+// pgTransaction adapts a pgx transaction to the transaction interface
+type pgTransaction struct {
+	tx pgx.Tx
+}
+
+func (t *pgTransaction) Exec(ctx context.Context, query string, args ...any) (int64, error) {
+	tag, err := t.tx.Exec(ctx, query, args...)
+	return tag.RowsAffected(), err
+}
+
+func (t *pgTransaction) Query(ctx context.Context, query string, args ...any) (rows, error) {
+	return t.tx.Query(ctx, query, args...)
+}
+
+func (t *pgTransaction) QueryRow(ctx context.Context, query string, args ...any) row {
+	return pgRow{t.tx.QueryRow(ctx, query, args...)}
+}
+
+func (t *pgTransaction) Commit(ctx context.Context) error {
+	return t.tx.Commit(ctx)
+}
+
+func (t *pgTransaction) Rollback(ctx context.Context) error {
+	return t.tx.Rollback(ctx)
+}
+
+// pgRow translates pgx.ErrNoRows into the driver-independent errNoRows
+type pgRow struct {
+	row pgx.Row
+}
+
+func (r pgRow) Scan(dest ...any) error {
+	err := r.row.Scan(dest...)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return errNoRows
+	}
+	return err
+}
@@ -0,0 +1,628 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// ========== SEARCH QUERY LANGUAGE ==========
+// The search bars accept free text mixed with field filters, all of them required:
+//
+//	console:"Super Nintendo" genre:RPG condition>=4 owned:false box:yes price<20
+//	year:1995..1999 dev:Squaresoft -genre:sport mario
+//
+//   field:value    text fields contain the value, other fields equal it
+//   field=value    exact value (case-insensitive for text)
+//   field!=value   anything but the value
+//   field>value    also >=, < and <= for numbers and years
+//   field:a..b     range, bounds included; "..b" and "a.." leave one side open
+//   -term          negates a filter or a free text word
+//   "two words"    quotes keep spaces in a value or in free text
+//
+// Free text matches the main text columns of the tab (title, platform, genre for games...).
+// A query compiles into a searchFilter that runs in memory (search bars) or as a SQL
+// WHERE clause (SearchGames, SearchConsoles and SearchAccessories).
+
+// queryError reports a malformed query
+type queryError struct {
+	Offset  int // Position of the faulty term in the query, in characters
+	Message string
+}
+
+func (e *queryError) Error() string {
+	return e.Message
+}
+
+// queryTerm is one element of a parsed query
+type queryTerm struct {
+	Offset int
+	Negate bool
+	Field  string // "" for free text
+	Op     string // ":", "=", "!=", ">", ">=", "<" or "<="
+	Value  string
+	Quoted bool
+}
+
+// queryOperators is ordered so that two-character operators are tried first
+var queryOperators = []string{">=", "<=", "!=", ":", "=", ">", "<"}
+
+// parseSearchQuery splits a query into terms
+func parseSearchQuery(text string) ([]queryTerm, error) {
+	runes := []rune(text)
+	var terms []queryTerm
+
+	for pos := 0; pos < len(runes); {
+		if unicode.IsSpace(runes[pos]) {
+			pos++
+			continue
+		}
+
+		term := queryTerm{Offset: pos}
+		if runes[pos] == '-' && pos+1 < len(runes) && !unicode.IsSpace(runes[pos+1]) {
+			term.Negate = true
+			pos++
+		}
+
+		// Field name followed by an operator
+		nameEnd := pos
+		for nameEnd < len(runes) && (unicode.IsLetter(runes[nameEnd]) || unicode.IsDigit(runes[nameEnd]) || runes[nameEnd] == '_') {
+			nameEnd++
+		}
+		if nameEnd > pos {
+			for _, op := range queryOperators {
+				if strings.HasPrefix(string(runes[nameEnd:]), op) {
+					term.Field = string(runes[pos:nameEnd])
+					term.Op = op
+					pos = nameEnd + len([]rune(op))
+					break
+				}
+			}
+		}
+
+		// Value, or free text when no field was found
+		if pos < len(runes) && runes[pos] == '"' {
+			end := slices.Index(runes[pos+1:], '"')
+			if end < 0 {
+				return nil, &queryError{Offset: pos, Message: "guillemet fermant manquant"}
+			}
+			term.Value = string(runes[pos+1 : pos+1+end])
+			term.Quoted = true
+			pos += end + 2
+		} else {
+			start := pos
+			for pos < len(runes) && !unicode.IsSpace(runes[pos]) {
+				pos++
+			}
+			term.Value = string(runes[start:pos])
+		}
+
+		terms = append(terms, term)
+	}
+	return terms, nil
+}
+
+// ========== Fields ==========
+
+// queryKind tells how the values of a field are compared
+type queryKind int
+
+const (
+	queryText   queryKind = iota // Contains with ":", equals with "="
+	queryNumber                  // Comparisons and ranges
+	queryYear                    // Number compared with the year of dates
+	queryBool                    // yes/no
+)
+
+// queryField is a field usable in a query
+// In SQL, the term matches when any of Columns does; Exists wraps the condition in a
+// subquery for many-to-many fields (%s is replaced by the condition on Columns).
+type queryField[T any] struct {
+	Name    string
+	Aliases []string
+	Kind    queryKind
+	Text    func(item *T) []string
+	Number  func(item *T) []float64 // Empty when the value is not set
+	Bool    func(item *T) bool
+	Columns []string
+	Exists  string
+}
+
+// querySchema lists the fields of an entity and those searched by free text
+type querySchema[T any] struct {
+	Fields   []queryField[T]
+	FreeText []string // Names of the text fields matched by free text
+}
+
+// field finds a field by name or alias, ignoring case and accents
+func (s *querySchema[T]) field(name string) *queryField[T] {
+	name = normalizeHeader(name)
+	for i := range s.Fields {
+		if s.Fields[i].Name == name || slices.Contains(s.Fields[i].Aliases, name) {
+			return &s.Fields[i]
+		}
+	}
+	return nil
+}
+
+// fieldNames lists the field names for error messages
+func (s *querySchema[T]) fieldNames() string {
+	names := make([]string, len(s.Fields))
+	for i, f := range s.Fields {
+		names[i] = f.Name
+	}
+	return strings.Join(names, ", ")
+}
+
+func textValue(value string) []string {
+	return []string{value}
+}
+
+func optionalTextValue(value *string) []string {
+	if value == nil {
+		return nil
+	}
+	return []string{*value}
+}
+
+func intValue(value *int) []float64 {
+	if value == nil {
+		return nil
+	}
+	return []float64{float64(*value)}
+}
+
+func floatValue(value *float64) []float64 {
+	if value == nil {
+		return nil
+	}
+	return []float64{*value}
+}
+
+func yearValues(dates ...*time.Time) []float64 {
+	var years []float64
+	for _, d := range dates {
+		if d != nil {
+			years = append(years, float64(d.Year()))
+		}
+	}
+	return years
+}
+
+func boolValue(value *bool) bool {
+	return value != nil && *value
+}
+
+var gameQuerySchema = querySchema[Game]{
+	Fields: []queryField[Game]{
+		{Name: "title", Aliases: []string{"titre", "t"}, Kind: queryText,
+			Text: func(g *Game) []string { return textValue(g.Title) }, Columns: []string{"g.title"}},
+		{Name: "console", Aliases: []string{"platform", "plateforme", "c"}, Kind: queryText,
+			Text: func(g *Game) []string { return textValue(g.ConsoleName) }, Columns: []string{"COALESCE(c.name, '')"}},
+		{Name: "genre", Aliases: []string{"g"}, Kind: queryText,
+			Text: func(g *Game) []string { return textValue(g.GenreName) }, Columns: []string{"COALESCE(ge.name, '')"}},
+		{Name: "dev", Aliases: []string{"developer", "developpeur"}, Kind: queryText,
+			Text: func(g *Game) []string { return g.Developers }, Columns: []string{"qd.name"},
+			Exists: "SELECT 1 FROM game_developers qgd JOIN developers qd ON qgd.developer_id = qd.developer_id WHERE qgd.game_id = g.game_id AND %s"},
+		{Name: "pub", Aliases: []string{"publisher", "editeur"}, Kind: queryText,
+			Text: func(g *Game) []string { return g.Publishers }, Columns: []string{"qp.name"},
+			Exists: "SELECT 1 FROM game_publishers qgp JOIN publishers qp ON qgp.publisher_id = qp.publisher_id WHERE qgp.game_id = g.game_id AND %s"},
+		{Name: "rating", Aliases: []string{"classification"}, Kind: queryText,
+			Text:    func(g *Game) []string { return []string{g.JPRating, g.USRating, g.EURating} },
+			Columns: []string{"COALESCE(rj.code, '')", "COALESCE(ru.code, '')", "COALESCE(re.code, '')"}},
+		{Name: "condition", Aliases: []string{"cond", "etat"}, Kind: queryNumber,
+			Number: func(g *Game) []float64 { return intValue(g.Condition) }, Columns: []string{"g.condition"}},
+		{Name: "owned", Aliases: []string{"possede"}, Kind: queryBool,
+			Bool: func(g *Game) bool { return g.Owned }, Columns: []string{"g.owned"}},
+		{Name: "lent", Aliases: []string{"prete"}, Kind: queryBool,
+			Bool:    func(g *Game) bool { return g.LentTo != "" },
+			Columns: []string{"EXISTS (SELECT 1 FROM loans ql WHERE ql.item_type = 'game' AND ql.item_id = g.game_id AND ql.returned_on IS NULL)"}},
+		{Name: "borrower", Aliases: []string{"emprunteur"}, Kind: queryText,
+			Text: func(g *Game) []string { return textValue(g.LentTo) }, Columns: []string{"qlb.borrower"},
+			Exists: "SELECT 1 FROM loans qlb WHERE qlb.item_type = 'game' AND qlb.item_id = g.game_id AND qlb.returned_on IS NULL AND %s"},
+		{Name: "box", Aliases: []string{"boite"}, Kind: queryBool,
+			Bool: func(g *Game) bool { return boolValue(g.BoxOwned) }, Columns: []string{"g.box_owned"}},
+		{Name: "collector", Kind: queryBool,
+			Bool: func(g *Game) bool { return boolValue(g.Collector) }, Columns: []string{"g.collector"}},
+		{Name: "price", Aliases: []string{"prix"}, Kind: queryNumber,
+			Number: func(g *Game) []float64 { return floatValue(g.PurchasePrice) }, Columns: []string{"g.purchase_price"}},
+		{Name: "copies", Aliases: []string{"exemplaires", "ex"}, Kind: queryNumber,
+			Number:  func(g *Game) []float64 { return []float64{float64(g.CopyCount)} },
+			Columns: []string{"(SELECT COUNT(*) FROM game_copies qgc WHERE qgc.game_id = g.game_id)"}},
+		{Name: "region", Kind: queryText,
+			Text: func(g *Game) []string { return g.CopyRegions }, Columns: []string{"qgr.region"},
+			Exists: "SELECT 1 FROM game_copies qgr WHERE qgr.game_id = g.game_id AND %s"},
+		{Name: "completeness", Aliases: []string{"completude", "status"}, Kind: queryText,
+			Text: func(g *Game) []string {
+				if g.Completeness == "" {
+					return nil
+				}
+				return []string{g.Completeness, completenessLabels[g.Completeness]}
+			},
+			Columns: []string{gameCompletenessSQL(nil), gameCompletenessSQL(completenessLabels)}},
+		{Name: "missing", Aliases: []string{"manque", "manquant"}, Kind: queryText,
+			Text: func(g *Game) []string { return g.MissingComponents }, Columns: []string{"qmm.name"},
+			Exists: `SELECT 1 FROM game_copies qmc
+				JOIN completeness_components qmm ON qmm.role <> 'seal' AND ` + componentAppliesSQL("qmm") + `
+				WHERE qmc.game_id = g.game_id
+				AND NOT EXISTS (SELECT 1 FROM copy_components qmp WHERE qmp.copy_id = qmc.copy_id AND qmp.component_id = qmm.component_id)
+				AND NOT ` + copySealedSQL("qmc", "qms") + ` AND %s`},
+		{Name: "play", Aliases: []string{"progression", "suivi"}, Kind: queryText,
+			Text: func(g *Game) []string {
+				if g.PlayStatus == nil {
+					return nil
+				}
+				return []string{*g.PlayStatus, playStatusText(g.PlayStatus)}
+			},
+			Columns: []string{"g.play_status", playStatusLabelSQL("g.play_status")}},
+		{Name: "hours", Aliases: []string{"heures"}, Kind: queryNumber,
+			Number: func(g *Game) []float64 { return floatValue(g.HoursPlayed) }, Columns: []string{"g.hours_played"}},
+		{Name: "score", Aliases: []string{"note"}, Kind: queryNumber,
+			Number: func(g *Game) []float64 { return intValue(g.PersonalRating) }, Columns: []string{"g.personal_rating"}},
+		{Name: "finished", Aliases: []string{"termine"}, Kind: queryYear,
+			Number: func(g *Game) []float64 { return yearValues(g.PlayFinishedOn) }, Columns: []string{"g.play_finished_on"}},
+		{Name: "year", Aliases: []string{"annee"}, Kind: queryYear,
+			Number:  func(g *Game) []float64 { return yearValues(g.JPReleaseDate, g.USReleaseDate, g.EUReleaseDate) },
+			Columns: []string{"g.jp_release_date", "g.us_release_date", "g.eu_release_date"}},
+		{Name: "id", Kind: queryNumber,
+			Number: func(g *Game) []float64 { return []float64{float64(g.GameID)} }, Columns: []string{"g.game_id"}},
+	},
+	FreeText: []string{"title", "console", "genre"},
+}
+
+var consoleQuerySchema = querySchema[Console]{
+	Fields: []queryField[Console]{
+		{Name: "name", Aliases: []string{"nom", "n"}, Kind: queryText,
+			Text: func(c *Console) []string { return textValue(c.Name) }, Columns: []string{"c.name"}},
+		{Name: "manufacturer", Aliases: []string{"maker", "fabricant"}, Kind: queryText,
+			Text: func(c *Console) []string { return textValue(c.ManufacturerName) }, Columns: []string{"COALESCE(m.name, '')"}},
+		{Name: "type", Kind: queryText,
+			Text: func(c *Console) []string { return textValue(c.TypeName) }, Columns: []string{"COALESCE(ct.name, '')"}},
+		{Name: "generation", Aliases: []string{"gen"}, Kind: queryNumber,
+			Number: func(c *Console) []float64 { return intValue(c.Generation) }, Columns: []string{"c.generation"}},
+		{Name: "condition", Aliases: []string{"cond", "etat"}, Kind: queryNumber,
+			Number: func(c *Console) []float64 { return intValue(c.Condition) }, Columns: []string{"c.condition"}},
+		{Name: "owned", Aliases: []string{"possede"}, Kind: queryBool,
+			Bool: func(c *Console) bool { return c.Owned }, Columns: []string{"c.owned"}},
+		{Name: "lent", Aliases: []string{"prete"}, Kind: queryBool,
+			Bool:    func(c *Console) bool { return c.LentTo != "" },
+			Columns: []string{"EXISTS (SELECT 1 FROM loans ql WHERE ql.item_type = 'console' AND ql.item_id = c.console_id AND ql.returned_on IS NULL)"}},
+		{Name: "borrower", Aliases: []string{"emprunteur"}, Kind: queryText,
+			Text: func(c *Console) []string { return textValue(c.LentTo) }, Columns: []string{"qlb.borrower"},
+			Exists: "SELECT 1 FROM loans qlb WHERE qlb.item_type = 'console' AND qlb.item_id = c.console_id AND qlb.returned_on IS NULL AND %s"},
+		{Name: "year", Aliases: []string{"annee"}, Kind: queryYear,
+			Number:  func(c *Console) []float64 { return yearValues(c.JPReleaseDate, c.USReleaseDate, c.EUReleaseDate) },
+			Columns: []string{"c.jp_release_date", "c.us_release_date", "c.eu_release_date"}},
+		{Name: "id", Kind: queryNumber,
+			Number: func(c *Console) []float64 { return []float64{float64(c.ConsoleID)} }, Columns: []string{"c.console_id"}},
+	},
+	FreeText: []string{"name", "manufacturer"},
+}
+
+var accessoryQuerySchema = querySchema[Accessory]{
+	Fields: []queryField[Accessory]{
+		{Name: "name", Aliases: []string{"nom", "n"}, Kind: queryText,
+			Text: func(a *Accessory) []string { return textValue(a.Name) }, Columns: []string{"a.name"}},
+		{Name: "type", Kind: queryText,
+			Text: func(a *Accessory) []string { return textValue(a.TypeName) }, Columns: []string{"COALESCE(at.name, '')"}},
+		{Name: "manufacturer", Aliases: []string{"maker", "fabricant"}, Kind: queryText,
+			Text: func(a *Accessory) []string { return textValue(a.ManufacturerName) }, Columns: []string{"COALESCE(m.name, '')"}},
+		{Name: "color", Aliases: []string{"couleur"}, Kind: queryText,
+			Text: func(a *Accessory) []string { return optionalTextValue(a.Color) }, Columns: []string{"COALESCE(a.color, '')"}},
+		{Name: "console", Aliases: []string{"platform", "plateforme", "c"}, Kind: queryText,
+			Text: func(a *Accessory) []string { return a.Consoles }, Columns: []string{"qc.name"},
+			Exists: "SELECT 1 FROM accessory_consoles qac JOIN consoles qc ON qac.console_id = qc.console_id WHERE qac.accessory_id = a.accessory_id AND %s"},
+		{Name: "condition", Aliases: []string{"cond", "etat"}, Kind: queryNumber,
+			Number: func(a *Accessory) []float64 { return intValue(a.Condition) }, Columns: []string{"a.condition"}},
+		{Name: "owned", Aliases: []string{"possede"}, Kind: queryBool,
+			Bool: func(a *Accessory) bool { return a.Owned }, Columns: []string{"a.owned"}},
+		{Name: "lent", Aliases: []string{"prete"}, Kind: queryBool,
+			Bool:    func(a *Accessory) bool { return a.LentTo != "" },
+			Columns: []string{"EXISTS (SELECT 1 FROM loans ql WHERE ql.item_type = 'accessory' AND ql.item_id = a.accessory_id AND ql.returned_on IS NULL)"}},
+		{Name: "borrower", Aliases: []string{"emprunteur"}, Kind: queryText,
+			Text: func(a *Accessory) []string { return textValue(a.LentTo) }, Columns: []string{"qlb.borrower"},
+			Exists: "SELECT 1 FROM loans qlb WHERE qlb.item_type = 'accessory' AND qlb.item_id = a.accessory_id AND qlb.returned_on IS NULL AND %s"},
+		{Name: "price", Aliases: []string{"prix"}, Kind: queryNumber,
+			Number: func(a *Accessory) []float64 { return floatValue(a.PurchasePrice) }, Columns: []string{"a.purchase_price"}},
+		{Name: "quantity", Aliases: []string{"qty", "quantite"}, Kind: queryNumber,
+			Number: func(a *Accessory) []float64 { return []float64{float64(a.Quantity)} }, Columns: []string{"a.quantity"}},
+		{Name: "id", Kind: queryNumber,
+			Number: func(a *Accessory) []float64 { return []float64{float64(a.AccessoryID)} }, Columns: []string{"a.accessory_id"}},
+	},
+	FreeText: []string{"name", "type", "manufacturer", "color"},
+}
+
+// ========== Filters ==========
+
+// filterTerm is a term checked against its field
+type filterTerm[T any] struct {
+	Fields []*queryField[T] // Several fields for free text, matched if any of them does
+	Negate bool
+	Op     string // ":", "=", "<", "<=", ">", ">=" or ".." for ranges
+	Text   string // Lowercase text value
+	Low    *float64
+	High   *float64
+	Bool   bool
+}
+
+// searchFilter is a compiled query; all its terms must match
+type searchFilter[T any] struct {
+	Terms []filterTerm[T]
+}
+
+// compileSearchQuery parses a query for the fields of an entity
+// An empty query gives a filter matching everything.
+func compileSearchQuery[T any](schema *querySchema[T], text string) (*searchFilter[T], error) {
+	terms, err := parseSearchQuery(text)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := &searchFilter[T]{}
+	for _, term := range terms {
+		compiled, err := compileQueryTerm(schema, term)
+		if err != nil {
+			return nil, err
+		}
+		filter.Terms = append(filter.Terms, compiled)
+	}
+	return filter, nil
+}
+
+func compileQueryTerm[T any](schema *querySchema[T], term queryTerm) (filterTerm[T], error) {
+	fail := func(format string, args ...any) (filterTerm[T], error) {
+		return filterTerm[T]{}, &queryError{Offset: term.Offset, Message: fmt.Sprintf(format, args...)}
+	}
+
+	field := (*queryField[T])(nil)
+	if term.Field != "" {
+		field = schema.field(term.Field)
+		if field == nil {
+			// "Zelda: Link's Awakening" is free text, not a filter
+			if term.Value == "" && term.Op == ":" && !term.Quoted {
+				term.Value = term.Field + term.Op
+				term.Field = ""
+			} else {
+				return fail("champ inconnu « %s » (champs disponibles: %s)", term.Field, schema.fieldNames())
+			}
+		}
+	}
+
+	// Free text: contains, in any of the main text fields
+	if field == nil {
+		result := filterTerm[T]{Negate: term.Negate, Op: ":", Text: strings.ToLower(term.Value)}
+		for _, name := range schema.FreeText {
+			result.Fields = append(result.Fields, schema.field(name))
+		}
+		return result, nil
+	}
+
+	if term.Value == "" && !term.Quoted {
+		return fail("valeur manquante après « %s%s »", term.Field, term.Op)
+	}
+
+	result := filterTerm[T]{Fields: []*queryField[T]{field}, Negate: term.Negate, Op: term.Op}
+	if term.Op == "!=" {
+		// Anything but the value, including items without a value
+		result.Negate = !result.Negate
+		result.Op = "="
+	}
+
+	switch field.Kind {
+	case queryText:
+		if result.Op != ":" && result.Op != "=" {
+			return fail("« %s » ne peut pas être comparé avec %s (utiliser : ou =)", term.Field, term.Op)
+		}
+		result.Text = strings.ToLower(term.Value)
+
+	case queryBool:
+		if result.Op != ":" && result.Op != "=" {
+			return fail("« %s » ne peut pas être comparé avec %s (utiliser : ou =)", term.Field, term.Op)
+		}
+		value, ok := parseQueryBool(term.Value)
+		if !ok {
+			return fail("« %s » attend oui ou non, pas « %s »", term.Field, term.Value)
+		}
+		result.Op = "="
+		result.Bool = value
+
+	case queryNumber, queryYear:
+		if result.Op == ":" {
+			result.Op = "="
+		}
+		if low, high, isRange := strings.Cut(term.Value, ".."); isRange {
+			if result.Op != "=" {
+				return fail("un intervalle s'écrit %s:a..b", term.Field)
+			}
+			if low == "" && high == "" {
+				return fail("intervalle vide pour « %s »", term.Field)
+			}
+			result.Op = ".."
+			for _, bound := range []struct {
+				text  string
+				value **float64
+			}{{low, &result.Low}, {high, &result.High}} {
+				if bound.text == "" {
+					continue
+				}
+				number, ok := parseQueryNumber(bound.text)
+				if !ok {
+					return fail("« %s » n'est pas un nombre", bound.text)
+				}
+				*bound.value = &number
+			}
+			break
+		}
+
+		number, ok := parseQueryNumber(term.Value)
+		if !ok {
+			return fail("« %s » attend un nombre, pas « %s »", term.Field, term.Value)
+		}
+		result.Low = &number
+	}
+
+	return result, nil
+}
+
+// parseQueryBool accepts yes/no in English and French
+func parseQueryBool(value string) (bool, bool) {
+	switch normalizeHeader(value) {
+	case "yes", "y", "true", "oui", "o", "vrai", "1":
+		return true, true
+	case "no", "n", "false", "non", "faux", "0":
+		return false, true
+	}
+	return false, false
+}
+
+// parseQueryNumber accepts "19.99", "19,99" and "19.99€"
+func parseQueryNumber(value string) (float64, bool) {
+	value = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(value), "€"))
+	number, err := strconv.ParseFloat(strings.Replace(value, ",", ".", 1), 64)
+	return number, err == nil
+}
+
+// ========== In-memory Matching ==========
+
+// Match reports whether an item matches every term of the filter
+func (f *searchFilter[T]) Match(item *T) bool {
+	for _, term := range f.Terms {
+		if term.match(item) == term.Negate {
+			return false
+		}
+	}
+	return true
+}
+
+// Apply returns the matching items, in the same order
+func (f *searchFilter[T]) Apply(items []T) []T {
+	if len(f.Terms) == 0 {
+		return items
+	}
+	var matching []T
+	for i := range items {
+		if f.Match(&items[i]) {
+			matching = append(matching, items[i])
+		}
+	}
+	return matching
+}
+
+func (t *filterTerm[T]) match(item *T) bool {
+	for _, field := range t.Fields {
+		switch field.Kind {
+		case queryText:
+			for _, value := range field.Text(item) {
+				value = strings.ToLower(value)
+				if (t.Op == ":" && strings.Contains(value, t.Text)) || (t.Op == "=" && value == t.Text) {
+					return true
+				}
+			}
+		case queryBool:
+			if field.Bool(item) == t.Bool {
+				return true
+			}
+		case queryNumber, queryYear:
+			for _, value := range field.Number(item) {
+				if t.matchNumber(value) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+func (t *filterTerm[T]) matchNumber(value float64) bool {
+	switch t.Op {
+	case "=":
+		return value == *t.Low
+	case "<":
+		return value < *t.Low
+	case "<=":
+		return value <= *t.Low
+	case ">":
+		return value > *t.Low
+	case ">=":
+		return value >= *t.Low
+	case "..":
+		return (t.Low == nil || value >= *t.Low) && (t.High == nil || value <= *t.High)
+	}
+	return false
+}
+
+// ========== SQL ==========
+
+// likeEscaper escapes the LIKE wildcards of a searched text
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// SQL returns the filter as a WHERE condition ("" when it matches everything)
+// Arguments are numbered from firstArg on.
+func (f *searchFilter[T]) SQL(d dialect, firstArg int) (string, []any) {
+	var conditions []string
+	var args []any
+
+	arg := func(value any) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", firstArg+len(args)-1)
+	}
+
+	for _, term := range f.Terms {
+		var alternatives []string
+		for _, field := range term.Fields {
+			var columns []string
+			for _, column := range field.Columns {
+				columns = append(columns, term.sqlCondition(d, field, column, arg))
+			}
+			condition := strings.Join(columns, " OR ")
+			if field.Exists != "" {
+				condition = "EXISTS (" + fmt.Sprintf(field.Exists, condition) + ")"
+			}
+			alternatives = append(alternatives, condition)
+		}
+
+		condition := "(" + strings.Join(alternatives, " OR ") + ")"
+		if term.Negate {
+			// NULL columns don't match, so their negation does (as in memory)
+			condition = "NOT COALESCE(" + condition + ", FALSE)"
+		}
+		conditions = append(conditions, condition)
+	}
+	return strings.Join(conditions, " AND "), args
+}
+
+func (t *filterTerm[T]) sqlCondition(d dialect, field *queryField[T], column string, arg func(any) string) string {
+	switch field.Kind {
+	case queryText:
+		lower := fmt.Sprintf(d.lower, column)
+		if t.Op == "=" {
+			return fmt.Sprintf("%s = %s", lower, arg(t.Text))
+		}
+		return fmt.Sprintf(`%s LIKE %s ESCAPE '\'`, lower, arg("%"+likeEscaper.Replace(t.Text)+"%"))
+
+	case queryBool:
+		return fmt.Sprintf("COALESCE(%s, FALSE) = %s", column, arg(t.Bool))
+
+	default:
+		if field.Kind == queryYear {
+			column = fmt.Sprintf(d.yearOf, column)
+		}
+		// The cast lets PostgreSQL compare integer columns with decimal values
+		number := func(value float64) string {
+			return "CAST(" + arg(value) + " AS DOUBLE PRECISION)"
+		}
+		switch {
+		case t.Op != "..":
+			return fmt.Sprintf("%s %s %s", column, t.Op, number(*t.Low))
+		case t.Low != nil && t.High != nil:
+			return fmt.Sprintf("%s BETWEEN %s AND %s", column, number(*t.Low), number(*t.High))
+		case t.Low != nil:
+			return fmt.Sprintf("%s >= %s", column, number(*t.Low))
+		default:
+			return fmt.Sprintf("%s <= %s", column, number(*t.High))
+		}
+	}
+}
@@ -0,0 +1,256 @@
+package main
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+)
+
+// newTestStore opens an empty SQLite collection in a temporary folder
+func newTestStore(t *testing.T) *sqlStore {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("DB_PATH", filepath.Join(dir, "vgc.db"))
+	t.Setenv("MEDIA_DIR", filepath.Join(dir, "media"))
+	store, err := newSQLiteStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(store.Close)
+	return store
+}
+
+func TestParseSearchQuery(t *testing.T) {
+	tests := []struct {
+		query string
+		want  []queryTerm
+	}{
+		{"", nil},
+		{"zelda", []queryTerm{{Value: "zelda"}}},
+		{"  zelda   link ", []queryTerm{{Offset: 2, Value: "zelda"}, {Offset: 10, Value: "link"}}},
+		{`title:"link's awakening"`, []queryTerm{{Field: "title", Op: ":", Value: "link's awakening", Quoted: true}}},
+		{`"super mario"`, []queryTerm{{Value: "super mario", Quoted: true}}},
+		{"-console:snes", []queryTerm{{Negate: true, Field: "console", Op: ":", Value: "snes"}}},
+		{`-"mario kart"`, []queryTerm{{Negate: true, Value: "mario kart", Quoted: true}}},
+		{"a - b", []queryTerm{{Value: "a"}, {Offset: 2, Value: "-"}, {Offset: 4, Value: "b"}}},
+		{"condition>=4", []queryTerm{{Field: "condition", Op: ">=", Value: "4"}}},
+		{"condition<=2", []queryTerm{{Field: "condition", Op: "<=", Value: "2"}}},
+		{"genre!=rpg", []queryTerm{{Field: "genre", Op: "!=", Value: "rpg"}}},
+		{"price:10..20", []queryTerm{{Field: "price", Op: ":", Value: "10..20"}}},
+		{"year>1995", []queryTerm{{Field: "year", Op: ">", Value: "1995"}}},
+		{"title=", []queryTerm{{Field: "title", Op: "=", Value: ""}}},
+		{`title:""`, []queryTerm{{Field: "title", Op: ":", Value: "", Quoted: true}}},
+		// Offsets count characters, not bytes
+		{"été box:yes", []queryTerm{{Value: "été"}, {Offset: 4, Field: "box", Op: ":", Value: "yes"}}},
+		{"Zelda: Link's Awakening", []queryTerm{{Field: "Zelda", Op: ":"}, {Offset: 7, Value: "Link's"}, {Offset: 14, Value: "Awakening"}}},
+	}
+
+	for _, tt := range tests {
+		got, err := parseSearchQuery(tt.query)
+		if err != nil {
+			t.Errorf("parseSearchQuery(%q): %v", tt.query, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseSearchQuery(%q) = %+v, want %+v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestParseSearchQueryErrors(t *testing.T) {
+	tests := []struct {
+		query  string
+		offset int
+	}{
+		{`title:"unterminated`, 6},
+		{`zelda "open`, 6},
+	}
+
+	for _, tt := range tests {
+		_, err := parseSearchQuery(tt.query)
+		var queryErr *queryError
+		if !errors.As(err, &queryErr) {
+			t.Errorf("parseSearchQuery(%q) error = %v, want a *queryError", tt.query, err)
+			continue
+		}
+		if queryErr.Offset != tt.offset {
+			t.Errorf("parseSearchQuery(%q) error offset = %d, want %d", tt.query, queryErr.Offset, tt.offset)
+		}
+	}
+}
+
+func TestCompileSearchQuery(t *testing.T) {
+	ptr := func(v float64) *float64 { return &v }
+	tests := []struct {
+		query  string
+		fields []string // Names of the fields of the single term
+		want   filterTerm[Game]
+	}{
+		{"Zelda", []string{"title", "console", "genre"}, filterTerm[Game]{Op: ":", Text: "zelda"}},
+		{"-ÉTÉ", []string{"title", "console", "genre"}, filterTerm[Game]{Negate: true, Op: ":", Text: "été"}},
+		{"Zelda:", []string{"title", "console", "genre"}, filterTerm[Game]{Op: ":", Text: "zelda:"}},
+		{"titre:Mario", []string{"title"}, filterTerm[Game]{Op: ":", Text: "mario"}},
+		{`t="Super Mario"`, []string{"title"}, filterTerm[Game]{Op: "=", Text: "super mario"}},
+		{"genre!=RPG", []string{"genre"}, filterTerm[Game]{Negate: true, Op: "=", Text: "rpg"}},
+		{"-genre!=rpg", []string{"genre"}, filterTerm[Game]{Op: "=", Text: "rpg"}},
+		{"box:oui", []string{"box"}, filterTerm[Game]{Op: "=", Bool: true}},
+		{"owned=no", []string{"owned"}, filterTerm[Game]{Op: "=", Bool: false}},
+		{"condition:4", []string{"condition"}, filterTerm[Game]{Op: "=", Low: ptr(4)}},
+		{"etat>=3", []string{"condition"}, filterTerm[Game]{Op: ">=", Low: ptr(3)}},
+		{"prix<19,99€", []string{"price"}, filterTerm[Game]{Op: "<", Low: ptr(19.99)}},
+		{"price:10..20", []string{"price"}, filterTerm[Game]{Op: "..", Low: ptr(10), High: ptr(20)}},
+		{"year:..1999", []string{"year"}, filterTerm[Game]{Op: "..", High: ptr(1999)}},
+		{"year:2000..", []string{"year"}, filterTerm[Game]{Op: "..", Low: ptr(2000)}},
+	}
+
+	for _, tt := range tests {
+		filter, err := compileSearchQuery(&gameQuerySchema, tt.query)
+		if err != nil {
+			t.Errorf("compileSearchQuery(%q): %v", tt.query, err)
+			continue
+		}
+		if len(filter.Terms) != 1 {
+			t.Errorf("compileSearchQuery(%q) = %d terms, want 1", tt.query, len(filter.Terms))
+			continue
+		}
+		got := filter.Terms[0]
+		var names []string
+		for _, field := range got.Fields {
+			names = append(names, field.Name)
+		}
+		if !slices.Equal(names, tt.fields) {
+			t.Errorf("compileSearchQuery(%q) fields = %v, want %v", tt.query, names, tt.fields)
+		}
+		got.Fields = nil
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("compileSearchQuery(%q) = %+v, want %+v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestCompileSearchQueryErrors(t *testing.T) {
+	tests := []string{
+		"studio:nintendo", // Unknown field
+		`studio:"x"`,      // Unknown field, even quoted
+		"title>3",         // Text compared as a number
+		"box>1",           // Bool compared as a number
+		"box:maybe",       // Neither yes nor no
+		"condition:good",  // Not a number
+		"price:a..20",     // Bound not a number
+		"price:..",        // Empty range
+		"price>10..20",    // Range with another operator
+		"condition:",      // Missing value
+		`title:"open`,     // Unterminated quote
+	}
+
+	for _, query := range tests {
+		_, err := compileSearchQuery(&gameQuerySchema, query)
+		var queryErr *queryError
+		if !errors.As(err, &queryErr) {
+			t.Errorf("compileSearchQuery(%q) error = %v, want a *queryError", query, err)
+		}
+	}
+}
+
+func TestSearchFilterSQL(t *testing.T) {
+	tests := []struct {
+		query string
+		where string
+		args  []any
+	}{
+		{"", "", nil},
+		{"title:zelda", `(unicode_lower(g.title) LIKE $3 ESCAPE '\')`, []any{"%zelda%"}},
+		// LIKE wildcards and the escape character are searched literally
+		{`title:"100%_sur\"`, `(unicode_lower(g.title) LIKE $3 ESCAPE '\')`, []any{`%100\%\_sur\\%`}},
+		{"title=Zelda", `(unicode_lower(g.title) = $3)`, []any{"zelda"}},
+		{"-box:yes", `NOT COALESCE((COALESCE(g.box_owned, FALSE) = $3), FALSE)`, []any{true}},
+		{"condition>=4", `(g.condition >= CAST($3 AS DOUBLE PRECISION))`, []any{4.0}},
+		{"price:10..20", `(g.purchase_price BETWEEN CAST($3 AS DOUBLE PRECISION) AND CAST($4 AS DOUBLE PRECISION))`, []any{10.0, 20.0}},
+		{"condition:4 owned:no", `(g.condition = CAST($3 AS DOUBLE PRECISION)) AND (COALESCE(g.owned, FALSE) = $4)`, []any{4.0, false}},
+	}
+
+	for _, tt := range tests {
+		filter, err := compileSearchQuery(&gameQuerySchema, tt.query)
+		if err != nil {
+			t.Errorf("compileSearchQuery(%q): %v", tt.query, err)
+			continue
+		}
+		where, args := filter.SQL(sqliteDialect, 3)
+		if where != tt.where || !reflect.DeepEqual(args, tt.args) {
+			t.Errorf("SQL of %q = %s %v, want %s %v", tt.query, where, args, tt.where, tt.args)
+		}
+	}
+}
+
+// TestSearchFilterApplyMatchesSQL checks that the database (REST API, command line) and the
+// in-memory filter (tables of the window) find the same games
+func TestSearchFilterApplyMatchesSQL(t *testing.T) {
+	store := newTestStore(t)
+
+	consoleID, err := store.SaveConsole(&Console{Name: "Super Nintendo", Owned: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	developerID, err := store.AddDeveloper("Nintendo EAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	intPtr := func(v int) *int { return &v }
+	floatPtr := func(v float64) *float64 { return &v }
+	boolPtr := func(v bool) *bool { return &v }
+	date := func(year int) *time.Time {
+		d := time.Date(year, 6, 1, 0, 0, 0, 0, time.UTC)
+		return &d
+	}
+	games := []Game{
+		{Title: "The Legend of Zelda: A Link to the Past", Owned: true, Condition: intPtr(5), PurchasePrice: floatPtr(45), BoxOwned: boolPtr(true),
+			DeveloperIDs: []int{developerID}, JPReleaseDate: date(1991)},
+		{Title: "L'ÉTÉ ÉTERNEL", Owned: true, Condition: intPtr(2), PurchasePrice: floatPtr(12.5), HoursPlayed: floatPtr(30)},
+		{Title: "100%_sur", Owned: true, Condition: intPtr(3), BoxOwned: boolPtr(false), EUReleaseDate: date(1998)},
+		{Title: "Chrono Trigger", Owned: false, DeveloperIDs: []int{developerID}, USReleaseDate: date(1995)},
+	}
+	for i := range games {
+		games[i].ConsoleID = &consoleID
+		if _, err := store.SaveGame(&games[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	all, err := store.GetGames()
+	if err != nil {
+		t.Fatal(err)
+	}
+	titles := func(games []Game) []string {
+		var result []string
+		for _, g := range games {
+			result = append(result, g.Title)
+		}
+		slices.Sort(result)
+		return result
+	}
+
+	queries := []string{
+		"", "zelda", "-zelda", "nintendo", "été", "title:été", `title="l'été éternel"`, "title:%", "title:_", `title:\`,
+		"condition>=3", "condition:2..3", "-condition:5", "condition!=5", "price:..20", "price>40",
+		"owned:no", "box:yes", "-box:yes", "dev:ead", "-dev:ead", "year:1991", "year>=1995", "year:..1995",
+		"hours>10", "copies:1", "copies:0", "zelda owned:yes", `-"chrono trigger"`,
+	}
+	for _, query := range queries {
+		filter, err := compileSearchQuery(&gameQuerySchema, query)
+		if err != nil {
+			t.Fatalf("compileSearchQuery(%q): %v", query, err)
+		}
+		inMemory := titles(filter.Apply(all))
+
+		found, err := store.SearchGames(query)
+		if err != nil {
+			t.Fatalf("SearchGames(%q): %v", query, err)
+		}
+		if inDatabase := titles(found); !slices.Equal(inMemory, inDatabase) {
+			t.Errorf("%q: in memory %q, in the database %q", query, inMemory, inDatabase)
+		}
+	}
+}
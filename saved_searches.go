@@ -0,0 +1,202 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// ========== SAVED SEARCHES ==========
+// Each tab lists its saved searches on the left. Only the query is stored, so a saved
+// search always runs against the current data, and the counts are updated whenever the
+// tab is reloaded.
+
+// savedSearchPanelOffset is the share of the tab width taken by the saved searches
+const savedSearchPanelOffset = 0.16
+
+// savedSearchEntry is a line of the panel: "Tout" then the saved searches
+type savedSearchEntry struct {
+	Search SavedSearch
+	Count  string
+}
+
+// createSavedSearchPanel lists the saved searches of a tab with the number of items they find
+// Selecting one puts its query in the search bar. count runs a query on the data of the tab.
+// The returned function keeps the selection in sync with the text of the search bar.
+func createSavedSearchPanel(w fyne.Window, store CollectionStore, entityType string, searchEntry *widget.Entry, count func(query string) (int, error)) (fyne.CanvasObject, func(query string)) {
+	var entries []savedSearchEntry
+	selected := -1
+	syncing := false // Set while the selection follows the search bar, so it doesn't set the text back
+
+	deleteBtn := widget.NewButtonWithIcon("", theme.DeleteIcon(), nil)
+	deleteBtn.Disable()
+
+	list := widget.NewList(
+		func() int {
+			return len(entries)
+		},
+		func() fyne.CanvasObject {
+			name := widget.NewLabel("")
+			name.Truncation = fyne.TextTruncateEllipsis
+			return container.NewBorder(nil, nil, nil, widget.NewLabel(""), name)
+		},
+		func(id widget.ListItemID, obj fyne.CanvasObject) {
+			row := obj.(*fyne.Container)
+			row.Objects[0].(*widget.Label).SetText(entries[id].Search.Name)
+			row.Objects[1].(*widget.Label).SetText(entries[id].Count)
+		},
+	)
+
+	load := func() {
+		entries = []savedSearchEntry{{Search: SavedSearch{Name: "Tout"}}}
+		searches, err := store.GetSavedSearches(entityType)
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("échec de chargement des recherches: %w", err), w)
+		}
+		for _, search := range searches {
+			entries = append(entries, savedSearchEntry{Search: search})
+		}
+
+		for i := range entries {
+			if n, err := count(entries[i].Search.Query); err != nil {
+				entries[i].Count = "⚠" // The query no longer parses
+			} else {
+				entries[i].Count = fmt.Sprintf("%d", n)
+			}
+		}
+		list.Refresh()
+	}
+
+	// syncSelection highlights the saved search matching the text of the search bar, if any
+	syncSelection := func(query string) {
+		query = strings.TrimSpace(query)
+		match := -1
+		for i, entry := range entries {
+			if strings.TrimSpace(entry.Search.Query) == query {
+				match = i
+				break
+			}
+		}
+		if match == selected {
+			return
+		}
+
+		syncing = true
+		if match >= 0 {
+			list.Select(match)
+		} else {
+			list.UnselectAll()
+		}
+		syncing = false
+	}
+
+	list.OnSelected = func(id widget.ListItemID) {
+		selected = id
+		if id > 0 {
+			deleteBtn.Enable()
+		} else {
+			deleteBtn.Disable()
+		}
+		if !syncing {
+			searchEntry.SetText(entries[id].Search.Query)
+		}
+	}
+	list.OnUnselected = func(id widget.ListItemID) {
+		selected = -1
+		deleteBtn.Disable()
+	}
+
+	saveBtn := widget.NewButtonWithIcon("Enregistrer", theme.DocumentSaveIcon(), func() {
+		query := strings.TrimSpace(searchEntry.Text)
+		if query == "" {
+			dialog.ShowInformation("Enregistrer la recherche", "Tapez d'abord une recherche dans la barre de recherche.", w)
+			return
+		}
+		if _, err := count(query); err != nil {
+			dialog.ShowError(fmt.Errorf("recherche invalide: %w", err), w)
+			return
+		}
+		showSaveSearchDialog(w, store, entityType, query, entries, func() {
+			load()
+			syncSelection(query)
+		})
+	})
+
+	deleteBtn.OnTapped = func() {
+		if selected <= 0 {
+			return
+		}
+		search := entries[selected].Search
+		dialog.ShowConfirm("Supprimer la recherche",
+			fmt.Sprintf("Supprimer la recherche « %s »? Les éléments qu'elle trouve ne sont pas supprimés.", search.Name),
+			func(confirmed bool) {
+				if !confirmed {
+					return
+				}
+				if err := store.DeleteSavedSearch(search.SearchID); err != nil {
+					dialog.ShowError(err, w)
+					return
+				}
+				selected = -1
+				list.UnselectAll()
+				load()
+				syncSelection(searchEntry.Text)
+			}, w)
+	}
+
+	load()
+	syncSelection(searchEntry.Text)
+
+	header := widget.NewLabelWithStyle("Recherches", fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+	buttons := container.NewBorder(nil, nil, nil, deleteBtn, saveBtn)
+	return container.NewBorder(header, buttons, nil, nil, list), syncSelection
+}
+
+// showSaveSearchDialog asks for the name of a search, replacing the search of the same name if confirmed
+func showSaveSearchDialog(w fyne.Window, store CollectionStore, entityType, query string, entries []savedSearchEntry, onSaved func()) {
+	nameEntry := widget.NewEntry()
+	nameEntry.SetPlaceHolder("ex: PS1 sans boîte")
+
+	save := func(search SavedSearch) {
+		if _, err := store.SaveSavedSearch(&search); err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		onSaved()
+	}
+
+	dialog.ShowForm("Enregistrer la recherche", "Enregistrer", "Annuler",
+		[]*widget.FormItem{
+			widget.NewFormItem("Nom", nameEntry),
+			widget.NewFormItem("Recherche", widget.NewLabel(query)),
+		},
+		func(confirmed bool) {
+			name := strings.TrimSpace(nameEntry.Text)
+			if !confirmed || name == "" {
+				return
+			}
+
+			// Same name: replace the query of the existing search
+			for _, entry := range entries[1:] {
+				if strings.EqualFold(entry.Search.Name, name) {
+					existing := entry.Search
+					dialog.ShowConfirm("Remplacer la recherche",
+						fmt.Sprintf("Une recherche « %s » existe déjà. La remplacer?", existing.Name),
+						func(replace bool) {
+							if replace {
+								existing.Query = query
+								save(existing)
+							}
+						}, w)
+					return
+				}
+			}
+
+			save(SavedSearch{EntityType: entityType, Name: name, Query: query})
+		}, w)
+}
@@ -0,0 +1,248 @@
+package main
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/widget"
+	"golang.org/x/text/collate"
+	"golang.org/x/text/language"
+)
+
+// ========== TABLE SORTING ==========
+// Clicking a table header sorts by that column. The previous sort columns are kept as
+// tie breakers (e.g. by platform, then by title), and clicking the main column again
+// reverses the order. Each tab remembers its sort in the preferences.
+
+// maxSortKeys is the number of columns taken into account when sorting
+const maxSortKeys = 3
+
+// Preference keys of the sort of each tab
+const (
+	prefGamesSort       = "gamesSort"
+	prefConsolesSort    = "consolesSort"
+	prefAccessoriesSort = "accessoriesSort"
+)
+
+// sortKey is a column to sort by
+type sortKey struct {
+	Column     string
+	Descending bool
+}
+
+// tableSort lists the sort keys, main column first
+type tableSort []sortKey
+
+// sortColumn describes a sortable table column
+// Exactly one of Text and Number is set. Empty values are always listed last.
+type sortColumn[T any] struct {
+	Key    string
+	Label  string
+	Text   func(item *T) string          // Compared alphabetically, ignoring case and accents
+	Number func(item *T) (float64, bool) // false when the value is not set
+}
+
+var gameSortColumns = []sortColumn[Game]{
+	{Key: "id", Label: "ID", Number: func(g *Game) (float64, bool) { return float64(g.GameID), true }},
+	{Key: "title", Label: "Titre", Text: func(g *Game) string { return g.Title }},
+	{Key: "platform", Label: "Plateforme", Text: func(g *Game) string { return g.ConsoleName }},
+	{Key: "genre", Label: "Genre", Text: func(g *Game) string { return g.GenreName }},
+	{Key: "condition", Label: "État", Number: func(g *Game) (float64, bool) { return sortNumber(g.Condition) }},
+	{Key: "copies", Label: "Exemplaires", Number: func(g *Game) (float64, bool) { return float64(g.CopyCount), true }},
+	{Key: "completeness", Label: "Complétude", Number: func(g *Game) (float64, bool) {
+		rank := slices.Index(completenessStatuses, g.Completeness)
+		return float64(rank), rank >= 0
+	}},
+}
+
+var consoleSortColumns = []sortColumn[Console]{
+	{Key: "id", Label: "ID", Number: func(c *Console) (float64, bool) { return float64(c.ConsoleID), true }},
+	{Key: "name", Label: "Nom", Text: func(c *Console) string { return c.Name }},
+	{Key: "manufacturer", Label: "Fabricant", Text: func(c *Console) string { return c.ManufacturerName }},
+	{Key: "generation", Label: "Gen", Number: func(c *Console) (float64, bool) { return sortNumber(c.Generation) }},
+	{Key: "condition", Label: "État", Number: func(c *Console) (float64, bool) { return sortNumber(c.Condition) }},
+}
+
+var accessorySortColumns = []sortColumn[Accessory]{
+	{Key: "id", Label: "ID", Number: func(a *Accessory) (float64, bool) { return float64(a.AccessoryID), true }},
+	{Key: "name", Label: "Nom", Text: func(a *Accessory) string { return a.Name }},
+	{Key: "color", Label: "Couleur", Text: func(a *Accessory) string { return sortText(a.Color) }},
+	{Key: "type", Label: "Type", Text: func(a *Accessory) string { return a.TypeName }},
+	{Key: "manufacturer", Label: "Fabricant", Text: func(a *Accessory) string { return a.ManufacturerName }},
+	{Key: "condition", Label: "État", Number: func(a *Accessory) (float64, bool) { return sortNumber(a.Condition) }},
+}
+
+// Order used when the chosen columns are equal (same as the database queries)
+var (
+	gameDefaultSort      = tableSort{{Column: "title"}, {Column: "platform"}, {Column: "id"}}
+	consoleDefaultSort   = tableSort{{Column: "name"}, {Column: "id"}}
+	accessoryDefaultSort = tableSort{{Column: "name"}, {Column: "id"}}
+)
+
+func sortNumber(value *int) (float64, bool) {
+	if value == nil {
+		return 0, false
+	}
+	return float64(*value), true
+}
+
+func sortText(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
+// loadTableSort reads a sort saved as "-condition,title" (a minus sign for descending)
+func loadTableSort(prefKey string) tableSort {
+	var ts tableSort
+	for _, field := range strings.Split(fyne.CurrentApp().Preferences().String(prefKey), ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		column, descending := strings.CutPrefix(field, "-")
+		ts = append(ts, sortKey{Column: column, Descending: descending})
+	}
+	return ts
+}
+
+// save remembers the sort for the next sessions
+func (ts tableSort) save(prefKey string) {
+	fields := make([]string, 0, len(ts))
+	for _, key := range ts {
+		if key.Descending {
+			fields = append(fields, "-"+key.Column)
+		} else {
+			fields = append(fields, key.Column)
+		}
+	}
+	fyne.CurrentApp().Preferences().SetString(prefKey, strings.Join(fields, ","))
+}
+
+// toggle returns the sort after a click on a column header: the main column changes
+// direction, any other column becomes the main one and the previous ones break ties
+func (ts tableSort) toggle(column string) tableSort {
+	if len(ts) > 0 && ts[0].Column == column {
+		result := slices.Clone(ts)
+		result[0].Descending = !result[0].Descending
+		return result
+	}
+
+	result := tableSort{{Column: column}}
+	for _, key := range ts {
+		if key.Column != column && len(result) < maxSortKeys {
+			result = append(result, key)
+		}
+	}
+	return result
+}
+
+// indicator returns the arrow shown in the header of a column: ▲/▼ for the main column,
+// followed by the position for the tie breakers
+func (ts tableSort) indicator(column string) string {
+	for i, key := range ts {
+		if key.Column != column {
+			continue
+		}
+		arrow := "▲"
+		if key.Descending {
+			arrow = "▼"
+		}
+		if i == 0 {
+			return " " + arrow
+		}
+		return " " + arrow + string(rune('1'+i))
+	}
+	return ""
+}
+
+// sortItems returns the items sorted by ts, then by the default order
+// The input slice is left untouched.
+func sortItems[T any](items []T, columns []sortColumn[T], ts tableSort, defaultSort tableSort) []T {
+	byKey := make(map[string]sortColumn[T], len(columns))
+	for _, column := range columns {
+		byKey[column.Key] = column
+	}
+
+	var keys tableSort
+	for _, key := range append(slices.Clone(ts), defaultSort...) {
+		if _, ok := byKey[key.Column]; ok {
+			keys = append(keys, key)
+		}
+	}
+
+	collator := collate.New(language.French, collate.IgnoreCase, collate.IgnoreDiacritics, collate.Numeric)
+	sorted := slices.Clone(items)
+	slices.SortStableFunc(sorted, func(a, b T) int {
+		for _, key := range keys {
+			result, emptyOrder := compareSortColumn(collator, byKey[key.Column], &a, &b)
+			if emptyOrder != 0 {
+				return emptyOrder // Empty values stay last whatever the direction
+			}
+			if key.Descending {
+				result = -result
+			}
+			if result != 0 {
+				return result
+			}
+		}
+		return 0
+	})
+	return sorted
+}
+
+// compareSortColumn compares two items on a column
+// emptyOrder is non-zero when only one of them has a value.
+func compareSortColumn[T any](collator *collate.Collator, column sortColumn[T], a, b *T) (result, emptyOrder int) {
+	if column.Text != nil {
+		textA, textB := strings.TrimSpace(column.Text(a)), strings.TrimSpace(column.Text(b))
+		if (textA == "") != (textB == "") {
+			return 0, emptyLast(textA == "")
+		}
+		return collator.CompareString(textA, textB), 0
+	}
+
+	numberA, okA := column.Number(a)
+	numberB, okB := column.Number(b)
+	if okA != okB {
+		return 0, emptyLast(!okA)
+	}
+	return cmp.Compare(numberA, numberB), 0
+}
+
+func emptyLast(firstIsEmpty bool) int {
+	if firstIsEmpty {
+		return 1
+	}
+	return -1
+}
+
+// newSortHeader creates a clickable table header
+func newSortHeader() fyne.CanvasObject {
+	btn := widget.NewButton("", nil)
+	btn.Importance = widget.LowImportance
+	btn.Alignment = widget.ButtonAlignLeading
+	return btn
+}
+
+// updateSortHeader shows the label of a column with its sort indicator
+// col is negative for columns that can't be sorted (the cover thumbnails).
+func updateSortHeader[T any](obj fyne.CanvasObject, columns []sortColumn[T], ts tableSort, col int, onSort func(column string)) {
+	btn := obj.(*widget.Button)
+	if col < 0 {
+		btn.SetText("")
+		btn.OnTapped = nil
+		return
+	}
+
+	key := columns[col].Key
+	btn.SetText(columns[col].Label + ts.indicator(key))
+	btn.OnTapped = func() {
+		if onSort != nil {
+			onSort(key)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+
+	"fyne.io/fyne/v2/test"
+)
+
+func TestSortItems(t *testing.T) {
+	intPtr := func(v int) *int { return &v }
+	games := []Game{
+		{GameID: 1, Title: "zelda", ConsoleName: "SNES", Condition: intPtr(3)},
+		{GameID: 2, Title: "Éternal Darkness", ConsoleName: "GameCube"},
+		{GameID: 3, Title: "Mario Kart 64", ConsoleName: "N64", Condition: intPtr(5)},
+		{GameID: 4, Title: "Mario Kart 8", ConsoleName: "Switch", Condition: intPtr(3)},
+		{GameID: 5, Title: "", ConsoleName: "SNES", Condition: intPtr(4)},
+		{GameID: 6, Title: "Zelda", ConsoleName: "NES", Condition: intPtr(3)},
+	}
+
+	tests := []struct {
+		name string
+		sort tableSort
+		want []int
+	}{
+		// Accents and case ignored, numbers in titles compared by value, empty titles last
+		{"default", nil, []int{2, 4, 3, 6, 1, 5}},
+		{"descending title", tableSort{{Column: "title", Descending: true}}, []int{6, 1, 3, 4, 2, 5}},
+		// Games without a condition stay last in both directions
+		{"condition", tableSort{{Column: "condition"}}, []int{4, 6, 1, 5, 3, 2}},
+		{"descending condition", tableSort{{Column: "condition", Descending: true}}, []int{3, 5, 4, 6, 1, 2}},
+		{"condition then platform", tableSort{{Column: "condition"}, {Column: "platform"}}, []int{6, 1, 4, 5, 3, 2}},
+		{"unknown column ignored", tableSort{{Column: "studio"}, {Column: "id", Descending: true}}, []int{6, 5, 4, 3, 2, 1}},
+	}
+
+	for _, tt := range tests {
+		sorted := sortItems(games, gameSortColumns, tt.sort, gameDefaultSort)
+		var got []int
+		for _, g := range sorted {
+			got = append(got, g.GameID)
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%s: sorted %v, want %v", tt.name, got, tt.want)
+		}
+	}
+	if games[0].GameID != 1 || games[5].GameID != 6 {
+		t.Error("sortItems modified its input")
+	}
+}
+
+func TestTableSortToggle(t *testing.T) {
+	tests := []struct {
+		sort   tableSort
+		column string
+		want   tableSort
+	}{
+		{nil, "title", tableSort{{Column: "title"}}},
+		{tableSort{{Column: "title"}}, "title", tableSort{{Column: "title", Descending: true}}},
+		{tableSort{{Column: "title", Descending: true}}, "platform", tableSort{{Column: "platform"}, {Column: "title", Descending: true}}},
+		{tableSort{{Column: "platform"}, {Column: "title"}}, "title", tableSort{{Column: "title"}, {Column: "platform"}}},
+		{tableSort{{Column: "a"}, {Column: "b"}, {Column: "c"}}, "d", tableSort{{Column: "d"}, {Column: "a"}, {Column: "b"}}},
+	}
+
+	for _, tt := range tests {
+		if got := tt.sort.toggle(tt.column); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%v.toggle(%q) = %v, want %v", tt.sort, tt.column, got, tt.want)
+		}
+	}
+}
+
+func TestTableSortPreference(t *testing.T) {
+	app := test.NewApp()
+	defer app.Quit()
+
+	tests := []struct {
+		saved string
+		want  tableSort
+	}{
+		{"", nil},
+		{"title", tableSort{{Column: "title"}}},
+		{"-condition,title", tableSort{{Column: "condition", Descending: true}, {Column: "title"}}},
+		{" -condition , ,platform,", tableSort{{Column: "condition", Descending: true}, {Column: "platform"}}},
+	}
+
+	for _, tt := range tests {
+		app.Preferences().SetString(prefGamesSort, tt.saved)
+		got := loadTableSort(prefGamesSort)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("loadTableSort(%q) = %v, want %v", tt.saved, got, tt.want)
+		}
+
+		got.save(prefGamesSort)
+		if again := loadTableSort(prefGamesSort); !reflect.DeepEqual(again, tt.want) {
+			t.Errorf("sort %v read back as %v", tt.want, again)
+		}
+	}
+}
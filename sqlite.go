@@ -0,0 +1,177 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"embed"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"modernc.org/sqlite" // pure Go driver, registers "sqlite"
+)
+
+// ========== SQLite Backend ==========
+// Single-user, offline alternative to PostgreSQL: the whole collection lives in one file.
+
+//go:embed migrations/sqlite/*.sql
+var sqliteMigrations embed.FS
+
+var sqliteDialect = dialect{
+	name:       "sqlite",
+	migrations: sqliteMigrations,
+	dir:        "migrations/sqlite",
+	migrationsTable: `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version    INTEGER PRIMARY KEY,
+			name       TEXT NOT NULL,
+			applied_at TEXT NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)
+	`,
+	yearOf: "CAST(substr(%s, 1, 4) AS INTEGER)", // Dates are stored as text starting with YYYY-MM-DD
+	lower:  "unicode_lower(%s)",                 // LOWER only folds ASCII letters, see init
+
+	tableColumns: "SELECT name, type FROM pragma_table_info($1) ORDER BY cid",
+	dropTable:    "DROP TABLE IF EXISTS %s",
+	// A transaction always reads a snapshot, and INTEGER PRIMARY KEY continues after the highest ID
+}
+
+// The built-in LOWER of SQLite leaves "É" as is, so searches through SQL would miss what the
+// in-memory filter of query.go finds; unicode_lower folds text the same way as that filter.
+func init() {
+	sqlite.MustRegisterDeterministicScalarFunction("unicode_lower", 1, func(ctx *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+		switch value := args[0].(type) {
+		case string:
+			return strings.ToLower(value), nil
+		case []byte:
+			return strings.ToLower(string(value)), nil
+		default:
+			return value, nil // NULL and numbers
+		}
+	})
+}
+
+// defaultSQLitePath is used when DB_PATH is not set
+const defaultSQLitePath = "vgc.db"
+
+// newSQLiteStore opens (or creates) the SQLite file configured in .env and applies pending migrations
+func newSQLiteStore() (*sqlStore, error) {
+	path := strings.TrimSpace(os.Getenv("DB_PATH"))
+	if path == "" {
+		path = defaultSQLitePath
+	}
+
+	// Foreign keys are off by default in SQLite and must be enabled per connection
+	params := url.Values{}
+	params.Add("_pragma", "foreign_keys(1)")
+	params.Add("_pragma", "busy_timeout(5000)")
+	params.Set("_time_format", "sqlite")
+
+	sqlDB, err := sql.Open("sqlite", "file:"+path+"?"+params.Encode())
+	if err != nil {
+		return nil, fmt.Errorf("unable to open database %s: %w", path, err)
+	}
+
+	// SQLite allows a single writer; one connection avoids "database is locked" errors
+	sqlDB.SetMaxOpenConns(1)
+
+	if err := sqlDB.Ping(); err != nil {
+		sqlDB.Close()
+		return nil, fmt.Errorf("unable to open database %s: %w", path, err)
+	}
+
+	db := &sqlDatabase{db: sqlDB}
+	if err := migrate(db, sqliteDialect); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &sqlStore{db: db, dialect: sqliteDialect, media: newMediaCache()}, nil
+}
+
+// ========== database/sql Adapter ==========
+
+// sqlDatabase adapts a database/sql pool to the database interface
+type sqlDatabase struct {
+	db *sql.DB
+}
+
+func (d *sqlDatabase) Exec(ctx context.Context, query string, args ...any) (int64, error) {
+	return sqlExec(d.db.ExecContext(ctx, query, args...))
+}
+
+func (d *sqlDatabase) Query(ctx context.Context, query string, args ...any) (rows, error) {
+	r, err := d.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return sqlRows{r}, nil
+}
+
+func (d *sqlDatabase) QueryRow(ctx context.Context, query string, args ...any) row {
+	return d.db.QueryRowContext(ctx, query, args...)
+}
+
+func (d *sqlDatabase) Begin(ctx context.Context) (transaction, error) {
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &sqlTransaction{tx: tx}, nil
+}
+
+func (d *sqlDatabase) Ping(ctx context.Context) error {
+	return d.db.PingContext(ctx)
+}
+
+func (d *sqlDatabase) Close() {
+	d.db.Close()
+}
+
+// sqlTransaction adapts a database/sql transaction to the transaction interface
+type sqlTransaction struct {
+	tx *sql.Tx
+}
+
+func (t *sqlTransaction) Exec(ctx context.Context, query string, args ...any) (int64, error) {
+	return sqlExec(t.tx.ExecContext(ctx, query, args...))
+}
+
+func (t *sqlTransaction) Query(ctx context.Context, query string, args ...any) (rows, error) {
+	r, err := t.tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return sqlRows{r}, nil
+}
+
+func (t *sqlTransaction) QueryRow(ctx context.Context, query string, args ...any) row {
+	return t.tx.QueryRowContext(ctx, query, args...)
+}
+
+func (t *sqlTransaction) Commit(ctx context.Context) error {
+	return t.tx.Commit()
+}
+
+func (t *sqlTransaction) Rollback(ctx context.Context) error {
+	return t.tx.Rollback()
+}
+
+// sqlRows drops the error returned by sql.Rows.Close (it is reported by Err)
+type sqlRows struct {
+	*sql.Rows
+}
+
+func (r sqlRows) Close() {
+	r.Rows.Close()
+}
+
+// sqlExec extracts the number of affected rows from an Exec result
+func sqlExec(result sql.Result, err error) (int64, error) {
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/joho/godotenv"
+)
+
+// ========== Storage Interface ==========
+// The UI only talks to a CollectionStore, never to a database driver directly.
+// This keeps SQL out of the widgets and lets other backends (or in-memory fakes)
+// be plugged in without touching ui.go or dialogs.go.
+
+// CollectionStore gives access to the games, consoles, accessories and lookup tables
+type CollectionStore interface {
+	// Games
+	GetGames() ([]Game, error)
+	SearchGames(query string) ([]Game, error) // Query syntax in query.go; returns a *queryError when malformed
+	GetGameByID(gameID int) (*Game, error)
+	SaveGame(game *Game) (int, error) // GameID == 0 inserts, otherwise updates
+	DeleteGame(gameID int) error
+
+	// Consoles
+	GetConsoles() ([]Console, error)
+	SearchConsoles(query string) ([]Console, error) // Same syntax as SearchGames
+	GetConsoleByID(consoleID int) (*Console, error)
+	SaveConsole(console *Console) (int, error) // ConsoleID == 0 inserts, otherwise updates
+	DeleteConsole(consoleID int) error
+
+	// Accessories
+	GetAccessories() ([]Accessory, error)
+	SearchAccessories(query string) ([]Accessory, error) // Same syntax as SearchGames
+	GetAccessoryByID(accessoryID int) (*Accessory, error)
+	SaveAccessory(accessory *Accessory) (int, error) // AccessoryID == 0 inserts, otherwise updates
+	DeleteAccessory(accessoryID int) error
+
+	// Lookup tables
+	GetGenres() ([]Genre, error)
+	GetDevelopers() ([]Developer, error)
+	GetComposers() ([]Composer, error)
+	GetPublishers() ([]Publisher, error)
+	GetProducers() ([]Producer, error)
+	GetManufacturers() ([]Manufacturer, error)
+	GetConsoleTypes() ([]ConsoleType, error)
+	GetAccessoryTypes() ([]AccessoryType, error)
+	GetRatingSystems() ([]RatingSystem, error)
+
+	// Lookup entries that can be created from the game form
+	AddDeveloper(name string) (int, error)
+	AddComposer(name string) (int, error)
+	AddPublisher(name string) (int, error)
+	AddProducer(name string) (int, error)
+
+	// Lookup entries edited through the REST API (table is "genres", "developers"... as in the schema)
+	SaveLookupEntry(table string, id int, name string) (int, error) // id == 0 inserts, otherwise renames; not for rating_systems
+	SaveRatingSystem(rating *RatingSystem) (int, error)             // RatingID == 0 inserts, otherwise updates
+	DeleteLookupEntry(table string, id int) error                   // Refused with errLookupEntryInUse while items use the entry
+
+	// Dashboard
+	GetDashboardStats() (*DashboardStats, error)
+
+	// Import
+	ImportCollection(batch *ImportBatch) error // All rows or nothing
+
+	// Images (itemType is "game", "console" or "accessory")
+	GetImages(itemType string, itemID int) ([]ItemImage, error)
+	AddImage(itemType string, itemID int, kind string, sourcePath string) (*ItemImage, error) // Copies the file into the media cache
+	DeleteImage(imageID int) error
+
+	// Saved searches (entityType is "game", "console" or "accessory")
+	GetSavedSearches(entityType string) ([]SavedSearch, error) // Sorted by name
+	SaveSavedSearch(search *SavedSearch) (int, error)          // SearchID == 0 inserts, otherwise updates
+	DeleteSavedSearch(searchID int) error
+
+	// Wishlist (items with Owned == false; itemType is "game", "console" or "accessory")
+	MarkAsBought(itemType string, itemID int, purchase Purchase) error // Sets Owned and the purchase details
+
+	// Game copies (GetGameByID also returns them)
+	GetGameCopies(gameID int) ([]GameCopy, error) // In the order they were added
+	SaveGameCopy(gameCopy *GameCopy) (int, error) // CopyID == 0 inserts, otherwise updates
+	DeleteGameCopy(copyID int) error              // Refused for the last copy of an owned game
+
+	// Completeness checklist of the game copies (GameCopy.Components)
+	GetCompletenessComponents() ([]CompletenessComponent, error)   // Every console type, in checklist order
+	GetGameComponents(gameID int) ([]CompletenessComponent, error) // Those that apply to the platform of a game
+	SaveCompletenessComponent(component *CompletenessComponent) (int, error)
+	DeleteCompletenessComponent(componentID int) error
+
+	// Market values (itemType is "game", "console" or "accessory")
+	GetMarketValues(itemType string, itemID int) ([]MarketValue, error) // Newest first
+	AddMarketValues(values []MarketValue) error                         // In a single transaction
+	DeleteMarketValue(valueID int) error
+
+	// Loans (itemType is "game", "console" or "accessory")
+	GetLoans(itemType string, itemID int) ([]Loan, error) // History of an item, newest first
+	GetOpenLoans() ([]Loan, error)                        // Items currently lent, with their name
+	SaveLoan(loan *Loan) (int, error)                     // Refuses a second current loan of an item
+	DeleteLoan(loanID int) error
+
+	// Backups (see backup.go)
+	WriteBackup(w io.Writer) (*BackupManifest, error) // Every table and the pictures they use, as a zip archive
+	RestoreBackup(archive *zip.Reader) error          // Replaces everything, or nothing on error
+
+	// Undo history (see undo.go; itemType is "game", "console" or "accessory")
+	SnapshotItem(itemType string, itemID int, withMedia bool) (*ItemSnapshot, error) // Every row of an item, none if it doesn't exist
+	RestoreItem(itemType string, itemID int, snapshot *ItemSnapshot) error           // Puts the rows of a snapshot back
+
+	// Connection
+	Ping() error // nil while the database is reachable
+	Close()
+}
+
+// openStore opens the storage backend selected by DB_DRIVER in .env with an up-to-date schema
+// DB_DRIVER=postgres (default) uses DB_HOST/DB_PORT/DB_USER/DB_PASSWORD/DB_NAME,
+// DB_DRIVER=sqlite uses the file given by DB_PATH
+func openStore() (CollectionStore, error) {
+	// Load .env file
+	err := godotenv.Load()
+	if err != nil {
+		return nil, fmt.Errorf("error loading .env file: %w", err)
+	}
+
+	driver := strings.ToLower(strings.TrimSpace(os.Getenv("DB_DRIVER")))
+	switch driver {
+	case "", "postgres", "postgresql":
+		return newPostgresStore()
+	case "sqlite", "sqlite3":
+		return newSQLiteStore()
+	default:
+		return nil, fmt.Errorf("unknown DB_DRIVER %q (expected postgres or sqlite)", driver)
+	}
+}
@@ -1,14 +1,16 @@
 package main
 
 import (
+	"errors"
 	"fmt"
-	"strings"
+	"slices"
 
 	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
-	"github.com/jackc/pgx/v5"
 )
 
 // ========== UTILITY FUNCTIONS ==========
@@ -30,35 +32,56 @@ func conditionToStars(condition *int) string {
 }
 
 // createSearchBar creates a search entry that filters data as user types
-// Returns a container with the search bar that has a fixed minimum width
-func createSearchBar(placeholder string, onSearch func(searchText string)) *fyne.Container {
+// onSearch returns an error when the query is malformed (see query.go): the message is
+// shown under the entry until the query is fixed, and the table keeps its last results.
+func createSearchBar(placeholder string, onSearch func(searchText string) error) (*fyne.Container, *widget.Entry) {
 	searchEntry := widget.NewEntry()
 	searchEntry.SetPlaceHolder(placeholder)
 
+	errorLabel := widget.NewLabel("")
+	errorLabel.Importance = widget.DangerImportance
+	errorLabel.Wrapping = fyne.TextWrapWord
+	errorLabel.Hide()
+
 	// Filter as user types
 	searchEntry.OnChanged = func(text string) {
-		onSearch(text)
+		err := onSearch(text)
+		var queryErr *queryError
+		if errors.As(err, &queryErr) {
+			errorLabel.SetText(fmt.Sprintf("Recherche invalide (caractère %d): %s", queryErr.Offset+1, queryErr.Message))
+			errorLabel.Show()
+		} else if err != nil {
+			errorLabel.SetText(err.Error())
+			errorLabel.Show()
+		} else {
+			errorLabel.Hide()
+		}
 	}
 
-	// Wrap in a container with padding to give it breathing room
-	// Using a fixed-size container ensures consistent width
-	return container.NewGridWithColumns(1, searchEntry)
+	// The error line only takes room while it is shown
+	return container.NewVBox(searchEntry, errorLabel), searchEntry
 }
 
 // ========== ACTION BUTTONS ==========
 
-// createActionButtons creates the Add/Details/Edit/Delete button toolbar
-func createActionButtons(w fyne.Window, conn *pgx.Conn, entityType string, detailsBtn, editBtn, deleteBtn *widget.Button, refreshFunc func()) fyne.CanvasObject {
+// createActionButtons creates the Add/Details/Edit/Delete/Import/Export button toolbar
+// onExport is called by the Export button so the tab can pass its currently displayed rows
+func createActionButtons(w fyne.Window, store CollectionStore, entityType string, detailsBtn, editBtn, deleteBtn *widget.Button, refreshFunc func(), onExport func()) fyne.CanvasObject {
 	addBtn := widget.NewButton("Ajouter", func() {
 		if entityType == "game" {
-			showAddGameDialog(w, conn, refreshFunc)
+			showAddGameDialog(w, store, true, refreshFunc)
 		} else if entityType == "console" {
-			showAddConsoleDialog(w, conn, refreshFunc)
+			showAddConsoleDialog(w, store, true, refreshFunc)
 		} else if entityType == "accessory" {
-			showAddAccessoryDialog(w, conn, refreshFunc)
+			showAddAccessoryDialog(w, store, true, refreshFunc)
 		}
 	})
 
+	importBtn := widget.NewButton("Importer", func() {
+		showImportWizard(w, store, entityType, refreshFunc)
+	})
+	exportBtn := widget.NewButton("Exporter", onExport)
+
 	// Style buttons with colors
 	addBtn.Importance = widget.SuccessImportance   // Green
 	detailsBtn.Importance = widget.HighImportance  // Blue
@@ -75,43 +98,126 @@ func createActionButtons(w fyne.Window, conn *pgx.Conn, entityType string, detai
 		detailsBtn,
 		editBtn,
 		deleteBtn,
+		importBtn,
+		exportBtn,
 	)
 }
 
 // ========== TABLE BUILDERS ==========
 
+// prefTableThumbnails is the preference key of the "Miniatures dans les tableaux" menu item
+const prefTableThumbnails = "tableThumbnails"
+
+// tableThumbnailSize is the width and row height of the optional cover column
+const tableThumbnailSize = 48
+
+// showTableThumbnails reports whether the tables start with a cover column
+func showTableThumbnails() bool {
+	return fyne.CurrentApp().Preferences().Bool(prefTableThumbnails)
+}
+
+// newTableCell creates a table cell able to show either a text or a cover thumbnail
+func newTableCell() fyne.CanvasObject {
+	thumb := canvas.NewImageFromFile("")
+	thumb.FillMode = canvas.ImageFillContain
+	return container.NewStack(widget.NewLabel(""), thumb)
+}
+
+// setTableCellText shows a text in a cell made by newTableCell
+func setTableCellText(obj fyne.CanvasObject, text string) {
+	cell := obj.(*fyne.Container)
+	label, thumb := cell.Objects[0].(*widget.Label), cell.Objects[1].(*canvas.Image)
+	thumb.Hide()
+	label.Show()
+	label.SetText(text)
+}
+
+// setTableCellThumbnail shows a cover thumbnail (or nothing) in a cell made by newTableCell
+func setTableCellThumbnail(obj fyne.CanvasObject, path string) {
+	cell := obj.(*fyne.Container)
+	label, thumb := cell.Objects[0].(*widget.Label), cell.Objects[1].(*canvas.Image)
+	label.Hide()
+	if path == "" {
+		thumb.Hide()
+		return
+	}
+	thumb.File = path
+	thumb.Show()
+	thumb.Refresh()
+}
+
+// setTableLayout applies the column widths, preceded by the cover column when thumbnails are shown
+func setTableLayout(table *widget.Table, rows int, thumbnails bool, widths ...float32) {
+	offset := 0
+	if thumbnails {
+		table.SetColumnWidth(0, tableThumbnailSize)
+		for row := 0; row < rows; row++ {
+			table.SetRowHeight(row, tableThumbnailSize)
+		}
+		offset = 1
+	}
+	for col, width := range widths {
+		table.SetColumnWidth(col+offset, width)
+	}
+	table.ShowHeaderColumn = false
+}
+
+// restoreTableSelection selects a row again after the table was rebuilt
+// When the item is no longer displayed (row -1), the selection is cleared.
+func restoreTableSelection(table *widget.Table, row int, selectedID *int, detailsBtn, editBtn, deleteBtn *widget.Button) {
+	if row >= 0 {
+		table.Select(widget.TableCellID{Row: row, Col: 0})
+		return
+	}
+	*selectedID = -1
+	detailsBtn.Disable()
+	editBtn.Disable()
+	deleteBtn.Disable()
+}
+
 // buildGamesTableWithSelection creates the games table and tracks selection
-func buildGamesTableWithSelection(w fyne.Window, conn *pgx.Conn, games []Game, detailsBtn, editBtn, deleteBtn *widget.Button, selectedGameID *int, refreshFunc func()) *widget.Table {
+func buildGamesTableWithSelection(w fyne.Window, store CollectionStore, games []Game, detailsBtn, editBtn, deleteBtn *widget.Button, selectedGameID *int, sort tableSort, onSort func(column string), refreshFunc func()) *widget.Table {
+	thumbnails := showTableThumbnails()
+	offset := 0
+	if thumbnails {
+		offset = 1
+	}
+
 	table := widget.NewTableWithHeaders(
 		func() (int, int) {
-			return len(games), 5
-		},
-		func() fyne.CanvasObject {
-			return widget.NewLabel("")
+			return len(games), 7 + offset
 		},
+		newTableCell,
 		func(id widget.TableCellID, obj fyne.CanvasObject) {
-			label := obj.(*widget.Label)
 			game := games[id.Row]
+			if thumbnails && id.Col == 0 {
+				setTableCellThumbnail(obj, game.CoverThumb)
+				return
+			}
 
-			switch id.Col {
+			switch id.Col - offset {
 			case 0:
-				label.SetText(fmt.Sprintf("%d", game.GameID))
+				setTableCellText(obj, fmt.Sprintf("%d", game.GameID))
 			case 1:
-				label.SetText(game.Title)
+				setTableCellText(obj, lentBadge(game.Title, game.LentTo))
 			case 2:
-				label.SetText(game.ConsoleName)
+				setTableCellText(obj, game.ConsoleName)
 			case 3:
-				label.SetText(game.GenreName)
+				setTableCellText(obj, game.GenreName)
 			case 4:
-				label.SetText(conditionToStars(game.Condition))
+				setTableCellText(obj, conditionToStars(game.Condition))
+			case 5:
+				setTableCellText(obj, copiesSummary(game.CopyCount, game.CopyRegions))
+			case 6:
+				setTableCellText(obj, completenessLabels[game.Completeness])
 			}
 		},
 	)
 
+	// Clicking a header sorts by that column
+	table.CreateHeader = newSortHeader
 	table.UpdateHeader = func(id widget.TableCellID, obj fyne.CanvasObject) {
-		label := obj.(*widget.Label)
-		headers := []string{"ID", "Titre", "Plateforme", "Genre", "État"}
-		label.SetText(headers[id.Col])
+		updateSortHeader(obj, gameSortColumns, sort, id.Col-offset, onSort)
 	}
 
 	table.OnSelected = func(id widget.TableCellID) {
@@ -129,52 +235,58 @@ func buildGamesTableWithSelection(w fyne.Window, conn *pgx.Conn, games []Game, d
 		deleteBtn.Disable()
 	}
 
-	table.SetColumnWidth(0, 50)
-	table.SetColumnWidth(1, 400)
-	table.SetColumnWidth(2, 400)
-	table.SetColumnWidth(3, 200)
-	table.SetColumnWidth(4, 50)
-	table.ShowHeaderColumn = false
+	setTableLayout(table, len(games), thumbnails, 60, 400, 300, 200, 90, 160, 110)
+
+	// Keep the selected item selected after a sort or a search when it is still displayed
+	row := slices.IndexFunc(games, func(g Game) bool { return g.GameID == *selectedGameID })
+	restoreTableSelection(table, row, selectedGameID, detailsBtn, editBtn, deleteBtn)
 
 	return table
 }
 
 // buildConsolesTableWithSelection creates the consoles table and tracks selection
-func buildConsolesTableWithSelection(w fyne.Window, conn *pgx.Conn, consoles []Console, detailsBtn, editBtn, deleteBtn *widget.Button, selectedConsoleID *int, refreshFunc func()) *widget.Table {
+func buildConsolesTableWithSelection(w fyne.Window, store CollectionStore, consoles []Console, detailsBtn, editBtn, deleteBtn *widget.Button, selectedConsoleID *int, sort tableSort, onSort func(column string), refreshFunc func()) *widget.Table {
+	thumbnails := showTableThumbnails()
+	offset := 0
+	if thumbnails {
+		offset = 1
+	}
+
 	table := widget.NewTableWithHeaders(
 		func() (int, int) {
-			return len(consoles), 5
-		},
-		func() fyne.CanvasObject {
-			return widget.NewLabel("")
+			return len(consoles), 5 + offset
 		},
+		newTableCell,
 		func(id widget.TableCellID, obj fyne.CanvasObject) {
-			label := obj.(*widget.Label)
 			console := consoles[id.Row]
+			if thumbnails && id.Col == 0 {
+				setTableCellThumbnail(obj, console.CoverThumb)
+				return
+			}
 
-			switch id.Col {
+			switch id.Col - offset {
 			case 0:
-				label.SetText(fmt.Sprintf("%d", console.ConsoleID))
+				setTableCellText(obj, fmt.Sprintf("%d", console.ConsoleID))
 			case 1:
-				label.SetText(console.Name)
+				setTableCellText(obj, lentBadge(console.Name, console.LentTo))
 			case 2:
-				label.SetText(console.ManufacturerName)
+				setTableCellText(obj, console.ManufacturerName)
 			case 3:
 				if console.Generation != nil {
-					label.SetText(fmt.Sprintf("%d", *console.Generation))
+					setTableCellText(obj, fmt.Sprintf("%d", *console.Generation))
 				} else {
-					label.SetText("")
+					setTableCellText(obj, "")
 				}
 			case 4:
-				label.SetText(conditionToStars(console.Condition))
+				setTableCellText(obj, conditionToStars(console.Condition))
 			}
 		},
 	)
 
+	// Clicking a header sorts by that column
+	table.CreateHeader = newSortHeader
 	table.UpdateHeader = func(id widget.TableCellID, obj fyne.CanvasObject) {
-		label := obj.(*widget.Label)
-		headers := []string{"ID", "Nom", "Fabricant", "Gen", "État"}
-		label.SetText(headers[id.Col])
+		updateSortHeader(obj, consoleSortColumns, sort, id.Col-offset, onSort)
 	}
 
 	table.OnSelected = func(id widget.TableCellID) {
@@ -192,54 +304,60 @@ func buildConsolesTableWithSelection(w fyne.Window, conn *pgx.Conn, consoles []C
 		deleteBtn.Disable()
 	}
 
-	table.SetColumnWidth(0, 50)
-	table.SetColumnWidth(1, 300)
-	table.SetColumnWidth(2, 300)
-	table.SetColumnWidth(3, 50)
-	table.SetColumnWidth(4, 100)
-	table.ShowHeaderColumn = false
+	setTableLayout(table, len(consoles), thumbnails, 60, 300, 300, 70, 100)
+
+	// Keep the selected item selected after a sort or a search when it is still displayed
+	row := slices.IndexFunc(consoles, func(c Console) bool { return c.ConsoleID == *selectedConsoleID })
+	restoreTableSelection(table, row, selectedConsoleID, detailsBtn, editBtn, deleteBtn)
 
 	return table
 }
 
 // buildAccessoriesTableWithSelection creates the accessories table and tracks selection
-func buildAccessoriesTableWithSelection(w fyne.Window, conn *pgx.Conn, accessories []Accessory, detailsBtn, editBtn, deleteBtn *widget.Button, selectedAccessoryID *int, refreshFunc func()) *widget.Table {
+func buildAccessoriesTableWithSelection(w fyne.Window, store CollectionStore, accessories []Accessory, detailsBtn, editBtn, deleteBtn *widget.Button, selectedAccessoryID *int, sort tableSort, onSort func(column string), refreshFunc func()) *widget.Table {
+	thumbnails := showTableThumbnails()
+	offset := 0
+	if thumbnails {
+		offset = 1
+	}
+
 	table := widget.NewTableWithHeaders(
 		func() (int, int) {
-			return len(accessories), 6
-		},
-		func() fyne.CanvasObject {
-			return widget.NewLabel("")
+			return len(accessories), 6 + offset
 		},
+		newTableCell,
 		func(id widget.TableCellID, obj fyne.CanvasObject) {
-			label := obj.(*widget.Label)
 			accessory := accessories[id.Row]
+			if thumbnails && id.Col == 0 {
+				setTableCellThumbnail(obj, accessory.CoverThumb)
+				return
+			}
 
-			switch id.Col {
+			switch id.Col - offset {
 			case 0:
-				label.SetText(fmt.Sprintf("%d", accessory.AccessoryID))
+				setTableCellText(obj, fmt.Sprintf("%d", accessory.AccessoryID))
 			case 1:
-				label.SetText(accessory.Name)
+				setTableCellText(obj, lentBadge(accessory.Name, accessory.LentTo))
 			case 2:
 				if accessory.Color != nil {
-					label.SetText(*accessory.Color)
+					setTableCellText(obj, *accessory.Color)
 				} else {
-					label.SetText("")
+					setTableCellText(obj, "")
 				}
 			case 3:
-				label.SetText(accessory.TypeName)
+				setTableCellText(obj, accessory.TypeName)
 			case 4:
-				label.SetText(accessory.ManufacturerName)
+				setTableCellText(obj, accessory.ManufacturerName)
 			case 5:
-				label.SetText(conditionToStars(accessory.Condition))
+				setTableCellText(obj, conditionToStars(accessory.Condition))
 			}
 		},
 	)
 
+	// Clicking a header sorts by that column
+	table.CreateHeader = newSortHeader
 	table.UpdateHeader = func(id widget.TableCellID, obj fyne.CanvasObject) {
-		label := obj.(*widget.Label)
-		headers := []string{"ID", "Nom", "Couleur", "Type", "Fabricant", "État"}
-		label.SetText(headers[id.Col])
+		updateSortHeader(obj, accessorySortColumns, sort, id.Col-offset, onSort)
 	}
 
 	table.OnSelected = func(id widget.TableCellID) {
@@ -257,91 +375,51 @@ func buildAccessoriesTableWithSelection(w fyne.Window, conn *pgx.Conn, accessori
 		deleteBtn.Disable()
 	}
 
-	table.SetColumnWidth(0, 50)
-	table.SetColumnWidth(1, 300)
-	table.SetColumnWidth(2, 150)
-	table.SetColumnWidth(3, 150)
-	table.SetColumnWidth(4, 200)
-	table.SetColumnWidth(5, 100)
-	table.ShowHeaderColumn = false
+	setTableLayout(table, len(accessories), thumbnails, 60, 300, 150, 150, 200, 100)
+
+	// Keep the selected item selected after a sort or a search when it is still displayed
+	row := slices.IndexFunc(accessories, func(a Accessory) bool { return a.AccessoryID == *selectedAccessoryID })
+	restoreTableSelection(table, row, selectedAccessoryID, detailsBtn, editBtn, deleteBtn)
 
 	return table
 }
 
 // ========== FILTER FUNCTIONS ==========
 
-// filterGames returns games that match the search text (case-insensitive)
-// Searches: Title, Console Name, Genre Name
-func filterGames(games []Game, searchText string) []Game {
-	if searchText == "" {
-		return games
+// filterGames returns games that match a search query (see query.go)
+// Free text searches: Title, Console Name, Genre Name
+func filterGames(games []Game, searchText string) ([]Game, error) {
+	filter, err := compileSearchQuery(&gameQuerySchema, searchText)
+	if err != nil {
+		return nil, err
 	}
-
-	searchLower := strings.ToLower(searchText)
-	var filtered []Game
-
-	for _, game := range games {
-		// Search in: Title, Console, Genre
-		if strings.Contains(strings.ToLower(game.Title), searchLower) ||
-			strings.Contains(strings.ToLower(game.ConsoleName), searchLower) ||
-			strings.Contains(strings.ToLower(game.GenreName), searchLower) {
-			filtered = append(filtered, game)
-		}
-	}
-
-	return filtered
+	return filter.Apply(games), nil
 }
 
-// filterConsoles returns consoles that match the search text (case-insensitive)
-// Searches: Name, Manufacturer Name
-func filterConsoles(consoles []Console, searchText string) []Console {
-	if searchText == "" {
-		return consoles
+// filterConsoles returns consoles that match a search query (see query.go)
+// Free text searches: Name, Manufacturer Name
+func filterConsoles(consoles []Console, searchText string) ([]Console, error) {
+	filter, err := compileSearchQuery(&consoleQuerySchema, searchText)
+	if err != nil {
+		return nil, err
 	}
-
-	searchLower := strings.ToLower(searchText)
-	var filtered []Console
-
-	for _, console := range consoles {
-		// Search in: Name, Manufacturer
-		if strings.Contains(strings.ToLower(console.Name), searchLower) ||
-			strings.Contains(strings.ToLower(console.ManufacturerName), searchLower) {
-			filtered = append(filtered, console)
-		}
-	}
-
-	return filtered
+	return filter.Apply(consoles), nil
 }
 
-// filterAccessories returns accessories that match the search text (case-insensitive)
-// Searches: Name, Type, Manufacturer, Color
-func filterAccessories(accessories []Accessory, searchText string) []Accessory {
-	if searchText == "" {
-		return accessories
+// filterAccessories returns accessories that match a search query (see query.go)
+// Free text searches: Name, Type, Manufacturer, Color
+func filterAccessories(accessories []Accessory, searchText string) ([]Accessory, error) {
+	filter, err := compileSearchQuery(&accessoryQuerySchema, searchText)
+	if err != nil {
+		return nil, err
 	}
-
-	searchLower := strings.ToLower(searchText)
-	var filtered []Accessory
-
-	for _, accessory := range accessories {
-		// Search in: Name, Type, Manufacturer, Color
-		matchName := strings.Contains(strings.ToLower(accessory.Name), searchLower)
-		matchType := strings.Contains(strings.ToLower(accessory.TypeName), searchLower)
-		matchManufacturer := strings.Contains(strings.ToLower(accessory.ManufacturerName), searchLower)
-		matchColor := accessory.Color != nil && strings.Contains(strings.ToLower(*accessory.Color), searchLower)
-
-		if matchName || matchType || matchManufacturer || matchColor {
-			filtered = append(filtered, accessory)
-		}
-	}
-
-	return filtered
+	return filter.Apply(accessories), nil
 }
 
 // ========== TAB BUILDERS ==========
 
 // buildJeuxTab creates the complete "Jeux" tab content with search
-func buildJeuxTab(w fyne.Window, conn *pgx.Conn, games []Game, refreshFunc func()) fyne.CanvasObject {
+func buildJeuxTab(w fyne.Window, store CollectionStore, games []Game, refreshFunc func()) fyne.CanvasObject {
 	var selectedGameID int = -1
 	allGames := games
 
@@ -350,16 +428,16 @@ func buildJeuxTab(w fyne.Window, conn *pgx.Conn, games []Game, refreshFunc func(
 		if selectedGameID == -1 {
 			return
 		}
-		showGameDetailDialog(w, conn, selectedGameID, func() {
-			showEditGameDialog(w, conn, selectedGameID, refreshFunc)
-		})
+		showGameDetailDialog(w, store, selectedGameID, func() {
+			showEditGameDialog(w, store, selectedGameID, refreshFunc)
+		}, refreshFunc)
 	})
 
 	editBtn := widget.NewButton("Éditer", func() {
 		if selectedGameID == -1 {
 			return
 		}
-		showEditGameDialog(w, conn, selectedGameID, refreshFunc)
+		showEditGameDialog(w, store, selectedGameID, refreshFunc)
 	})
 
 	deleteBtn := widget.NewButton("Supprimer", func() {
@@ -377,15 +455,14 @@ func buildJeuxTab(w fyne.Window, conn *pgx.Conn, games []Game, refreshFunc func(
 
 		dialog.NewConfirm(
 			"Supprimer le jeu",
-			fmt.Sprintf("Êtes-vous sûr de vouloir supprimer '%s'? Cette action est irréversible.", gameName),
+			fmt.Sprintf("Êtes-vous sûr de vouloir supprimer '%s'? La suppression peut être annulée avec Ctrl+Z.", gameName),
 			func(confirmed bool) {
 				if confirmed {
-					err := deleteGame(conn, selectedGameID)
+					err := store.DeleteGame(selectedGameID)
 					if err != nil {
 						dialog.ShowError(fmt.Errorf("échec de suppression: %w", err), w)
 						return
 					}
-					dialog.ShowInformation("Succès", "Jeu supprimé avec succès!", w)
 					refreshFunc()
 				}
 			},
@@ -393,45 +470,120 @@ func buildJeuxTab(w fyne.Window, conn *pgx.Conn, games []Game, refreshFunc func(
 		).Show()
 	})
 
-	actionButtons := createActionButtons(w, conn, "game", detailsBtn, editBtn, deleteBtn, refreshFunc)
+	// Export the rows currently displayed (the search may hide some)
+	visibleGames := allGames
+	exportFunc := func() {
+		ids := make([]int, 0, len(visibleGames))
+		for _, item := range visibleGames {
+			ids = append(ids, item.GameID)
+		}
+		showExportDialog(w, store, "game", ids, len(allGames))
+	}
 
-	var tableContainer *fyne.Container
+	actionButtons := createActionButtons(w, store, "game", detailsBtn, editBtn, deleteBtn, refreshFunc, exportFunc)
 
-	rebuildTable := func(filteredGames []Game) {
-		selectedGameID = -1
-		detailsBtn.Disable()
-		editBtn.Disable()
-		deleteBtn.Disable()
+	// The games can be shown as a table or as a cover gallery, sorted by the columns
+	// clicked in the table headers
+	view := gamesViewPreference()
+	order := loadTableSort(prefGamesSort)
+	tableContainer := container.NewStack()
 
-		table := buildGamesTableWithSelection(w, conn, filteredGames, detailsBtn, editBtn, deleteBtn, &selectedGameID, refreshFunc)
-		tableContainer.Objects = []fyne.CanvasObject{table}
+	var rebuildTable func(filteredGames []Game)
+	onSort := func(column string) {
+		order = order.toggle(column)
+		order.save(prefGamesSort)
+		rebuildTable(visibleGames)
+	}
+
+	rebuildTable = func(filteredGames []Game) {
+		sortedGames := sortItems(filteredGames, gameSortColumns, order, gameDefaultSort)
+
+		var content fyne.CanvasObject
+		if view == gamesViewGallery {
+			content = buildGamesGalleryWithSelection(sortedGames, detailsBtn, editBtn, deleteBtn, &selectedGameID)
+		} else {
+			content = buildGamesTableWithSelection(w, store, sortedGames, detailsBtn, editBtn, deleteBtn, &selectedGameID, order, onSort, refreshFunc)
+		}
+		tableContainer.Objects = []fyne.CanvasObject{content}
 		tableContainer.Refresh()
 	}
 
-	searchBar := createSearchBar("Rechercher un jeu...", func(searchText string) {
-		filtered := filterGames(allGames, searchText)
-		rebuildTable(filtered)
+	// The facets of the filter panel narrow down the rows found by the search bar
+	searchResults := allGames
+	var facets *facetPanel[Game]
+	applyFilters := func() {
+		visibleGames = facets.Apply(searchResults)
+		facets.UpdateCounts(searchResults)
+		rebuildTable(visibleGames)
+	}
+	facets = newFacetPanel(store, gameFacets, allGames, applyFilters)
+
+	var syncSavedSearches func(query string)
+
+	searchBar, searchEntry := createSearchBar("Rechercher un jeu (ex: console:snes genre:rpg condition>=4)", func(searchText string) error {
+		if syncSavedSearches != nil {
+			syncSavedSearches(searchText)
+		}
+		filtered, err := filterGames(allGames, searchText)
+		if err != nil {
+			return err
+		}
+		searchResults = filtered
+		applyFilters()
+		return nil
 	})
 
+	// The button offers the other view
+	viewBtn := widget.NewButton("", nil)
+	updateViewBtn := func() {
+		if view == gamesViewGallery {
+			viewBtn.SetText("Tableau")
+			viewBtn.SetIcon(theme.ListIcon())
+		} else {
+			viewBtn.SetText("Galerie")
+			viewBtn.SetIcon(theme.GridIcon())
+		}
+	}
+	viewBtn.OnTapped = func() {
+		if view == gamesViewGallery {
+			view = gamesViewTable
+		} else {
+			view = gamesViewGallery
+		}
+		setGamesViewPreference(view)
+		updateViewBtn()
+		rebuildTable(visibleGames)
+	}
+	updateViewBtn()
+
 	toolbar := container.NewBorder(
 		nil, nil,
 		actionButtons,
-		nil,
+		container.NewHBox(viewBtn, facets.Button()),
 		searchBar,
 	)
 
-	table := buildGamesTableWithSelection(w, conn, games, detailsBtn, editBtn, deleteBtn, &selectedGameID, refreshFunc)
-	tableContainer = container.NewStack(table)
+	applyFilters()
+
+	// Saved searches, counted on all the games of the tab
+	savedSearches, syncSelection := createSavedSearchPanel(w, store, "game", searchEntry, func(query string) (int, error) {
+		matching, err := filterGames(allGames, query)
+		return len(matching), err
+	})
+	syncSavedSearches = syncSelection
+
+	split := container.NewHSplit(savedSearches, tableContainer)
+	split.Offset = savedSearchPanelOffset
 
 	return container.NewBorder(
 		toolbar,
-		nil, nil, nil,
-		tableContainer,
+		nil, nil, facets.Content(),
+		split,
 	)
 }
 
 // buildConsolesTab creates the complete "Consoles" tab content with search
-func buildConsolesTab(w fyne.Window, conn *pgx.Conn, consoles []Console, refreshFunc func()) fyne.CanvasObject {
+func buildConsolesTab(w fyne.Window, store CollectionStore, consoles []Console, refreshFunc func()) fyne.CanvasObject {
 	var selectedConsoleID int = -1
 	allConsoles := consoles
 
@@ -439,16 +591,16 @@ func buildConsolesTab(w fyne.Window, conn *pgx.Conn, consoles []Console, refresh
 		if selectedConsoleID == -1 {
 			return
 		}
-		showConsoleDetailDialog(w, conn, selectedConsoleID, func() {
-			showEditConsoleDialog(w, conn, selectedConsoleID, refreshFunc)
-		})
+		showConsoleDetailDialog(w, store, selectedConsoleID, func() {
+			showEditConsoleDialog(w, store, selectedConsoleID, refreshFunc)
+		}, refreshFunc)
 	})
 
 	editBtn := widget.NewButton("Éditer", func() {
 		if selectedConsoleID == -1 {
 			return
 		}
-		showEditConsoleDialog(w, conn, selectedConsoleID, refreshFunc)
+		showEditConsoleDialog(w, store, selectedConsoleID, refreshFunc)
 	})
 
 	deleteBtn := widget.NewButton("Supprimer", func() {
@@ -466,10 +618,10 @@ func buildConsolesTab(w fyne.Window, conn *pgx.Conn, consoles []Console, refresh
 
 		dialog.NewConfirm(
 			"Supprimer la console",
-			fmt.Sprintf("Êtes-vous sûr de vouloir supprimer '%s'? Cette action est irréversible.", consoleName),
+			fmt.Sprintf("Êtes-vous sûr de vouloir supprimer '%s'? La suppression peut être annulée avec Ctrl+Z.", consoleName),
 			func(confirmed bool) {
 				if confirmed {
-					err := deleteConsole(conn, selectedConsoleID)
+					err := store.DeleteConsole(selectedConsoleID)
 					if err != nil {
 						dialog.ShowError(fmt.Errorf("échec de suppression: %w", err), w)
 						return
@@ -482,45 +634,89 @@ func buildConsolesTab(w fyne.Window, conn *pgx.Conn, consoles []Console, refresh
 		).Show()
 	})
 
-	actionButtons := createActionButtons(w, conn, "console", detailsBtn, editBtn, deleteBtn, refreshFunc)
+	// Export the rows currently displayed (the search may hide some)
+	visibleConsoles := allConsoles
+	exportFunc := func() {
+		ids := make([]int, 0, len(visibleConsoles))
+		for _, item := range visibleConsoles {
+			ids = append(ids, item.ConsoleID)
+		}
+		showExportDialog(w, store, "console", ids, len(allConsoles))
+	}
 
-	var tableContainer *fyne.Container
+	actionButtons := createActionButtons(w, store, "console", detailsBtn, editBtn, deleteBtn, refreshFunc, exportFunc)
 
-	rebuildTable := func(filteredConsoles []Console) {
-		selectedConsoleID = -1
-		detailsBtn.Disable()
-		editBtn.Disable()
-		deleteBtn.Disable()
+	// Rows are sorted by the columns clicked in the table headers
+	order := loadTableSort(prefConsolesSort)
+	tableContainer := container.NewStack()
 
-		table := buildConsolesTableWithSelection(w, conn, filteredConsoles, detailsBtn, editBtn, deleteBtn, &selectedConsoleID, refreshFunc)
+	var rebuildTable func(filteredConsoles []Console)
+	onSort := func(column string) {
+		order = order.toggle(column)
+		order.save(prefConsolesSort)
+		rebuildTable(visibleConsoles)
+	}
+
+	rebuildTable = func(filteredConsoles []Console) {
+		sortedConsoles := sortItems(filteredConsoles, consoleSortColumns, order, consoleDefaultSort)
+		table := buildConsolesTableWithSelection(w, store, sortedConsoles, detailsBtn, editBtn, deleteBtn, &selectedConsoleID, order, onSort, refreshFunc)
 		tableContainer.Objects = []fyne.CanvasObject{table}
 		tableContainer.Refresh()
 	}
 
-	searchBar := createSearchBar("Rechercher une console...", func(searchText string) {
-		filtered := filterConsoles(allConsoles, searchText)
-		rebuildTable(filtered)
+	// The facets of the filter panel narrow down the rows found by the search bar
+	searchResults := allConsoles
+	var facets *facetPanel[Console]
+	applyFilters := func() {
+		visibleConsoles = facets.Apply(searchResults)
+		facets.UpdateCounts(searchResults)
+		rebuildTable(visibleConsoles)
+	}
+	facets = newFacetPanel(store, consoleFacets, allConsoles, applyFilters)
+
+	var syncSavedSearches func(query string)
+
+	searchBar, searchEntry := createSearchBar("Rechercher une console (ex: manufacturer:sega gen:5)", func(searchText string) error {
+		if syncSavedSearches != nil {
+			syncSavedSearches(searchText)
+		}
+		filtered, err := filterConsoles(allConsoles, searchText)
+		if err != nil {
+			return err
+		}
+		searchResults = filtered
+		applyFilters()
+		return nil
 	})
 
 	toolbar := container.NewBorder(
 		nil, nil,
 		actionButtons,
-		nil,
+		facets.Button(),
 		searchBar,
 	)
 
-	table := buildConsolesTableWithSelection(w, conn, consoles, detailsBtn, editBtn, deleteBtn, &selectedConsoleID, refreshFunc)
-	tableContainer = container.NewStack(table)
+	applyFilters()
+
+	// Saved searches, counted on all the consoles of the tab
+	savedSearches, syncSelection := createSavedSearchPanel(w, store, "console", searchEntry, func(query string) (int, error) {
+		matching, err := filterConsoles(allConsoles, query)
+		return len(matching), err
+	})
+	syncSavedSearches = syncSelection
+
+	split := container.NewHSplit(savedSearches, tableContainer)
+	split.Offset = savedSearchPanelOffset
 
 	return container.NewBorder(
 		toolbar,
-		nil, nil, nil,
-		tableContainer,
+		nil, nil, facets.Content(),
+		split,
 	)
 }
 
 // buildAccessoiresTab creates the complete "Accessoires" tab content with search
-func buildAccessoiresTab(w fyne.Window, conn *pgx.Conn, accessories []Accessory, refreshFunc func()) fyne.CanvasObject {
+func buildAccessoiresTab(w fyne.Window, store CollectionStore, accessories []Accessory, refreshFunc func()) fyne.CanvasObject {
 	var selectedAccessoryID int = -1
 	allAccessories := accessories
 
@@ -528,16 +724,16 @@ func buildAccessoiresTab(w fyne.Window, conn *pgx.Conn, accessories []Accessory,
 		if selectedAccessoryID == -1 {
 			return
 		}
-		showAccessoryDetailDialog(w, conn, selectedAccessoryID, func() {
-			showEditAccessoryDialog(w, conn, selectedAccessoryID, refreshFunc)
-		})
+		showAccessoryDetailDialog(w, store, selectedAccessoryID, func() {
+			showEditAccessoryDialog(w, store, selectedAccessoryID, refreshFunc)
+		}, refreshFunc)
 	})
 
 	editBtn := widget.NewButton("Éditer", func() {
 		if selectedAccessoryID == -1 {
 			return
 		}
-		showEditAccessoryDialog(w, conn, selectedAccessoryID, refreshFunc)
+		showEditAccessoryDialog(w, store, selectedAccessoryID, refreshFunc)
 	})
 
 	deleteBtn := widget.NewButton("Supprimer", func() {
@@ -555,15 +751,14 @@ func buildAccessoiresTab(w fyne.Window, conn *pgx.Conn, accessories []Accessory,
 
 		dialog.NewConfirm(
 			"Supprimer l'accessoire",
-			fmt.Sprintf("Êtes-vous sûr de vouloir supprimer '%s'? Cette action est irréversible.", accessoryName),
+			fmt.Sprintf("Êtes-vous sûr de vouloir supprimer '%s'? La suppression peut être annulée avec Ctrl+Z.", accessoryName),
 			func(confirmed bool) {
 				if confirmed {
-					err := deleteAccessory(conn, selectedAccessoryID)
+					err := store.DeleteAccessory(selectedAccessoryID)
 					if err != nil {
 						dialog.ShowError(fmt.Errorf("échec de suppression: %w", err), w)
 						return
 					}
-					dialog.ShowInformation("Succès", "Accessoire supprimé avec succès!", w)
 					refreshFunc()
 				}
 			},
@@ -571,39 +766,83 @@ func buildAccessoiresTab(w fyne.Window, conn *pgx.Conn, accessories []Accessory,
 		).Show()
 	})
 
-	actionButtons := createActionButtons(w, conn, "accessory", detailsBtn, editBtn, deleteBtn, refreshFunc)
+	// Export the rows currently displayed (the search may hide some)
+	visibleAccessories := allAccessories
+	exportFunc := func() {
+		ids := make([]int, 0, len(visibleAccessories))
+		for _, item := range visibleAccessories {
+			ids = append(ids, item.AccessoryID)
+		}
+		showExportDialog(w, store, "accessory", ids, len(allAccessories))
+	}
+
+	actionButtons := createActionButtons(w, store, "accessory", detailsBtn, editBtn, deleteBtn, refreshFunc, exportFunc)
 
-	var tableContainer *fyne.Container
+	// Rows are sorted by the columns clicked in the table headers
+	order := loadTableSort(prefAccessoriesSort)
+	tableContainer := container.NewStack()
 
-	rebuildTable := func(filteredAccessories []Accessory) {
-		selectedAccessoryID = -1
-		detailsBtn.Disable()
-		editBtn.Disable()
-		deleteBtn.Disable()
+	var rebuildTable func(filteredAccessories []Accessory)
+	onSort := func(column string) {
+		order = order.toggle(column)
+		order.save(prefAccessoriesSort)
+		rebuildTable(visibleAccessories)
+	}
 
-		table := buildAccessoriesTableWithSelection(w, conn, filteredAccessories, detailsBtn, editBtn, deleteBtn, &selectedAccessoryID, refreshFunc)
+	rebuildTable = func(filteredAccessories []Accessory) {
+		sortedAccessories := sortItems(filteredAccessories, accessorySortColumns, order, accessoryDefaultSort)
+		table := buildAccessoriesTableWithSelection(w, store, sortedAccessories, detailsBtn, editBtn, deleteBtn, &selectedAccessoryID, order, onSort, refreshFunc)
 		tableContainer.Objects = []fyne.CanvasObject{table}
 		tableContainer.Refresh()
 	}
 
-	searchBar := createSearchBar("Rechercher un accessoire...", func(searchText string) {
-		filtered := filterAccessories(allAccessories, searchText)
-		rebuildTable(filtered)
+	// The facets of the filter panel narrow down the rows found by the search bar
+	searchResults := allAccessories
+	var facets *facetPanel[Accessory]
+	applyFilters := func() {
+		visibleAccessories = facets.Apply(searchResults)
+		facets.UpdateCounts(searchResults)
+		rebuildTable(visibleAccessories)
+	}
+	facets = newFacetPanel(store, accessoryFacets, allAccessories, applyFilters)
+
+	var syncSavedSearches func(query string)
+
+	searchBar, searchEntry := createSearchBar("Rechercher un accessoire (ex: type:manette console:n64)", func(searchText string) error {
+		if syncSavedSearches != nil {
+			syncSavedSearches(searchText)
+		}
+		filtered, err := filterAccessories(allAccessories, searchText)
+		if err != nil {
+			return err
+		}
+		searchResults = filtered
+		applyFilters()
+		return nil
 	})
 
 	toolbar := container.NewBorder(
 		nil, nil,
 		actionButtons,
-		nil,
+		facets.Button(),
 		searchBar,
 	)
 
-	table := buildAccessoriesTableWithSelection(w, conn, accessories, detailsBtn, editBtn, deleteBtn, &selectedAccessoryID, refreshFunc)
-	tableContainer = container.NewStack(table)
+	applyFilters()
+
+	// Saved searches, counted on all the accessories of the tab
+	savedSearches, syncSelection := createSavedSearchPanel(w, store, "accessory", searchEntry, func(query string) (int, error) {
+		matching, err := filterAccessories(allAccessories, query)
+		return len(matching), err
+	})
+	syncSavedSearches = syncSelection
+
+	split := container.NewHSplit(savedSearches, tableContainer)
+	split.Offset = savedSearchPanelOffset
 
 	return container.NewBorder(
 		toolbar,
-		nil, nil, nil,
-		tableContainer,
+		nil, nil, facets.Content(),
+		split,
 	)
 }
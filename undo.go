@@ -0,0 +1,291 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"reflect"
+	"sync"
+)
+
+// ========== UNDO / REDO ==========
+// The window talks to the store through an undoStore, which records every creation, update
+// and deletion of a game, console or accessory. Each change keeps a snapshot of the rows of
+// the item before and after it: its own row, its credits or compatible consoles, and what is
+// deleted along with it (copies, pictures, loans, market values). Undoing writes the "before"
+// rows back with their IDs, redoing the "after" ones. A change is only undone while the item
+// is still as the change left it, so an edit made elsewhere (or not recorded, like a loan)
+// is never overwritten.
+
+// maxUndoEntries bounds the history (the snapshots of deleted items hold their picture files)
+const maxUndoEntries = 50
+
+// itemRowSet is a set of rows belonging to an item, selected by a condition on its ID ($1)
+type itemRowSet struct {
+	Table string
+	Where string
+}
+
+// itemRowSets lists the rows making up each type of item, parents first
+var itemRowSets = map[string][]itemRowSet{
+	"game": {
+		{"games", "game_id = $1"},
+		{"game_developers", "game_id = $1"},
+		{"game_composers", "game_id = $1"},
+		{"game_publishers", "game_id = $1"},
+		{"game_producers", "game_id = $1"},
+		{"game_copies", "game_id = $1"},
+		{"copy_components", "copy_id IN (SELECT copy_id FROM game_copies WHERE game_id = $1)"},
+		{"images", "item_type = 'game' AND item_id = $1"},
+		{"market_values", "item_type = 'game' AND item_id = $1"},
+		{"loans", "item_type = 'game' AND item_id = $1"},
+	},
+	"console": {
+		{"consoles", "console_id = $1"},
+		{"accessory_consoles", "console_id = $1"},
+		{"images", "item_type = 'console' AND item_id = $1"},
+		{"market_values", "item_type = 'console' AND item_id = $1"},
+		{"loans", "item_type = 'console' AND item_id = $1"},
+	},
+	"accessory": {
+		{"accessories", "accessory_id = $1"},
+		{"accessory_consoles", "accessory_id = $1"},
+		{"images", "item_type = 'accessory' AND item_id = $1"},
+		{"market_values", "item_type = 'accessory' AND item_id = $1"},
+		{"loans", "item_type = 'accessory' AND item_id = $1"},
+	},
+}
+
+// ItemSnapshot holds the rows of an item at a point in time (no rows: the item doesn't exist)
+type ItemSnapshot struct {
+	Tables map[string]*backupTable
+	Media  map[string][]byte // Picture files of the images rows by hash + extension, when asked for
+}
+
+// exists tells whether the item existed when the snapshot was taken
+func (s *ItemSnapshot) exists(itemType string) bool {
+	main := s.Tables[itemRowSets[itemType][0].Table]
+	return main != nil && len(main.Rows) > 0
+}
+
+// sameRows tells whether two snapshots hold the same rows
+func (s *ItemSnapshot) sameRows(other *ItemSnapshot) bool {
+	return reflect.DeepEqual(s.Tables, other.Tables)
+}
+
+// name returns the title or name of the item, "" if it doesn't exist
+func (s *ItemSnapshot) name(itemType string) string {
+	main := s.Tables[itemRowSets[itemType][0].Table]
+	if main == nil || len(main.Rows) == 0 {
+		return ""
+	}
+	for i, column := range main.Columns {
+		if column == "title" || column == "name" {
+			if name, ok := main.Rows[0][i].(string); ok {
+				return name
+			}
+		}
+	}
+	return ""
+}
+
+// undoEntry is a recorded change of an item
+type undoEntry struct {
+	ItemType string
+	ItemID   int
+	Label    string // "Suppression du jeu « Chrono Trigger »"
+	Before   *ItemSnapshot
+	After    *ItemSnapshot
+}
+
+// isDeletion tells whether the change removed the item
+func (e *undoEntry) isDeletion() bool {
+	return e.Before.exists(e.ItemType) && !e.After.exists(e.ItemType)
+}
+
+// undoItemNames gives the noun of each item type, with its article
+var undoItemNames = map[string]string{
+	"game":      "du jeu",
+	"console":   "de la console",
+	"accessory": "de l'accessoire",
+}
+
+// undoStore is a CollectionStore that records the changes of games, consoles and accessories
+type undoStore struct {
+	CollectionStore
+
+	mu     sync.Mutex
+	done   []*undoEntry // Oldest first
+	undone []*undoEntry // Redo stack, last undone last
+
+	// onChange is called with each recorded change, and with nil when the history is cleared
+	onChange func(recorded *undoEntry)
+}
+
+func newUndoStore(store CollectionStore) *undoStore {
+	return &undoStore{CollectionStore: store}
+}
+
+// track runs a change of an item and records it
+// itemID is 0 for a creation, change returns the ID of the item. A deletion removes the
+// picture files nothing else uses, so its snapshot keeps them. Recording is best effort:
+// a failed snapshot never fails the change itself.
+func (u *undoStore) track(itemType string, itemID int, deletion bool, change func() (int, error)) (int, error) {
+	before := &ItemSnapshot{}
+	if itemID != 0 {
+		var err error
+		if before, err = u.SnapshotItem(itemType, itemID, deletion); err != nil {
+			log.Println("Unable to record change for undo:", err)
+			return change()
+		}
+	}
+
+	id, err := change()
+	if err != nil {
+		return id, err
+	}
+	after, err := u.SnapshotItem(itemType, id, false)
+	if err != nil {
+		log.Println("Unable to record change for undo:", err)
+		return id, nil
+	}
+	if after.sameRows(before) {
+		return id, nil // Saved without changes
+	}
+
+	entry := &undoEntry{ItemType: itemType, ItemID: id, Before: before, After: after}
+	switch {
+	case !before.exists(itemType):
+		entry.Label = fmt.Sprintf("Ajout %s « %s »", undoItemNames[itemType], after.name(itemType))
+	case !after.exists(itemType):
+		entry.Label = fmt.Sprintf("Suppression %s « %s »", undoItemNames[itemType], before.name(itemType))
+	default:
+		entry.Label = fmt.Sprintf("Modification %s « %s »", undoItemNames[itemType], after.name(itemType))
+	}
+
+	u.mu.Lock()
+	u.done = append(u.done, entry)
+	if len(u.done) > maxUndoEntries {
+		u.done = u.done[len(u.done)-maxUndoEntries:]
+	}
+	u.undone = nil // A new change ends the redo history
+	u.mu.Unlock()
+
+	if u.onChange != nil {
+		u.onChange(entry)
+	}
+	return id, nil
+}
+
+// ========== Recorded changes ==========
+
+func (u *undoStore) SaveGame(game *Game) (int, error) {
+	return u.track("game", game.GameID, false, func() (int, error) { return u.CollectionStore.SaveGame(game) })
+}
+
+func (u *undoStore) DeleteGame(gameID int) error {
+	_, err := u.track("game", gameID, true, func() (int, error) { return gameID, u.CollectionStore.DeleteGame(gameID) })
+	return err
+}
+
+func (u *undoStore) SaveConsole(console *Console) (int, error) {
+	return u.track("console", console.ConsoleID, false, func() (int, error) { return u.CollectionStore.SaveConsole(console) })
+}
+
+func (u *undoStore) DeleteConsole(consoleID int) error {
+	_, err := u.track("console", consoleID, true, func() (int, error) { return consoleID, u.CollectionStore.DeleteConsole(consoleID) })
+	return err
+}
+
+func (u *undoStore) SaveAccessory(accessory *Accessory) (int, error) {
+	return u.track("accessory", accessory.AccessoryID, false, func() (int, error) { return u.CollectionStore.SaveAccessory(accessory) })
+}
+
+func (u *undoStore) DeleteAccessory(accessoryID int) error {
+	_, err := u.track("accessory", accessoryID, true, func() (int, error) { return accessoryID, u.CollectionStore.DeleteAccessory(accessoryID) })
+	return err
+}
+
+func (u *undoStore) MarkAsBought(itemType string, itemID int, purchase Purchase) error {
+	_, err := u.track(itemType, itemID, false, func() (int, error) { return itemID, u.CollectionStore.MarkAsBought(itemType, itemID, purchase) })
+	return err
+}
+
+// ========== Undo / Redo ==========
+
+// clear forgets the whole history, e.g. once a backup replaced the collection
+func (u *undoStore) clear() {
+	u.mu.Lock()
+	u.done, u.undone = nil, nil
+	u.mu.Unlock()
+
+	if u.onChange != nil {
+		u.onChange(nil)
+	}
+}
+
+// lastEntry returns the change Undo would revert (nil if none)
+func (u *undoStore) lastEntry() *undoEntry {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if len(u.done) == 0 {
+		return nil
+	}
+	return u.done[len(u.done)-1]
+}
+
+// undoLabel and redoLabel describe the change Undo and Redo would apply ("" if none)
+func (u *undoStore) undoLabel() string {
+	if entry := u.lastEntry(); entry != nil {
+		return entry.Label
+	}
+	return ""
+}
+
+func (u *undoStore) redoLabel() string {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if len(u.undone) == 0 {
+		return ""
+	}
+	return u.undone[len(u.undone)-1].Label
+}
+
+// Undo reverts the last recorded change and returns it (nil if there is nothing to undo)
+func (u *undoStore) Undo() (*undoEntry, error) {
+	return u.step(&u.done, &u.undone, func(e *undoEntry) (*ItemSnapshot, *ItemSnapshot) { return e.After, e.Before })
+}
+
+// Redo applies the last undone change again and returns it (nil if there is nothing to redo)
+func (u *undoStore) Redo() (*undoEntry, error) {
+	return u.step(&u.undone, &u.done, func(e *undoEntry) (*ItemSnapshot, *ItemSnapshot) { return e.Before, e.After })
+}
+
+// step moves the last entry of from to to, writing its target snapshot if the item is still
+// in the expected state. An entry whose item changed since is dropped, one that failed on a
+// database error is kept to be tried again.
+func (u *undoStore) step(from, to *[]*undoEntry, states func(*undoEntry) (expected, target *ItemSnapshot)) (*undoEntry, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if len(*from) == 0 {
+		return nil, nil
+	}
+	entry := (*from)[len(*from)-1]
+	*from = (*from)[:len(*from)-1]
+
+	expected, target := states(entry)
+	current, err := u.SnapshotItem(entry.ItemType, entry.ItemID, false)
+	if err != nil {
+		*from = append(*from, entry) // Keep it for when the database is back
+		return nil, err
+	}
+	if !current.sameRows(expected) {
+		return nil, fmt.Errorf("« %s » impossible: l'élément a été modifié depuis", entry.Label)
+	}
+	if err := u.RestoreItem(entry.ItemType, entry.ItemID, target); err != nil {
+		*from = append(*from, entry)
+		return nil, fmt.Errorf("« %s » impossible: %w", entry.Label, err)
+	}
+
+	*to = append(*to, entry)
+	return entry, nil
+}
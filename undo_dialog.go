@@ -0,0 +1,103 @@
+package main
+
+import (
+	"errors"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/widget"
+)
+
+// ========== UNDO MENU ==========
+
+// undoToastDuration is how long the "Annuler" button stays after a deletion
+const undoToastDuration = 8 * time.Second
+
+// newEditMenu creates the Édition menu and the Ctrl+Z / Ctrl+Y shortcuts of the undo history
+// refresh reloads the tab of an item type, refreshMenu redraws the main menu once the labels change.
+// The shortcuts are registered on the canvas rather than on the menu items, so a focused text
+// field keeps its own Ctrl+Z.
+func newEditMenu(w fyne.Window, undo *undoStore, refresh func(itemType string), refreshMenu func()) *fyne.Menu {
+	undoItem := fyne.NewMenuItem("Annuler", nil)
+	redoItem := fyne.NewMenuItem("Rétablir", nil)
+
+	updateItems := func() {
+		label := undo.undoLabel()
+		undoItem.Label, undoItem.Disabled = "Annuler", label == ""
+		if label != "" {
+			undoItem.Label = "Annuler: " + label
+		}
+		label = undo.redoLabel()
+		redoItem.Label, redoItem.Disabled = "Rétablir", label == ""
+		if label != "" {
+			redoItem.Label = "Rétablir: " + label
+		}
+		refreshMenu()
+	}
+
+	apply := func(step func() (*undoEntry, error)) {
+		entry, err := step()
+		if err != nil {
+			dialog.ShowError(err, w)
+		}
+		if entry != nil {
+			refresh(entry.ItemType)
+		}
+		updateItems()
+	}
+	undoItem.Action = func() { apply(undo.Undo) }
+	redoItem.Action = func() { apply(undo.Redo) }
+
+	w.Canvas().AddShortcut(&fyne.ShortcutUndo{}, func(fyne.Shortcut) { apply(undo.Undo) })
+	w.Canvas().AddShortcut(&fyne.ShortcutRedo{}, func(fyne.Shortcut) { apply(undo.Redo) })
+	w.Canvas().AddShortcut(&desktop.CustomShortcut{KeyName: fyne.KeyZ, Modifier: fyne.KeyModifierShortcutDefault | fyne.KeyModifierShift},
+		func(fyne.Shortcut) { apply(undo.Redo) })
+
+	undo.onChange = func(entry *undoEntry) {
+		updateItems()
+		if entry == nil || !entry.isDeletion() {
+			return
+		}
+		showUndoToast(w, entry.Label, func() {
+			// The toast undoes its own deletion, not whatever was changed since
+			if undo.lastEntry() != entry {
+				dialog.ShowError(errors.New("d'autres modifications ont été faites depuis, utilisez le menu Édition"), w)
+				return
+			}
+			apply(undo.Undo)
+		})
+	}
+
+	updateItems()
+	return fyne.NewMenu("Édition", undoItem, redoItem)
+}
+
+// undoToast is the toast currently shown, replaced by the next one
+var undoToast *widget.PopUp
+
+// showUndoToast shows a message with an "Annuler" button at the bottom of the window for a while
+func showUndoToast(w fyne.Window, message string, onUndo func()) {
+	if undoToast != nil {
+		undoToast.Hide()
+	}
+
+	var toast *widget.PopUp
+	undoBtn := widget.NewButton("Annuler", func() {
+		toast.Hide()
+		onUndo()
+	})
+	undoBtn.Importance = widget.HighImportance
+	toast = widget.NewPopUp(container.NewHBox(widget.NewLabel(message), undoBtn), w.Canvas())
+
+	size := toast.MinSize()
+	canvasSize := w.Canvas().Size()
+	toast.ShowAtPosition(fyne.NewPos((canvasSize.Width-size.Width)/2, canvasSize.Height-size.Height-40))
+	undoToast = toast
+
+	time.AfterFunc(undoToastDuration, func() {
+		fyne.Do(toast.Hide)
+	})
+}
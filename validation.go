@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ========== VALIDATION ==========
+// Rules checked before a game, console or accessory is saved, whether it comes from a form
+// or from the REST API (see api.go). The forms already restrict most values through their
+// widgets; the API receives them as is, so every rule is spelled out here.
+
+// maxHoursPlayed is the largest value of games.hours_played, a NUMERIC(7, 1)
+const maxHoursPlayed = 999999.9
+
+// validateGame checks a game before it is saved
+func validateGame(game *Game) error {
+	if strings.TrimSpace(game.Title) == "" {
+		return fmt.Errorf("titre requis")
+	}
+	if game.ConsoleID == nil {
+		return fmt.Errorf("plateforme requise")
+	}
+	if err := validateOwnership(game.Condition, game.WishPriority); err != nil {
+		return err
+	}
+
+	if game.PlayStatus != nil && !slices.Contains(playStatuses, *game.PlayStatus) {
+		return fmt.Errorf("progression inconnue '%s' (%s attendu)", *game.PlayStatus, strings.Join(playStatuses, ", "))
+	}
+	if game.PlayStartedOn != nil && game.PlayFinishedOn != nil && game.PlayFinishedOn.Before(*game.PlayStartedOn) {
+		return fmt.Errorf("la date de fin est avant la date de début")
+	}
+	if game.HoursPlayed != nil && (*game.HoursPlayed < 0 || *game.HoursPlayed > maxHoursPlayed) {
+		return fmt.Errorf("temps de jeu invalide (0 à %.1f h attendu)", maxHoursPlayed)
+	}
+	if game.PersonalRating != nil && (*game.PersonalRating < 1 || *game.PersonalRating > 10) {
+		return fmt.Errorf("note personnelle invalide (1 à 10 attendu)")
+	}
+	return nil
+}
+
+// validateConsole checks a console before it is saved
+func validateConsole(console *Console) error {
+	if strings.TrimSpace(console.Name) == "" {
+		return fmt.Errorf("nom requis")
+	}
+	if console.TypeID == nil {
+		return fmt.Errorf("type requis")
+	}
+	if console.ManufacturerID == nil {
+		return fmt.Errorf("fabricant requis")
+	}
+	return validateOwnership(console.Condition, console.WishPriority)
+}
+
+// validateAccessory checks an accessory before it is saved
+func validateAccessory(accessory *Accessory) error {
+	if strings.TrimSpace(accessory.Name) == "" {
+		return fmt.Errorf("nom requis")
+	}
+	if accessory.TypeID == nil {
+		return fmt.Errorf("type requis")
+	}
+	if accessory.Quantity < 1 {
+		return fmt.Errorf("quantité invalide (1 au moins)")
+	}
+	return validateOwnership(accessory.Condition, accessory.WishPriority)
+}
+
+// validateOwnership checks the condition and wishlist priority shared by every item
+func validateOwnership(condition, wishPriority *int) error {
+	if condition != nil && (*condition < 1 || *condition > 5) {
+		return fmt.Errorf("état invalide (1 à 5 attendu)")
+	}
+	if wishPriority != nil && (*wishPriority < WishPriorityHigh || *wishPriority > WishPriorityLow) {
+		return fmt.Errorf("priorité invalide (1 à 3 attendu)")
+	}
+	return nil
+}
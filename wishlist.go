@@ -0,0 +1,459 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// ========== WISHLIST ==========
+// Games, consoles and accessories that are not owned are wanted: they are listed in the
+// "Liste de souhaits" tab instead of the collection tabs, with a target price, a priority
+// and the preferred region or edition. "Je l'ai acheté" records the purchase and moves
+// the item into the collection.
+
+// prefWishlistSort is the preference key of the sort of the wishlist table
+const prefWishlistSort = "wishlistSort"
+
+// wishPriorityLabels are the priorities offered in the forms, WishPriorityHigh first
+var wishPriorityLabels = []string{"Haute", "Moyenne", "Basse"}
+
+// wishPriorityText returns the label of a priority, "" when not set
+func wishPriorityText(priority *int) string {
+	if priority == nil || *priority < WishPriorityHigh || *priority > WishPriorityLow {
+		return ""
+	}
+	return wishPriorityLabels[*priority-WishPriorityHigh]
+}
+
+// wishlistItem is a line of the wishlist table
+type wishlistItem struct {
+	ItemType    string // "game", "console" or "accessory"
+	ItemID      int
+	Name        string
+	Platform    string // Console of a game, manufacturer of a console, consoles of an accessory
+	TargetPrice *float64
+	Priority    *int
+	Edition     *string
+}
+
+var wishlistItemTypeLabels = map[string]string{
+	"game":      "Jeu",
+	"console":   "Console",
+	"accessory": "Accessoire",
+}
+
+var wishlistSortColumns = []sortColumn[wishlistItem]{
+	{Key: "type", Label: "Type", Text: func(i *wishlistItem) string { return wishlistItemTypeLabels[i.ItemType] }},
+	{Key: "name", Label: "Nom", Text: func(i *wishlistItem) string { return i.Name }},
+	{Key: "platform", Label: "Plateforme", Text: func(i *wishlistItem) string { return i.Platform }},
+	{Key: "priority", Label: "Priorité", Number: func(i *wishlistItem) (float64, bool) { return sortNumber(i.Priority) }},
+	{Key: "target_price", Label: "Prix cible", Number: func(i *wishlistItem) (float64, bool) {
+		if i.TargetPrice == nil {
+			return 0, false
+		}
+		return *i.TargetPrice, true
+	}},
+	{Key: "edition", Label: "Région / édition", Text: func(i *wishlistItem) string { return sortText(i.Edition) }},
+}
+
+// Highest priority first, then by name
+var wishlistDefaultSort = tableSort{{Column: "priority"}, {Column: "name"}, {Column: "type"}}
+
+// collectWishlist returns the items that are not owned
+func collectWishlist(games []Game, consoles []Console, accessories []Accessory) []wishlistItem {
+	var items []wishlistItem
+	for _, g := range games {
+		if !g.Owned {
+			items = append(items, wishlistItem{ItemType: "game", ItemID: g.GameID, Name: g.Title, Platform: g.ConsoleName,
+				TargetPrice: g.TargetPrice, Priority: g.WishPriority, Edition: g.WishEdition})
+		}
+	}
+	for _, c := range consoles {
+		if !c.Owned {
+			items = append(items, wishlistItem{ItemType: "console", ItemID: c.ConsoleID, Name: c.Name, Platform: c.ManufacturerName,
+				TargetPrice: c.TargetPrice, Priority: c.WishPriority, Edition: c.WishEdition})
+		}
+	}
+	for _, a := range accessories {
+		if !a.Owned {
+			items = append(items, wishlistItem{ItemType: "accessory", ItemID: a.AccessoryID, Name: a.Name, Platform: strings.Join(a.Consoles, ", "),
+				TargetPrice: a.TargetPrice, Priority: a.WishPriority, Edition: a.WishEdition})
+		}
+	}
+	return items
+}
+
+// ownedOnly returns the items of the collection, leaving out the wishlist
+func ownedOnly[T any](items []T, owned func(item *T) bool) []T {
+	var result []T
+	for i := range items {
+		if owned(&items[i]) {
+			result = append(result, items[i])
+		}
+	}
+	return result
+}
+
+// ========== Form fields ==========
+
+// wishlistFields are the wishlist widgets of the game, console and accessory forms
+// They are only shown while "Possédé" is unchecked.
+type wishlistFields struct {
+	prioritySelect   *widget.Select
+	targetPriceEntry *widget.Entry
+	editionEntry     *widget.Entry
+	section          *fyne.Container
+}
+
+// newWishlistFields creates the wishlist widgets filled with the current values
+// It takes over ownedCheck.OnChanged to show or hide the section.
+func newWishlistFields(ownedCheck *widget.Check, targetPrice *float64, priority *int, edition *string) *wishlistFields {
+	f := &wishlistFields{}
+
+	f.prioritySelect = widget.NewSelect(wishPriorityLabels, nil)
+	f.prioritySelect.PlaceHolder = "Priorité"
+	if label := wishPriorityText(priority); label != "" {
+		f.prioritySelect.SetSelected(label)
+	}
+
+	f.targetPriceEntry = widget.NewEntry()
+	f.targetPriceEntry.SetPlaceHolder("Prix cible")
+	if targetPrice != nil {
+		f.targetPriceEntry.SetText(fmt.Sprintf("%.2f", *targetPrice))
+	}
+
+	f.editionEntry = widget.NewEntry()
+	f.editionEntry.SetPlaceHolder("ex: PAL, version japonaise, édition limitée")
+	if edition != nil {
+		f.editionEntry.SetText(*edition)
+	}
+
+	f.section = container.NewVBox(
+		widget.NewSeparator(),
+		widget.NewLabel("Liste de souhaits"),
+		widget.NewLabel("Priorité:"),
+		f.prioritySelect,
+		widget.NewLabel("Prix cible:"),
+		f.targetPriceEntry,
+		widget.NewLabel("Région / édition recherchée:"),
+		f.editionEntry,
+	)
+
+	showSection := func(owned bool) {
+		if owned {
+			f.section.Hide()
+		} else {
+			f.section.Show()
+		}
+	}
+	showSection(ownedCheck.Checked)
+	ownedCheck.OnChanged = showSection
+
+	return f
+}
+
+// values returns the target price, priority and edition entered in the form
+func (f *wishlistFields) values() (targetPrice *float64, priority *int, edition *string) {
+	if index := slices.Index(wishPriorityLabels, f.prioritySelect.Selected); index >= 0 {
+		priority = intPtr(WishPriorityHigh + index)
+	}
+	return parsePriceEntry(f.targetPriceEntry.Text), priority, optionalText(strings.TrimSpace(f.editionEntry.Text))
+}
+
+// wishlistDetails returns the wishlist lines of a detail dialog, nothing for an owned item
+func wishlistDetails(owned bool, targetPrice *float64, priority *int, edition *string) []fyne.CanvasObject {
+	if owned {
+		return nil
+	}
+
+	content := []fyne.CanvasObject{widget.NewSeparator(), widget.NewLabel("Liste de souhaits")}
+	if label := wishPriorityText(priority); label != "" {
+		content = append(content, widget.NewLabel(fmt.Sprintf("Priorité: %s", label)))
+	}
+	if targetPrice != nil {
+		content = append(content, widget.NewLabel(fmt.Sprintf("Prix cible: %.2f", *targetPrice)))
+	}
+	if edition != nil {
+		content = append(content, widget.NewLabel(fmt.Sprintf("Région / édition: %s", *edition)))
+	}
+	return content
+}
+
+// ========== Tab ==========
+
+// buildWishlistTable creates the wishlist table and tracks the selected row
+func buildWishlistTable(items []wishlistItem, selected *wishlistItem, buttons []*widget.Button, sort tableSort, onSort func(column string)) *widget.Table {
+	setButtons := func(enabled bool) {
+		for _, btn := range buttons {
+			if enabled {
+				btn.Enable()
+			} else {
+				btn.Disable()
+			}
+		}
+	}
+
+	table := widget.NewTableWithHeaders(
+		func() (int, int) {
+			return len(items), len(wishlistSortColumns)
+		},
+		newTableCell,
+		func(id widget.TableCellID, obj fyne.CanvasObject) {
+			item := items[id.Row]
+			switch id.Col {
+			case 0:
+				setTableCellText(obj, wishlistItemTypeLabels[item.ItemType])
+			case 1:
+				setTableCellText(obj, item.Name)
+			case 2:
+				setTableCellText(obj, item.Platform)
+			case 3:
+				setTableCellText(obj, wishPriorityText(item.Priority))
+			case 4:
+				if item.TargetPrice != nil {
+					setTableCellText(obj, fmt.Sprintf("%.2f", *item.TargetPrice))
+				} else {
+					setTableCellText(obj, "")
+				}
+			case 5:
+				setTableCellText(obj, sortText(item.Edition))
+			}
+		},
+	)
+
+	table.CreateHeader = newSortHeader
+	table.UpdateHeader = func(id widget.TableCellID, obj fyne.CanvasObject) {
+		updateSortHeader(obj, wishlistSortColumns, sort, id.Col, onSort)
+	}
+
+	table.OnSelected = func(id widget.TableCellID) {
+		*selected = items[id.Row]
+		setButtons(true)
+	}
+	table.OnUnselected = func(id widget.TableCellID) {
+		*selected = wishlistItem{}
+		setButtons(false)
+	}
+
+	for col, width := range []float32{100, 300, 200, 90, 90, 220} {
+		table.SetColumnWidth(col, width)
+	}
+
+	// Keep the selected item selected after a sort when it is still listed
+	row := slices.IndexFunc(items, func(i wishlistItem) bool {
+		return i.ItemType == selected.ItemType && i.ItemID == selected.ItemID
+	})
+	if row >= 0 {
+		table.Select(widget.TableCellID{Row: row, Col: 0})
+	} else {
+		*selected = wishlistItem{}
+		setButtons(false)
+	}
+
+	return table
+}
+
+// buildWishlistTab creates the "Liste de souhaits" tab
+// onChanged reloads the tabs showing items of the given type.
+func buildWishlistTab(w fyne.Window, store CollectionStore, games []Game, consoles []Console, accessories []Accessory, onChanged func(itemType string)) fyne.CanvasObject {
+	items := collectWishlist(games, consoles, accessories)
+	var selected wishlistItem
+
+	// changed reloads the tabs after the selected item was edited
+	changed := func(item wishlistItem) func() {
+		return func() { onChanged(item.ItemType) }
+	}
+
+	detailsBtn := widget.NewButton("Détails", func() {
+		item := selected
+		switch item.ItemType {
+		case "game":
+			showGameDetailDialog(w, store, item.ItemID, func() {
+				showEditGameDialog(w, store, item.ItemID, changed(item))
+			}, changed(item))
+		case "console":
+			showConsoleDetailDialog(w, store, item.ItemID, func() {
+				showEditConsoleDialog(w, store, item.ItemID, changed(item))
+			}, changed(item))
+		case "accessory":
+			showAccessoryDetailDialog(w, store, item.ItemID, func() {
+				showEditAccessoryDialog(w, store, item.ItemID, changed(item))
+			}, changed(item))
+		}
+	})
+
+	editBtn := widget.NewButton("Éditer", func() {
+		item := selected
+		switch item.ItemType {
+		case "game":
+			showEditGameDialog(w, store, item.ItemID, changed(item))
+		case "console":
+			showEditConsoleDialog(w, store, item.ItemID, changed(item))
+		case "accessory":
+			showEditAccessoryDialog(w, store, item.ItemID, changed(item))
+		}
+	})
+
+	boughtBtn := widget.NewButtonWithIcon("Je l'ai acheté", theme.ConfirmIcon(), func() {
+		if selected.ItemType != "" {
+			showBoughtDialog(w, store, selected, changed(selected))
+		}
+	})
+
+	deleteBtn := widget.NewButton("Supprimer", func() {
+		if selected.ItemType == "" {
+			return
+		}
+		item := selected
+		dialog.NewConfirm(
+			"Retirer de la liste de souhaits",
+			fmt.Sprintf("Êtes-vous sûr de vouloir supprimer '%s'? La suppression peut être annulée avec Ctrl+Z.", item.Name),
+			func(confirmed bool) {
+				if !confirmed {
+					return
+				}
+				var err error
+				switch item.ItemType {
+				case "game":
+					err = store.DeleteGame(item.ItemID)
+				case "console":
+					err = store.DeleteConsole(item.ItemID)
+				case "accessory":
+					err = store.DeleteAccessory(item.ItemID)
+				}
+				if err != nil {
+					dialog.ShowError(fmt.Errorf("échec de suppression: %w", err), w)
+					return
+				}
+				onChanged(item.ItemType)
+			},
+			w,
+		).Show()
+	})
+
+	// New wanted items start with "Possédé" unchecked
+	addMenu := fyne.NewMenu("",
+		fyne.NewMenuItem("Jeu", func() {
+			showAddGameDialog(w, store, false, func() { onChanged("game") })
+		}),
+		fyne.NewMenuItem("Console", func() {
+			showAddConsoleDialog(w, store, false, func() { onChanged("console") })
+		}),
+		fyne.NewMenuItem("Accessoire", func() {
+			showAddAccessoryDialog(w, store, false, func() { onChanged("accessory") })
+		}),
+	)
+	var addBtn *widget.Button
+	addBtn = widget.NewButtonWithIcon("Ajouter", theme.ContentAddIcon(), func() {
+		position := fyne.CurrentApp().Driver().AbsolutePositionForObject(addBtn).AddXY(0, addBtn.Size().Height)
+		widget.ShowPopUpMenuAtPosition(addMenu, w.Canvas(), position)
+	})
+
+	addBtn.Importance = widget.SuccessImportance
+	detailsBtn.Importance = widget.HighImportance
+	editBtn.Importance = widget.WarningImportance
+	boughtBtn.Importance = widget.SuccessImportance
+	deleteBtn.Importance = widget.DangerImportance
+	buttons := []*widget.Button{detailsBtn, editBtn, boughtBtn, deleteBtn}
+
+	order := loadTableSort(prefWishlistSort)
+	tableContainer := container.NewStack()
+	var rebuildTable func()
+	onSort := func(column string) {
+		order = order.toggle(column)
+		order.save(prefWishlistSort)
+		rebuildTable()
+	}
+	rebuildTable = func() {
+		sorted := sortItems(items, wishlistSortColumns, order, wishlistDefaultSort)
+		tableContainer.Objects = []fyne.CanvasObject{buildWishlistTable(sorted, &selected, buttons, order, onSort)}
+		tableContainer.Refresh()
+	}
+	rebuildTable()
+
+	// Sum of the target prices, to know what completing the list would cost
+	total := 0.0
+	for _, item := range items {
+		if item.TargetPrice != nil {
+			total += *item.TargetPrice
+		}
+	}
+	summary := widget.NewLabel(fmt.Sprintf("%d élément(s) recherché(s), %.2f au total aux prix cibles", len(items), total))
+
+	toolbar := container.NewBorder(nil, nil,
+		container.NewHBox(addBtn, detailsBtn, editBtn, boughtBtn, deleteBtn),
+		nil,
+		summary,
+	)
+
+	var content fyne.CanvasObject = tableContainer
+	if len(items) == 0 {
+		content = container.NewCenter(widget.NewLabelWithStyle(
+			"La liste de souhaits est vide. Les jeux, consoles et accessoires non possédés apparaissent ici.",
+			fyne.TextAlignCenter, fyne.TextStyle{Italic: true}))
+	}
+	return container.NewBorder(toolbar, nil, nil, nil, content)
+}
+
+// ========== Purchase ==========
+
+// showBoughtDialog asks for the purchase date, price and condition of a wishlist item,
+// then moves it into the collection
+func showBoughtDialog(w fyne.Window, store CollectionStore, item wishlistItem, onDone func()) {
+	dateEntry := widget.NewEntry()
+	dateEntry.SetPlaceHolder("AAAA-MM-JJ")
+	dateEntry.SetText(time.Now().Format("2006-01-02"))
+
+	priceEntry := widget.NewEntry()
+	priceEntry.SetPlaceHolder("Prix d'achat")
+	if item.TargetPrice != nil {
+		priceEntry.SetText(fmt.Sprintf("%.2f", *item.TargetPrice))
+	}
+
+	conditionLabel := widget.NewLabel("État: -")
+	conditionSlider := widget.NewSlider(1, 5)
+	conditionSlider.Step = 1
+	conditionSet := false
+	conditionSlider.OnChanged = func(value float64) {
+		conditionSet = true
+		conditionLabel.SetText(fmt.Sprintf("État: %s", conditionToStars(intPtr(int(value)))))
+	}
+
+	dialog.ShowForm(fmt.Sprintf("Achat de « %s »", item.Name), "Ajouter à la collection", "Annuler",
+		[]*widget.FormItem{
+			widget.NewFormItem("Date d'achat", dateEntry),
+			widget.NewFormItem("Prix d'achat", priceEntry),
+			widget.NewFormItem("", conditionLabel),
+			widget.NewFormItem("État", conditionSlider),
+		},
+		func(confirmed bool) {
+			if !confirmed {
+				return
+			}
+
+			date, err := parseDateEntry(dateEntry.Text)
+			if err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			purchase := Purchase{Date: date, Price: parsePriceEntry(priceEntry.Text)}
+			if conditionSet {
+				purchase.Condition = intPtr(int(conditionSlider.Value))
+			}
+
+			if err := store.MarkAsBought(item.ItemType, item.ItemID, purchase); err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			dialog.ShowInformation("Ajouté à la collection", fmt.Sprintf("« %s » fait maintenant partie de la collection.", item.Name), w)
+			onDone()
+		}, w)
+}